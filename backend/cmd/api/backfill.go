@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/logging"
+	osrsclient "github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/osrs"
+)
+
+const backfillUsage = `usage: api backfill [flags]
+
+Loads past prices from the OSRS Wiki /timeseries endpoint into the shared
+repository (DATABASE_URL). Points already stored are skipped, so it can be
+re-run to fill gaps.
+
+Flags:
+`
+
+// backfillTimesteps are the sampling intervals offered by /timeseries
+var backfillTimesteps = map[string]bool{"5m": true, "1h": true, "6h": true, "24h": true}
+
+// runBackfill implements the "backfill" subcommand and returns the process exit code
+func runBackfill(args []string) int {
+	slog.SetDefault(logging.NewLogger(os.Stderr, slog.LevelWarn))
+
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	timestep := fs.String("timestep", "24h", "sampling interval: 5m, 1h, 6h or 24h (up to 365 points each)")
+	itemList := fs.String("items", "", "comma-separated item IDs (default: every item in the repository)")
+	pause := fs.Duration("pause", time.Second, "pause between provider requests")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), backfillUsage)
+		fs.PrintDefaults()
+	}
+	cfg, ok := parseConfig(fs, args)
+	if !ok {
+		return 2
+	}
+
+	if !backfillTimesteps[*timestep] {
+		fmt.Fprintf(os.Stderr, "invalid timestep %q (expected 5m, 1h, 6h or 24h)\n", *timestep)
+		return 2
+	}
+	itemIDs, err := parseItemList(*itemList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer store.Close()
+	if !store.shared() {
+		fmt.Fprintln(os.Stderr, "backfill needs DATABASE_URL: an in-memory repository is lost when the command exits")
+		return 2
+	}
+	if err := store.checkSchema(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	backfill := application.NewBackfillHistoryUseCase(osrsclient.NewOsrsWikiClient(cfg.OSRSWiki), store.repo)
+	summary, err := backfill.Execute(ctx, itemIDs, *timestep, *pause)
+
+	fmt.Printf("backfill summary (timestep %s)\n", *timestep)
+	fmt.Printf("  items:           %d\n", summary.Items)
+	fmt.Printf("  failed:          %d\n", summary.Failed)
+	fmt.Printf("  points fetched:  %d\n", summary.Fetched)
+	fmt.Printf("  inserted:        %d\n", summary.Inserted)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backfill stopped: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseItemList parses a comma-separated list of item IDs
+func parseItemList(list string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var ids []int
+	for _, part := range strings.Split(list, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid item ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/catalog"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/export"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/logging"
+	osrsclient "github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/osrs"
+)
+
+const exportUsage = `usage: api export <items|history> [flags]
+
+Dumps the current items or their price history as CSV or Parquet.
+
+Flags:
+`
+
+// runExport implements the "export" subcommand and returns the process exit code
+func runExport(args []string) int {
+	// Logs go to stderr so they never mix with an export written to stdout
+	slog.SetDefault(logging.NewLogger(os.Stderr, slog.LevelWarn))
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "csv", "output format: csv or parquet")
+	query := fs.String("q", "", "only export items whose name contains this text (same as /items?q=)")
+	category := fs.String("category", "", "only export items of this category (same as /items?category=)")
+	tag := fs.String("tag", "", "only export items carrying this tag (same as /items?tag=)")
+	days := fs.Int("days", 30, "history range in days (history only)")
+	out := fs.String("out", "", "output file (default: stdout)")
+	fetch := fs.Bool("fetch", true, "fetch the latest prices from the OSRS Wiki before exporting (default: only without a database)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), exportUsage)
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	kind := args[0]
+	cfg, ok := parseConfig(fs, args[1:])
+	if !ok {
+		return 2
+	}
+	// A shared database is kept fresh by the worker
+	if !flagSet(fs, "fetch") {
+		*fetch = cfg.Database.URL == ""
+	}
+	if kind != "items" && kind != "history" {
+		fmt.Fprintf(os.Stderr, "unknown export %q\n", kind)
+		fs.Usage()
+		return 2
+	}
+	if *days < 1 {
+		fmt.Fprintln(os.Stderr, "days must be positive")
+		return 2
+	}
+	if *category != "" && !domain.Category(*category).Valid() {
+		names := make([]string, len(domain.Categories))
+		for i, c := range domain.Categories {
+			names[i] = string(c)
+		}
+		fmt.Fprintf(os.Stderr, "invalid category %q (expected one of %s)\n", *category, strings.Join(names, ", "))
+		return 2
+	}
+	filter := domain.ItemFilter{Query: *query, Category: domain.Category(*category), Tag: strings.TrimSpace(*tag)}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer store.Close()
+	repo := store.repo
+
+	if *fetch {
+		itemCatalog, err := catalog.Load()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		updatePricesUseCase := application.NewUpdatePricesUseCase(osrsclient.NewOsrsWikiClient(cfg.OSRSWiki), repo, itemCatalog)
+		if err := updatePricesUseCase.Execute(ctx); err != nil {
+			slog.Warn("price update failed, exporting existing data", "error", err)
+		}
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		output, err = os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer output.Close()
+	}
+	buffered := bufio.NewWriter(output)
+
+	if kind == "items" {
+		err = exportItems(ctx, repo, format, buffered, filter)
+	} else {
+		err = exportHistory(ctx, repo, format, buffered, filter, *days)
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// exportItems writes every item matching filter
+func exportItems(ctx context.Context, repo domain.ItemRepository, format export.Format, w *bufio.Writer, filter domain.ItemFilter) error {
+	writer, err := export.NewItemWriter(format, w)
+	if err != nil {
+		return err
+	}
+	if err := application.NewExportItemsUseCase(repo).Execute(ctx, filter, writer.WriteItem); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// exportHistory writes the price history of every item matching filter
+func exportHistory(ctx context.Context, repo domain.ItemRepository, format export.Format, w *bufio.Writer, filter domain.ItemFilter, days int) error {
+	writer, err := export.NewHistoryWriter(format, w)
+	if err != nil {
+		return err
+	}
+	if err := application.NewExportPriceHistoryUseCase(repo).Execute(ctx, filter, days, writer.WriteHistory); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/importer"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/logging"
+)
+
+const importUsage = `usage: api import [flags] <file|->
+
+Validates a CSV or JSON price history dump, de-duplicates it by
+(item_id, timestamp) and loads it into the repository.
+
+CSV files need item_id, price and date (or timestamp) columns.
+JSON files hold an array of {"item_id", "price", "date"} objects or one object per line.
+
+Rows are stored in the database at DATABASE_URL. Without it the repository
+is in memory, so this command only checks that the load succeeds; to load a
+dump into a running server, POST it to /admin/import/history (requires ADMIN_TOKEN).
+
+Flags:
+`
+
+// runImport implements the "import" subcommand and returns the process exit code
+func runImport(args []string) int {
+	slog.SetDefault(logging.NewLogger(os.Stderr, slog.LevelWarn))
+
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatName := fs.String("format", "", "input format: csv or json (default: from the file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate and summarize without storing anything")
+	showRejections := fs.Int("show-rejections", 20, "number of rejected rows to print")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), importUsage)
+		fs.PrintDefaults()
+	}
+
+	cfg, ok := parseConfig(fs, args)
+	if !ok {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	name := *formatName
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format, err := importer.ParseFormat(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		input = file
+	}
+
+	source, err := importer.NewHistorySource(format, input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer store.Close()
+	if err := store.checkSchema(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	summary, err := application.NewImportPriceHistoryUseCase(store.repo).Execute(ctx, source, *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed after %d rows (%d accepted, %d inserted): %v\n", summary.Rows, summary.Accepted, summary.Inserted, err)
+		return 1
+	}
+
+	mode := "import"
+	if summary.DryRun {
+		mode = "dry run"
+	}
+	fmt.Printf("%s summary for %s\n", mode, path)
+	fmt.Printf("  rows read:       %d\n", summary.Rows)
+	fmt.Printf("  accepted:        %d\n", summary.Accepted)
+	fmt.Printf("  duplicates:      %d\n", summary.Duplicates)
+	fmt.Printf("  rejected:        %d\n", summary.Rejected)
+	if !summary.DryRun {
+		fmt.Printf("  inserted:        %d\n", summary.Inserted)
+	}
+
+	if summary.Rejected > 0 && *showRejections > 0 {
+		fmt.Println("rejected rows:")
+		for i, rejection := range summary.Rejections {
+			if i >= *showRejections {
+				fmt.Printf("  ... and %d more\n", summary.Rejected-i)
+				break
+			}
+			fmt.Printf("  line %d: %s\n", rejection.Line, rejection.Reason)
+		}
+	}
+
+	return 0
+}
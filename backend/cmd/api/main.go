@@ -1,102 +1,48 @@
 package main
 
 import (
-	"context"
 	"fmt"
-	"log"
-	"net/http"
 	"os"
-	"os/signal"
-	"strconv"
-	"syscall"
-	"time"
-
-	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
-	osrsclient "github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/osrs"
-	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/repository"
-	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/worker"
-	httpInterface "github.com/gabv/osrs-good-to-flip/backend/internal/interfaces/http"
-	"github.com/gabv/osrs-good-to-flip/backend/internal/interfaces/http/handlers"
+	"strings"
 )
 
-func main() {
-	// Initialize infrastructure
-	repo := repository.NewInMemoryRepository()
-	osrsClient := osrsclient.NewOsrsWikiClient()
-
-	// Initialize use cases
-	getItemUseCase := application.NewGetItemUseCase(repo)
-	searchItemsUseCase := application.NewSearchItemsUseCase(repo)
-	updatePricesUseCase := application.NewUpdatePricesUseCase(osrsClient, repo)
-	getPriceHistoryUseCase := application.NewGetPriceHistoryUseCase(repo)
-
-	// Initialize handlers
-	itemsHandler := handlers.NewItemsHandler(getItemUseCase, searchItemsUseCase, getPriceHistoryUseCase)
-	healthHandler := handlers.NewHealthHandler()
-
-	// Setup routes
-	router := httpInterface.SetupRoutes(itemsHandler, healthHandler)
-
-	// Create HTTP server
-	port := getPort()
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%s", port),
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	// Start server in a goroutine
-	go func() {
-		log.Printf("Server starting on port %s", port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
-		}
-	}()
-
-	// Run initial price update
-	ctx := context.Background()
-	if err := updatePricesUseCase.Execute(ctx); err != nil {
-		log.Printf("Warning: Failed to update prices: %v", err)
-	}
-
-	// Start price updater worker (updates every 5 minutes)
-	updateInterval := 5 * time.Minute
-	if envInterval := os.Getenv("PRICE_UPDATE_INTERVAL_MIN"); envInterval != "" {
-		if minutes, err := strconv.Atoi(envInterval); err == nil && minutes > 0 {
-			updateInterval = time.Duration(minutes) * time.Minute
-		}
-	}
-
-	priceWorker := worker.NewPriceUpdaterWorker(updatePricesUseCase, updateInterval)
-	priceWorker.Start()
-
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+const usage = `usage: api [command] [flags]
 
-	log.Println("Shutting down server...")
+Commands:
+  serve      run the HTTP API (default)
+  worker     run only the price updater
+  migrate    apply database migrations
+  backfill   load past prices from the OSRS Wiki
+  export     dump items or price history as CSV or Parquet
+  import     load price history from CSV or JSON dumps
 
-	// Stop price worker first
-	priceWorker.Stop()
+Run "api <command> -h" for command flags.
+`
 
-	// Shutdown HTTP server
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+func main() {
+	// Without a command (or with only flags) the API server runs, as before
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runServe(os.Args[1:]))
 	}
 
-	log.Println("Server exited")
-}
-
-func getPort() string {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "serve":
+		os.Exit(runServe(args))
+	case "worker":
+		os.Exit(runWorker(args))
+	case "migrate":
+		os.Exit(runMigrate(args))
+	case "backfill":
+		os.Exit(runBackfill(args))
+	case "export":
+		os.Exit(runExport(args))
+	case "import":
+		os.Exit(runImport(args))
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
 	}
-	return port
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const migrateUsage = `usage: api migrate [flags]
+
+Applies pending schema migrations to the database at DATABASE_URL.
+Safe to run from several processes at once.
+
+Flags:
+`
+
+// runMigrate implements the "migrate" subcommand and returns the process exit code
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	status := fs.Bool("status", false, "list pending migrations without applying them")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), migrateUsage)
+		fs.PrintDefaults()
+	}
+	cfg, ok := parseConfig(fs, args)
+	if !ok {
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer store.Close()
+	if !store.shared() {
+		fmt.Fprintln(os.Stderr, "DATABASE_URL is not set: the in-memory repository has no schema to migrate")
+		return 2
+	}
+
+	if *status {
+		pending, err := store.postgres.PendingMigrations(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(pending) == 0 {
+			fmt.Println("schema is up to date")
+			return 0
+		}
+		for _, version := range pending {
+			fmt.Printf("pending: %s\n", version)
+		}
+		return 0
+	}
+
+	applied, err := store.postgres.Migrate(ctx)
+	for _, version := range applied {
+		fmt.Printf("applied: %s\n", version)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(applied) == 0 {
+		fmt.Println("schema is up to date")
+	}
+	return 0
+}
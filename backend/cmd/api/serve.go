@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/auth"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/cache"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/catalog"
+	osrsclient "github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/osrs"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/repository"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/tracing"
+	httpInterface "github.com/gabv/osrs-good-to-flip/backend/internal/interfaces/http"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/interfaces/http/handlers"
+)
+
+// apiKeyCacheTTL is how long API key lookups are cached
+const apiKeyCacheTTL = time.Minute
+
+const serveUsage = `usage: api serve [flags]
+
+Runs the HTTP API. By default the price updater runs in the same process;
+with DATABASE_URL set, replicas elect a single leader to run it.
+
+Flags:
+`
+
+// runServe implements the "serve" subcommand and returns the process exit code
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), serveUsage)
+		fs.PrintDefaults()
+	}
+	reloader, configFile, ok := parseReloadableConfig(fs, args)
+	if !ok {
+		return 2
+	}
+	cfg := reloader.Current()
+
+	// Initialize structured logging
+	setupLogging(cfg)
+	handlers.SetProduction(cfg.IsProduction())
+
+	// Initialize tracing (none, stdout or otlp)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName, cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize infrastructure
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to open repository", "error", err)
+		return 1
+	}
+	defer store.Close()
+	if err := store.checkSchema(ctx); err != nil {
+		slog.Error("repository not ready", "error", err)
+		return 1
+	}
+
+	mode := resolveWorkerMode(cfg.Worker.Mode, store.shared())
+	if mode == workerOff && !store.shared() {
+		slog.Warn("price updater disabled with an in-memory repository; prices will never update")
+	}
+
+	itemCatalog, err := catalog.Load()
+	if err != nil {
+		slog.Error("failed to load item categories", "error", err)
+		return 1
+	}
+
+	repo := store.repo
+	osrsClient := osrsclient.NewOsrsWikiClient(cfg.OSRSWiki)
+
+	// Initialize use cases
+	getItemUseCase := application.NewGetItemUseCase(repo)
+	searchItemsUseCase := application.NewSearchItemsUseCase(repo)
+	updatePricesUseCase := application.NewUpdatePricesUseCase(osrsClient, repo, itemCatalog)
+	getPriceHistoryUseCase := application.NewGetPriceHistoryUseCase(repo)
+	getDataVersionUseCase := application.NewGetDataVersionUseCase(repo)
+	exportItemsUseCase := application.NewExportItemsUseCase(repo)
+	exportPriceHistoryUseCase := application.NewExportPriceHistoryUseCase(repo)
+	importPriceHistoryUseCase := application.NewImportPriceHistoryUseCase(repo)
+	findFlipsUseCase := application.NewFindFlipsUseCase(repo)
+	getItemsBatchUseCase := application.NewGetItemsBatchUseCase(repo)
+
+	// Key lookups run on every authenticated request; revocations reach
+	// other replicas once their cached entries expire
+	apiKeys := repository.NewCachedAPIKeyRepository(store.apiKeys, apiKeyCacheTTL)
+	defer apiKeys.Stop()
+	authenticateAPIKeyUseCase := application.NewAuthenticateAPIKeyUseCase(apiKeys)
+
+	// User sessions
+	jwtSecret, err := accessTokenSecret(cfg.Auth)
+	if err != nil {
+		slog.Error("failed to generate access token secret", "error", err)
+		return 1
+	}
+	accessTokens := auth.NewJWTIssuer(jwtSecret, cfg.Auth.AccessTokenTTL.Std())
+	passwords := auth.NewBcryptHasher(0)
+	refreshTTL := cfg.Auth.RefreshTokenTTL.Std()
+
+	updateInterval := cfg.Worker.UpdateInterval.Std()
+
+	// Updater and names cache status are only known when updates always run here;
+	// otherwise readiness judges freshness from the shared repository
+	var updates application.UpdateStatusReporter
+	var namesCache application.NamesCacheReporter
+	if mode == workerEmbedded {
+		updates = updatePricesUseCase
+		namesCache = osrsClient
+	}
+	checkHealthUseCase := application.NewCheckHealthUseCase(repo, updates, namesCache, updateInterval)
+
+	// Cache GET /items, /items/{id}/history and /flips responses until the next price update
+	responseCache := cache.NewMemoryCache[string, httpInterface.CachedResponse]("responses", 1000, updateInterval)
+	updatePricesUseCase.OnUpdate(func(ctx context.Context) {
+		responseCache.Clear()
+	})
+
+	// Initialize handlers
+	itemsHandler := handlers.NewItemsHandler(getItemUseCase, searchItemsUseCase, getPriceHistoryUseCase, exportItemsUseCase, getItemsBatchUseCase, application.NewForecastPriceUseCase(repo))
+	healthHandler := handlers.NewHealthHandler(checkHealthUseCase)
+	conditionalHandler := handlers.NewConditionalHandler(getDataVersionUseCase, updateInterval)
+	exportHandler := handlers.NewExportHandler(exportItemsUseCase, exportPriceHistoryUseCase)
+	importHandler := handlers.NewImportHandler(importPriceHistoryUseCase)
+	getBuyLimitsUseCase := application.NewGetBuyLimitsUseCase(store.trades, repo)
+	flipsHandler := handlers.NewFlipsHandler(findFlipsUseCase, getBuyLimitsUseCase, application.NewOptimizeAllocationUseCase(repo))
+	configHandler := handlers.NewConfigHandler(reloader)
+	authHandler := handlers.NewAuthHandler(
+		application.NewRegisterUserUseCase(store.users, passwords),
+		application.NewLoginUserUseCase(store.users, passwords, accessTokens, refreshTTL),
+		application.NewRefreshSessionUseCase(store.users, accessTokens, refreshTTL),
+		application.NewLogoutUserUseCase(store.users),
+		application.NewGetUserUseCase(store.users),
+		accessTokens,
+	)
+	watchlistsHandler := handlers.NewWatchlistsHandler(
+		application.NewCreateWatchlistUseCase(store.watchlists),
+		application.NewListWatchlistsUseCase(store.watchlists),
+		application.NewGetWatchlistUseCase(store.watchlists),
+		application.NewRenameWatchlistUseCase(store.watchlists),
+		application.NewDeleteWatchlistUseCase(store.watchlists),
+		application.NewAddWatchlistItemsUseCase(store.watchlists),
+		application.NewRemoveWatchlistItemUseCase(store.watchlists),
+		application.NewReorderWatchlistUseCase(store.watchlists),
+		application.NewGetWatchlistItemsUseCase(store.watchlists, repo),
+	)
+	tradesHandler := handlers.NewTradesHandler(
+		application.NewLogTradesUseCase(store.trades),
+		application.NewListTradesUseCase(store.trades),
+		application.NewDeleteTradeUseCase(store.trades),
+		application.NewGetTradeReportUseCase(store.trades, repo),
+	)
+	limitsHandler := handlers.NewLimitsHandler(getBuyLimitsUseCase)
+	portfolioHandler := handlers.NewPortfolioHandler(application.NewValuePortfolioUseCase(repo))
+	backtestHandler := handlers.NewBacktestHandler(application.NewRunBacktestUseCase(repo))
+	anomaliesHandler := handlers.NewAnomaliesHandler(application.NewListAnomaliesUseCase(repo))
+	categoriesHandler := handlers.NewCategoriesHandler(application.NewSummarizeCategoriesUseCase(repo))
+	apiKeysHandler := handlers.NewAPIKeysHandler(
+		application.NewCreateAPIKeyUseCase(apiKeys),
+		application.NewListAPIKeysUseCase(apiKeys),
+		application.NewGetAPIKeyUsageUseCase(apiKeys),
+		application.NewUpdateAPIKeyUseCase(apiKeys),
+		application.NewRevokeAPIKeyUseCase(apiKeys),
+	)
+
+	// Apply runtime settings on SIGHUP or config file changes; the rate limit
+	// and CORS origins are read from the reloader on each request
+	reloader.OnReload(func(cfg *config.Config) {
+		updateInterval := cfg.Worker.UpdateInterval.Std()
+		responseCache.SetDefaultTTL(updateInterval)
+		conditionalHandler.SetUpdateInterval(updateInterval)
+		checkHealthUseCase.SetUpdateInterval(updateInterval)
+		osrsClient.SetCacheTTLs(cfg.OSRSWiki.CacheTTL.Std(), cfg.OSRSWiki.NamesCacheTTL.Std())
+	})
+	watchConfig(ctx, reloader, configFile)
+
+	// Setup routes
+	router := httpInterface.SetupRoutes(reloader, itemsHandler, healthHandler, conditionalHandler, exportHandler, importHandler, flipsHandler, configHandler, apiKeysHandler, authHandler, watchlistsHandler, tradesHandler, limitsHandler, portfolioHandler, backtestHandler, anomaliesHandler, categoriesHandler, authenticateAPIKeyUseCase, responseCache)
+
+	// Create HTTP server
+	port := cfg.Server.Port
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      router,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// Start server in a goroutine
+	go func() {
+		slog.Info("server starting", "port", port, "env", cfg.Env, "worker_mode", string(mode), "shared_repository", store.shared())
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("server failed to start", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	// Start price updater worker
+	updaterDone := make(chan struct{})
+	go func() {
+		defer close(updaterDone)
+		if mode != workerOff {
+			runPriceUpdater(ctx, newElector(mode, store), updatePricesUseCase, reloader)
+		}
+	}()
+
+	// Graceful shutdown
+	<-ctx.Done()
+	slog.Info("shutting down server")
+
+	// Stop price worker first
+	<-updaterDone
+	responseCache.Stop()
+
+	// Shutdown HTTP server
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("server forced to shutdown", "error", err)
+		return 1
+	}
+
+	// Flush pending spans
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
+	}
+
+	slog.Info("server exited")
+	return 0
+}
+
+// accessTokenSecret returns the configured JWT secret or, when none is set,
+// a random one. Validation requires a secret in production; elsewhere a
+// random secret only logs every user out on restart.
+func accessTokenSecret(cfg config.AuthConfig) ([]byte, error) {
+	if cfg.JWTSecret != "" {
+		return []byte(cfg.JWTSecret), nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	slog.Warn("JWT_SECRET not set; using a random secret, so sessions end on restart and are not shared between replicas")
+	return secret, nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/leader"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/logging"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/repository"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/worker"
+)
+
+// priceUpdaterLockKey is the advisory lock held by the process running the price updater
+const priceUpdaterLockKey int64 = 0x6f73727366000002
+
+// leaderRetryInterval is how often followers try to take over and the leader checks its lock
+const leaderRetryInterval = 15 * time.Second
+
+// configPollInterval is how often the config file is checked for changes
+const configPollInterval = 5 * time.Second
+
+// logLevel is the level of the default logger; config reloads change it in place
+var logLevel slog.LevelVar
+
+// parseConfig parses the command line and loads the configuration, printing
+// any error. ok is false when the command must exit with status 2.
+func parseConfig(fs *flag.FlagSet, args []string) (cfg *config.Config, ok bool) {
+	flags := config.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		// The flag package already printed the problem and usage
+		return nil, false
+	}
+
+	cfg, err := flags.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
+	}
+	return cfg, true
+}
+
+// parseReloadableConfig is parseConfig for long-running commands: the
+// returned reloader loads the same sources again on SIGHUP or when the
+// config file changes (see watchConfig)
+func parseReloadableConfig(fs *flag.FlagSet, args []string) (reloader *config.Reloader, file string, ok bool) {
+	flags := config.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, "", false
+	}
+
+	cfg, err := flags.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, "", false
+	}
+	return config.NewReloader(cfg, flags.Load), flags.File(), true
+}
+
+// watchConfig reloads the configuration on SIGHUP and, when there is a
+// config file, whenever it changes, until ctx is cancelled
+func watchConfig(ctx context.Context, reloader *config.Reloader, file string) {
+	reloader.OnReload(applyLogLevel)
+	go reloader.WatchSignals(ctx)
+	if file != "" {
+		go reloader.WatchFile(ctx, file, configPollInterval)
+	}
+}
+
+// flagSet reports whether the named flag was given on the command line
+func flagSet(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
+// setupLogging installs the JSON logger at the configured level
+func setupLogging(cfg *config.Config) {
+	applyLogLevel(cfg)
+	slog.SetDefault(logging.NewLogger(os.Stdout, &logLevel))
+}
+
+// applyLogLevel sets the level of the default logger
+func applyLogLevel(cfg *config.Config) {
+	// The level was validated with the rest of the configuration
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	logLevel.Set(level)
+}
+
+// storage is the repository selected by the database URL: PostgreSQL when
+// set, otherwise in memory with mock data
+type storage struct {
+	repo       domain.ItemRepository
+	apiKeys    domain.APIKeyRepository
+	users      domain.UserRepository
+	watchlists domain.WatchlistRepository
+	trades     domain.TradeRepository
+	postgres   *repository.PostgresRepository // nil when in memory
+}
+
+// openStorage connects to the configured repository
+func openStorage(ctx context.Context, cfg *config.Config) (*storage, error) {
+	databaseURL := cfg.Database.URL
+	if databaseURL == "" {
+		return &storage{
+			repo:       repository.NewInstrumentedRepository(repository.NewInMemoryRepository()),
+			apiKeys:    repository.NewInstrumentedAPIKeyRepository(repository.NewInMemoryAPIKeyRepository()),
+			users:      repository.NewInstrumentedUserRepository(repository.NewInMemoryUserRepository()),
+			watchlists: repository.NewInstrumentedWatchlistRepository(repository.NewInMemoryWatchlistRepository()),
+			trades:     repository.NewInstrumentedTradeRepository(repository.NewInMemoryTradeRepository()),
+		}, nil
+	}
+
+	postgres, err := repository.NewPostgresRepository(ctx, databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &storage{
+		repo:       repository.NewInstrumentedRepository(postgres),
+		apiKeys:    repository.NewInstrumentedAPIKeyRepository(repository.NewPostgresAPIKeyRepository(postgres.Pool())),
+		users:      repository.NewInstrumentedUserRepository(repository.NewPostgresUserRepository(postgres.Pool())),
+		watchlists: repository.NewInstrumentedWatchlistRepository(repository.NewPostgresWatchlistRepository(postgres.Pool())),
+		trades:     repository.NewInstrumentedTradeRepository(repository.NewPostgresTradeRepository(postgres.Pool())),
+		postgres:   postgres,
+	}, nil
+}
+
+// shared reports whether other processes can see this repository
+func (s *storage) shared() bool {
+	return s.postgres != nil
+}
+
+// checkSchema fails when the database has migrations that were not applied
+func (s *storage) checkSchema(ctx context.Context) error {
+	if s.postgres == nil {
+		return nil
+	}
+	pending, err := s.postgres.PendingMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check database schema: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is out of date (%d pending migrations); run \"api migrate\"", len(pending))
+	}
+	return nil
+}
+
+// Close releases the database connections
+func (s *storage) Close() {
+	if s.postgres != nil {
+		s.postgres.Close()
+	}
+}
+
+// workerMode selects where the price updater runs
+type workerMode string
+
+const (
+	// workerEmbedded always runs the updater in this process (single-worker mode)
+	workerEmbedded workerMode = "embedded"
+	// workerLeader runs the updater only while this process holds the leader lock
+	workerLeader workerMode = "leader"
+	// workerOff never runs the updater; a separate worker keeps prices fresh
+	workerOff workerMode = "off"
+)
+
+// resolveWorkerMode returns the configured worker mode (already validated).
+// An empty mode defaults to leader election with a shared repository and to
+// embedded otherwise.
+func resolveWorkerMode(name string, shared bool) workerMode {
+	if name == "" {
+		if shared {
+			return workerLeader
+		}
+		return workerEmbedded
+	}
+	return workerMode(name)
+}
+
+// newElector returns the elector for mode. Without a shared repository
+// there is nobody to compete with, so the process is always the leader.
+func newElector(mode workerMode, store *storage) leader.Elector {
+	if mode == workerLeader && store.shared() {
+		return leader.NewPostgresElector(store.postgres.Pool(), priceUpdaterLockKey, leaderRetryInterval)
+	}
+	return leader.Single{}
+}
+
+// runPriceUpdater runs the periodic price updater whenever elector grants
+// leadership, until ctx is cancelled. Config reloads change the interval of
+// the running worker.
+func runPriceUpdater(ctx context.Context, elector leader.Elector, updatePricesUseCase *application.UpdatePricesUseCase, reloader *config.Reloader) {
+	var mu sync.Mutex
+	var active *worker.PriceUpdaterWorker
+	reloader.OnReload(func(cfg *config.Config) {
+		mu.Lock()
+		defer mu.Unlock()
+		if active != nil {
+			active.SetInterval(cfg.Worker.UpdateInterval.Std())
+		}
+	})
+
+	elector.Run(ctx, func(ctx context.Context) {
+		mu.Lock()
+		active = worker.NewPriceUpdaterWorker(updatePricesUseCase, reloader.Current().Worker.UpdateInterval.Std())
+		active.Start()
+		mu.Unlock()
+
+		<-ctx.Done()
+
+		mu.Lock()
+		active.Stop()
+		active = nil
+		mu.Unlock()
+	})
+}
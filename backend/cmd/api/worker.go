@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/catalog"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
+	osrsclient "github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/osrs"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/tracing"
+)
+
+const workerUsage = `usage: api worker [flags]
+
+Runs only the price updater against the shared repository (DATABASE_URL),
+so API replicas can run "api serve -worker-mode off". Several workers may
+run at once; they elect a single leader unless -worker-mode embedded is set.
+
+Flags:
+`
+
+// runWorker implements the "worker" subcommand and returns the process exit code
+func runWorker(args []string) int {
+	fs := flag.NewFlagSet("worker", flag.ContinueOnError)
+	metricsAddr := fs.String("metrics-addr", "", "address serving /metrics, e.g. :9090 (default: disabled)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), workerUsage)
+		fs.PrintDefaults()
+	}
+	reloader, configFile, ok := parseReloadableConfig(fs, args)
+	if !ok {
+		return 2
+	}
+	cfg := reloader.Current()
+
+	// leader (elect one worker, the default) or embedded (single worker, no election)
+	mode := resolveWorkerMode(cfg.Worker.Mode, true)
+	if mode == workerOff {
+		fmt.Fprintln(os.Stderr, "worker mode off makes no sense for the worker command (expected leader or embedded)")
+		return 2
+	}
+
+	setupLogging(cfg)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName+"-worker", cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to open repository", "error", err)
+		return 1
+	}
+	defer store.Close()
+	if !store.shared() {
+		slog.Error("the worker needs a database (DATABASE_URL): an in-memory repository is not shared with the API")
+		return 2
+	}
+	if err := store.checkSchema(ctx); err != nil {
+		slog.Error("repository not ready", "error", err)
+		return 1
+	}
+
+	var metricsServer *http.Server
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{Addr: *metricsAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("metrics server failed", "error", err)
+			}
+		}()
+	}
+
+	itemCatalog, err := catalog.Load()
+	if err != nil {
+		slog.Error("failed to load item categories", "error", err)
+		return 1
+	}
+
+	osrsClient := osrsclient.NewOsrsWikiClient(cfg.OSRSWiki)
+	updatePricesUseCase := application.NewUpdatePricesUseCase(osrsClient, store.repo, itemCatalog)
+
+	reloader.OnReload(func(cfg *config.Config) {
+		osrsClient.SetCacheTTLs(cfg.OSRSWiki.CacheTTL.Std(), cfg.OSRSWiki.NamesCacheTTL.Std())
+	})
+	watchConfig(ctx, reloader, configFile)
+
+	slog.Info("worker starting", "worker_mode", string(mode), "interval", cfg.Worker.UpdateInterval.Std().String())
+	runPriceUpdater(ctx, newElector(mode, store), updatePricesUseCase, reloader)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if metricsServer != nil {
+		metricsServer.Shutdown(shutdownCtx)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
+	}
+
+	slog.Info("worker exited")
+	return 0
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/backtest"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// newFlagSet creates a subcommand flag set with a usage line
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: osrsflip %s %s\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses flags and checks the number of positional arguments.
+// Flags may come before or after the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < minArgs || len(positional) > maxArgs {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+func runSearch(ctx context.Context, app *cli, args []string) error {
+	fs := newFlagSet("search", "[flags] <query>")
+	limit := fs.Int("limit", 20, "maximum number of results (1-100)")
+	category := fs.String("category", "", "only items of this category")
+	tag := fs.String("tag", "", "only items carrying this tag")
+	positional, err := parseArgs(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	if *limit < 1 || *limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+	if err := validateCategory(*category); err != nil {
+		return err
+	}
+
+	filter := domain.ItemFilter{Category: domain.Category(*category), Tag: *tag}
+	if len(positional) == 1 {
+		filter.Query = positional[0]
+	}
+
+	items, err := app.src.Search(ctx, filter, *limit)
+	if err != nil {
+		return err
+	}
+	return app.out.items(items)
+}
+
+func runItem(ctx context.Context, app *cli, args []string) error {
+	fs := newFlagSet("item", "<id|name>")
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	item, err := resolveItem(ctx, app.src, positional[0])
+	if err != nil {
+		return err
+	}
+	return app.out.item(item)
+}
+
+func runHistory(ctx context.Context, app *cli, args []string) error {
+	fs := newFlagSet("history", "[flags] <id>")
+	days := fs.Int("days", 30, "number of days (1-30)")
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *days < 1 || *days > 30 {
+		return fmt.Errorf("days must be between 1 and 30")
+	}
+
+	id, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid item ID %q", positional[0])
+	}
+
+	history, err := app.src.History(ctx, id, *days)
+	if err != nil {
+		return err
+	}
+	return app.out.history(history)
+}
+
+func runFlips(ctx context.Context, app *cli, args []string) error {
+	fs := newFlagSet("flips", "[flags]")
+	minMargin := fs.Float64("min-margin", 0, "minimum margin percentage")
+	minVolume := fs.Int("min-volume", 0, "minimum trading volume")
+	sortBy := fs.String("sort", string(domain.FlipSortProfit), "ranking: profit, margin or volume")
+	limit := fs.Int("limit", 20, "maximum number of results (1-100)")
+	excludeAnomalies := fs.Bool("exclude-anomalies", false, "skip items flagged for suspicious price movements")
+	category := fs.String("category", "", "only items of this category")
+	tag := fs.String("tag", "", "only items carrying this tag")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := validateCategory(*category); err != nil {
+		return err
+	}
+
+	switch domain.FlipSort(*sortBy) {
+	case domain.FlipSortProfit, domain.FlipSortMargin, domain.FlipSortVolume:
+	default:
+		return fmt.Errorf("invalid sort %q (expected profit, margin or volume)", *sortBy)
+	}
+	if *limit < 1 || *limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+
+	flips, err := app.src.Flips(ctx, domain.FlipFilter{
+		MinMargin:        *minMargin,
+		MinVolume:        *minVolume,
+		SortBy:           domain.FlipSort(*sortBy),
+		Limit:            *limit,
+		ExcludeAnomalies: *excludeAnomalies,
+		Category:         domain.Category(*category),
+		Tag:              *tag,
+	})
+	if err != nil {
+		return err
+	}
+	return app.out.flips(flips)
+}
+
+// validateCategory checks a -category flag; empty means every category
+func validateCategory(category string) error {
+	if category == "" || domain.Category(category).Valid() {
+		return nil
+	}
+	names := make([]string, len(domain.Categories))
+	for i, c := range domain.Categories {
+		names[i] = string(c)
+	}
+	return fmt.Errorf("invalid category %q (expected one of %s)", category, strings.Join(names, ", "))
+}
+
+// runBacktest replays price history through a built-in strategy. Without
+// item IDs the API picks the most traded items.
+func runBacktest(ctx context.Context, app *cli, args []string) error {
+	fs := newFlagSet("backtest", "[flags] [id ...]")
+	strategy := fs.String("strategy", backtest.StrategyMargin, "strategy: margin or mean_reversion")
+	lookback := fs.Int("lookback", 0, "bars in the strategy's rolling window (default 7)")
+	minMargin := fs.Float64("min-margin", 0, "margin strategy: minimum post-tax margin percentage")
+	threshold := fs.Float64("threshold", 0, "mean_reversion strategy: percentage below the mean to bid")
+	maxShare := fs.Float64("max-share", 0, "most of the cash one offer may take, in percent (default even split over the slots)")
+	cash := fs.Int("cash", 10_000_000, "starting cash")
+	slots := fs.Int("slots", domain.GESlots, "GE slots")
+	days := fs.Int("days", 30, "days of history to replay (1-365)")
+	minVolume := fs.Int("min-volume", 0, "minimum volume of the items picked when no IDs are given")
+	fillRatio := fs.Float64("fill-ratio", 1, "share of an offer filled per bar reaching its price (0-1]")
+	orderTTL := fs.Int("order-ttl", 1, "bars an offer stays open before it is canceled")
+	positional, err := parseArgs(fs, args, 0, application.MaxBacktestItems)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(positional))
+	for _, arg := range positional {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid item ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	if *days < 1 || *days > 365 {
+		return fmt.Errorf("days must be between 1 and 365")
+	}
+	if *cash < 1 {
+		return fmt.Errorf("cash must be positive")
+	}
+
+	report, err := app.src.Backtest(ctx, application.BacktestRequest{
+		Strategy: backtest.StrategyConfig{
+			Name:      *strategy,
+			Lookback:  *lookback,
+			MinMargin: *minMargin,
+			Threshold: *threshold,
+			MaxShare:  *maxShare,
+		},
+		ItemIDs:   ids,
+		MinVolume: *minVolume,
+		Days:      *days,
+		Config: backtest.Config{
+			Cash:      *cash,
+			Slots:     *slots,
+			FillRatio: *fillRatio,
+			OrderTTL:  *orderTTL,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return app.out.backtest(report)
+}
+
+// runWatch polls an item and prints a line every time its prices change,
+// until interrupted
+func runWatch(ctx context.Context, app *cli, args []string) error {
+	fs := newFlagSet("watch", "[flags] <id|name>")
+	interval := fs.Duration("interval", 30*time.Second, "polling interval")
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+	if src, ok := app.src.(*providerSource); ok {
+		src.maxAge = *interval
+	}
+
+	item, err := resolveItem(ctx, app.src, positional[0])
+	if err != nil {
+		return err
+	}
+	id := item.ItemID
+
+	w := newWatchWriter(app.out)
+	if app.out.format == formatTable {
+		fmt.Fprintf(os.Stderr, "watching %s (%d) every %s, Ctrl+C to stop\n", item.Name, id, *interval)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	var last *domain.ItemPrice
+	for {
+		if last == nil || item.High != last.High || item.Low != last.Low || item.Price != last.Price {
+			if err := w.write(item, last); err != nil {
+				return err
+			}
+			last = item
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		next, err := app.src.Item(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// Keep watching through transient failures
+			fmt.Fprintln(os.Stderr, "warning:", err)
+			continue
+		}
+		item = next
+	}
+}
+
+// watchWriter prints one line per price change: aligned columns for tables,
+// one JSON object per line, or CSV rows under a single header
+type watchWriter struct {
+	p   *printer
+	csv *csv.Writer
+	enc *json.Encoder
+}
+
+func newWatchWriter(p *printer) *watchWriter {
+	w := &watchWriter{p: p}
+	switch p.format {
+	case formatCSV:
+		w.csv = csv.NewWriter(p.w)
+	case formatJSON:
+		w.enc = json.NewEncoder(p.w)
+	}
+	return w
+}
+
+func (w *watchWriter) write(item, prev *domain.ItemPrice) error {
+	change := 0
+	if prev != nil {
+		change = item.Price - prev.Price
+	}
+	now := time.Now()
+
+	switch w.p.format {
+	case formatJSON:
+		return w.enc.Encode(item)
+	case formatCSV:
+		if prev == nil {
+			w.csv.Write([]string{"time", "item_id", "price", "high", "low", "volume", "change", "trend"})
+		}
+		w.csv.Write([]string{
+			now.Format(time.RFC3339),
+			strconv.Itoa(item.ItemID),
+			strconv.Itoa(item.Price),
+			strconv.Itoa(item.High),
+			strconv.Itoa(item.Low),
+			strconv.Itoa(item.Volume),
+			strconv.Itoa(change),
+			string(item.Trend),
+		})
+		w.csv.Flush()
+		return w.csv.Error()
+	default:
+		if prev == nil {
+			fmt.Fprintf(w.p.w, "%-8s  %14s  %14s  %14s  %12s  %s\n", "TIME", "PRICE", "HIGH", "LOW", "CHANGE", "TREND")
+		}
+		delta := w.p.gp(change)
+		if change > 0 {
+			delta = "+" + delta
+		}
+		_, err := fmt.Fprintf(w.p.w, "%-8s  %14s  %14s  %14s  %12s  %s\n",
+			now.Format("15:04:05"),
+			w.p.gp(item.Price),
+			w.p.gp(item.High),
+			w.p.gp(item.Low),
+			delta,
+			w.p.trend(item.Trend),
+		)
+		return err
+	}
+}
@@ -0,0 +1,149 @@
+// Command osrsflip queries OSRS Grand Exchange prices from the terminal,
+// either through a running API server or straight from the OSRS Wiki.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/logging"
+)
+
+const usage = `usage: osrsflip [flags] <command> [args]
+
+Commands:
+  search <query>          search items by name
+  item <id|name>          show one item
+  history <id>            show price history
+  flips                   rank items by flipping profit
+  watch <id|name>         print price changes as they happen
+  backtest [id ...]       replay price history through a flipping strategy
+
+Run "osrsflip <command> -h" for command flags.
+Set OSRSFLIP_API_KEY to send an API key with every request.
+
+Flags:
+`
+
+// defaultAPIURL is used when neither -api nor OSRSFLIP_API_URL is set
+const defaultAPIURL = "http://localhost:8080"
+
+// commandFunc runs a subcommand with its arguments
+type commandFunc func(ctx context.Context, app *cli, args []string) error
+
+var commands = map[string]commandFunc{
+	"search":   runSearch,
+	"item":     runItem,
+	"history":  runHistory,
+	"flips":    runFlips,
+	"watch":    runWatch,
+	"backtest": runBacktest,
+}
+
+// errUsage signals that a command was called with bad arguments and already printed its usage
+var errUsage = errors.New("usage")
+
+// cli holds the state shared by every subcommand
+type cli struct {
+	src source
+	out *printer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses the global flags, dispatches the subcommand and returns the process exit code
+func run(args []string) int {
+	// Provider and use case logs go to stderr so they never mix with command output
+	slog.SetDefault(logging.NewLogger(os.Stderr, slog.LevelError))
+
+	fs := flag.NewFlagSet("osrsflip", flag.ContinueOnError)
+	apiURL := fs.String("api", envOrDefault("OSRSFLIP_API_URL", defaultAPIURL), "API base URL (env OSRSFLIP_API_URL)")
+	direct := fs.Bool("direct", false, "query the OSRS Wiki directly instead of the API")
+	formatName := fs.String("o", "table", "output format: table, json or csv")
+	noColor := fs.Bool("no-color", false, "disable colored trend indicators (also NO_COLOR)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	command, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	format, err := parseOutputFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	app := &cli{
+		out: &printer{w: os.Stdout, format: format, color: !*noColor && useColor()},
+	}
+	if *direct {
+		// Same OSRS_WIKI_* variables as the server
+		cfg := config.Default()
+		if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		src, err := newProviderSource(cfg.OSRSWiki, time.Minute)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		app.src = src
+	} else {
+		app.src = newAPISource(*apiURL, os.Getenv("OSRSFLIP_API_KEY"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := command(ctx, app, fs.Args()[1:]); err != nil {
+		switch {
+		case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+			return 2
+		case errors.Is(err, context.Canceled):
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// envOrDefault returns the environment variable value or a default
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/backtest"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// outputFormat selects how results are printed
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+	formatCSV   outputFormat = "csv"
+)
+
+// parseOutputFormat validates an output format name
+func parseOutputFormat(name string) (outputFormat, error) {
+	switch f := outputFormat(strings.ToLower(name)); f {
+	case formatTable, formatJSON, formatCSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid output format %q (expected table, json or csv)", name)
+}
+
+// ANSI escape codes for trend colors
+const (
+	ansiReset  = "\033[0m"
+	ansiGreen  = "\033[32m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+)
+
+// printer writes rows in the selected format
+type printer struct {
+	w      io.Writer
+	format outputFormat
+	color  bool
+}
+
+// table is a set of rows with a header; the JSON form is kept separately so
+// JSON output keeps the API field names and types
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// print writes t as a table or CSV, or value as JSON
+func (p *printer) print(t table, value interface{}) error {
+	switch p.format {
+	case formatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case formatCSV:
+		cw := csv.NewWriter(p.w)
+		if err := cw.Write(t.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(t.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// trend renders a trend indicator. Colors are only used in table output;
+// the trend is the last table column so escape codes don't skew alignment.
+func (p *printer) trend(t domain.TrendType) string {
+	if p.format != formatTable {
+		return string(t)
+	}
+
+	symbol, color := "→", ansiYellow
+	switch t {
+	case domain.TrendUp:
+		symbol, color = "↑", ansiGreen
+	case domain.TrendDown:
+		symbol, color = "↓", ansiRed
+	}
+
+	label := symbol + " " + string(t)
+	if !p.color {
+		return label
+	}
+	return color + label + ansiReset
+}
+
+// gp formats a coin amount; table output uses thousands separators
+func (p *printer) gp(amount int) string {
+	if p.format != formatTable {
+		return strconv.Itoa(amount)
+	}
+
+	s := strconv.Itoa(amount)
+	sign := ""
+	if amount < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+func (p *printer) items(items []domain.ItemPrice) error {
+	t := table{header: []string{"ID", "NAME", "CATEGORY", "PRICE", "HIGH", "LOW", "VOLUME", "AVG 24H", "TREND"}}
+	for _, item := range items {
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(item.ItemID),
+			item.Name,
+			string(item.Category),
+			p.gp(item.Price),
+			p.gp(item.High),
+			p.gp(item.Low),
+			p.gp(item.Volume),
+			p.gp(item.Avg24h),
+			p.trend(item.Trend),
+		})
+	}
+	return p.print(t, items)
+}
+
+func (p *printer) item(item *domain.ItemPrice) error {
+	if p.format == formatJSON {
+		return p.print(table{}, item)
+	}
+	return p.items([]domain.ItemPrice{*item})
+}
+
+func (p *printer) history(history []domain.PriceHistoryEntry) error {
+	t := table{header: []string{"DATE", "PRICE"}}
+	for _, entry := range history {
+		t.rows = append(t.rows, []string{entry.Date, p.gp(entry.Price)})
+	}
+	return p.print(t, history)
+}
+
+func (p *printer) flips(flips []domain.Flip) error {
+	t := table{header: []string{"ID", "NAME", "BUY", "SELL", "MARGIN %", "PROFIT", "VOLUME", "TREND"}}
+	for _, flip := range flips {
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(flip.ItemID),
+			flip.Name,
+			p.gp(flip.BuyPrice),
+			p.gp(flip.SellPrice),
+			strconv.FormatFloat(flip.Margin, 'f', 2, 64),
+			p.gp(flip.Profit),
+			p.gp(flip.Volume),
+			p.trend(flip.Trend),
+		})
+	}
+	return p.print(t, flips)
+}
+
+// backtest prints the report's summary as key-value rows; the JSON form is
+// the full report, equity curve included
+func (p *printer) backtest(report backtest.Report) error {
+	percent := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	t := table{
+		header: []string{"METRIC", "VALUE"},
+		rows: [][]string{
+			{"strategy", report.Strategy},
+			{"start", date(report.Start)},
+			{"end", date(report.End)},
+			{"items", strconv.Itoa(report.Items)},
+			{"bars", strconv.Itoa(report.Bars)},
+			{"starting cash", p.gp(report.StartingCash)},
+			{"final equity", p.gp(report.FinalEquity)},
+			{"return %", percent(report.Return)},
+			{"realized profit", p.gp(report.RealizedProfit)},
+			{"tax", p.gp(report.Tax)},
+			{"trades", strconv.Itoa(report.Trades)},
+			{"rejected orders", strconv.Itoa(report.RejectedOrders)},
+			{"flips", strconv.Itoa(report.Flips)},
+			{"win rate %", percent(report.WinRate)},
+			{"max drawdown", p.gp(report.MaxDrawdown)},
+			{"max drawdown %", percent(report.MaxDrawdownPercent)},
+			{"open positions", strconv.Itoa(len(report.OpenPositions))},
+		},
+	}
+	return p.print(t, report)
+}
+
+// useColor reports whether stdout is a terminal and NO_COLOR is unset
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/backtest"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/catalog"
+	osrsclient "github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/osrs"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/repository"
+)
+
+// errItemNotFound is returned when an item lookup has no match
+var errItemNotFound = errors.New("item not found")
+
+// source is where the CLI reads prices from: the API or the OSRS Wiki directly
+type source interface {
+	Search(ctx context.Context, filter domain.ItemFilter, limit int) ([]domain.ItemPrice, error)
+	Item(ctx context.Context, id int) (*domain.ItemPrice, error)
+	History(ctx context.Context, id int, days int) ([]domain.PriceHistoryEntry, error)
+	Flips(ctx context.Context, filter domain.FlipFilter) ([]domain.Flip, error)
+	Backtest(ctx context.Context, req application.BacktestRequest) (backtest.Report, error)
+}
+
+// apiSource reads from a running API server
+type apiSource struct {
+	baseURL string
+	apiKey  string // Sent as X-API-Key when set
+	client  *http.Client
+
+	mu    sync.Mutex
+	items map[int]cachedItem // Last response per item, revalidated with If-None-Match
+}
+
+type cachedItem struct {
+	etag string
+	item domain.ItemPrice
+}
+
+func newAPISource(baseURL, apiKey string) *apiSource {
+	return &apiSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		items:   make(map[int]cachedItem),
+	}
+}
+
+// get performs a GET request and decodes the JSON response into out.
+// It returns the response status so callers can handle 304 and 404.
+func (s *apiSource) get(ctx context.Context, path string, header http.Header, out interface{}) (*http.Response, error) {
+	return s.do(ctx, http.MethodGet, path, header, nil, out)
+}
+
+// post sends in as a JSON body and decodes the JSON response into out
+func (s *apiSource) post(ctx context.Context, path string, in, out interface{}) (*http.Response, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return s.do(ctx, http.MethodPost, path, header, bytes.NewReader(body), out)
+}
+
+// do performs a request and decodes the JSON response into out
+func (s *apiSource) do(ctx context.Context, method, path string, header http.Header, body io.Reader, out interface{}) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("X-API-Key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return resp, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return resp, errItemNotFound
+	case resp.StatusCode != http.StatusOK:
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			return resp, fmt.Errorf("API error (%d): %s", resp.StatusCode, apiErr.Error)
+		}
+		return resp, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *apiSource) Search(ctx context.Context, filter domain.ItemFilter, limit int) ([]domain.ItemPrice, error) {
+	params := url.Values{}
+	if filter.Query != "" {
+		params.Set("q", filter.Query)
+	}
+	setCategoryParams(params, filter.Category, filter.Tag)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var result domain.PaginatedResult[domain.ItemPrice]
+	if _, err := s.get(ctx, "/items?"+params.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (s *apiSource) Item(ctx context.Context, id int) (*domain.ItemPrice, error) {
+	s.mu.Lock()
+	cached, ok := s.items[id]
+	s.mu.Unlock()
+
+	header := http.Header{}
+	if ok {
+		header.Set("If-None-Match", cached.etag)
+	}
+
+	var item domain.ItemPrice
+	resp, err := s.get(ctx, fmt.Sprintf("/items/%d", id), header, &item)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return &cached.item, nil
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		s.mu.Lock()
+		s.items[id] = cachedItem{etag: etag, item: item}
+		s.mu.Unlock()
+	}
+	return &item, nil
+}
+
+func (s *apiSource) History(ctx context.Context, id int, days int) ([]domain.PriceHistoryEntry, error) {
+	var history []domain.PriceHistoryEntry
+	if _, err := s.get(ctx, fmt.Sprintf("/items/%d/history?days=%d", id, days), nil, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *apiSource) Flips(ctx context.Context, filter domain.FlipFilter) ([]domain.Flip, error) {
+	params := url.Values{}
+	params.Set("min_margin", strconv.FormatFloat(filter.MinMargin, 'f', -1, 64))
+	params.Set("min_volume", strconv.Itoa(filter.MinVolume))
+	params.Set("sort", string(filter.SortBy))
+	params.Set("limit", strconv.Itoa(filter.Limit))
+	if filter.ExcludeAnomalies {
+		params.Set("exclude_anomalies", "true")
+	}
+	setCategoryParams(params, filter.Category, filter.Tag)
+
+	var flips []domain.Flip
+	if _, err := s.get(ctx, "/flips?"+params.Encode(), nil, &flips); err != nil {
+		return nil, err
+	}
+	return flips, nil
+}
+
+// setCategoryParams adds the category and tag filters shared by /items and /flips
+func setCategoryParams(params url.Values, category domain.Category, tag string) {
+	if category != "" {
+		params.Set("category", string(category))
+	}
+	if tag != "" {
+		params.Set("tag", tag)
+	}
+}
+
+func (s *apiSource) Backtest(ctx context.Context, req application.BacktestRequest) (backtest.Report, error) {
+	var report backtest.Report
+	if _, err := s.post(ctx, "/backtest", req, &report); err != nil {
+		return backtest.Report{}, err
+	}
+	return report, nil
+}
+
+// providerSource reads straight from the OSRS Wiki, running the same use cases
+// as the server over a private in-memory repository
+type providerSource struct {
+	maxAge time.Duration
+
+	mu       sync.Mutex
+	loadedAt time.Time
+
+	updatePrices *application.UpdatePricesUseCase
+	searchItems  *application.SearchItemsUseCase
+	getItem      *application.GetItemUseCase
+	findFlips    *application.FindFlipsUseCase
+	backfill     *application.BackfillHistoryUseCase
+	getHistory   *application.GetPriceHistoryUseCase
+	runBacktest  *application.RunBacktestUseCase
+}
+
+// newProviderSource creates a provider source whose prices are refetched
+// when older than maxAge
+func newProviderSource(wiki config.OSRSWikiConfig, maxAge time.Duration) (*providerSource, error) {
+	itemCatalog, err := catalog.Load()
+	if err != nil {
+		return nil, err
+	}
+	repo := repository.NewEmptyInMemoryRepository()
+	client := osrsclient.NewOsrsWikiClient(wiki)
+	return &providerSource{
+		maxAge:       maxAge,
+		updatePrices: application.NewUpdatePricesUseCase(client, repo, itemCatalog),
+		searchItems:  application.NewSearchItemsUseCase(repo),
+		getItem:      application.NewGetItemUseCase(repo),
+		findFlips:    application.NewFindFlipsUseCase(repo),
+		backfill:     application.NewBackfillHistoryUseCase(client, repo),
+		getHistory:   application.NewGetPriceHistoryUseCase(repo),
+		runBacktest:  application.NewRunBacktestUseCase(repo),
+	}, nil
+}
+
+// load fetches prices from the provider when they are missing or stale
+func (s *providerSource) load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.maxAge {
+		return nil
+	}
+	if err := s.updatePrices.Execute(ctx); err != nil {
+		return fmt.Errorf("failed to fetch prices from the OSRS Wiki: %w", err)
+	}
+	s.loadedAt = time.Now()
+	return nil
+}
+
+func (s *providerSource) Search(ctx context.Context, filter domain.ItemFilter, limit int) ([]domain.ItemPrice, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.searchItems.ExecutePaginated(ctx, filter, domain.NewPaginationParams(1, limit))
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (s *providerSource) Item(ctx context.Context, id int) (*domain.ItemPrice, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	item, err := s.getItem.Execute(ctx, strconv.Itoa(id))
+	if err != nil {
+		if err.Error() == "item not found" {
+			return nil, errItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// History fetches the item's history from the OSRS Wiki, like Backtest, then
+// reads it back the way the API serves it
+func (s *providerSource) History(ctx context.Context, id int, days int) ([]domain.PriceHistoryEntry, error) {
+	if _, err := s.Item(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.fetchHistory(ctx, []int{id}, days); err != nil {
+		return nil, err
+	}
+	return s.getHistory.Execute(ctx, strconv.Itoa(id), strconv.Itoa(days))
+}
+
+func (s *providerSource) Flips(ctx context.Context, filter domain.FlipFilter) ([]domain.Flip, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return s.findFlips.Execute(ctx, filter)
+}
+
+// Backtest fetches the history of the requested items from the OSRS Wiki at
+// the finest timestep covering req.Days, then replays it locally
+func (s *providerSource) Backtest(ctx context.Context, req application.BacktestRequest) (backtest.Report, error) {
+	if len(req.ItemIDs) == 0 {
+		return backtest.Report{}, errors.New("backtests with -direct need item IDs")
+	}
+	if err := s.load(ctx); err != nil {
+		return backtest.Report{}, err
+	}
+	if err := s.fetchHistory(ctx, req.ItemIDs, req.Days); err != nil {
+		return backtest.Report{}, err
+	}
+	return s.runBacktest.Execute(ctx, req)
+}
+
+// fetchHistory backfills the items' history from the OSRS Wiki at the finest
+// timestep covering days; it fails only when no item could be fetched
+func (s *providerSource) fetchHistory(ctx context.Context, itemIDs []int, days int) error {
+	// /timeseries returns up to 365 points per item
+	timestep := "24h"
+	switch {
+	case days <= 15:
+		timestep = "1h"
+	case days <= 90:
+		timestep = "6h"
+	}
+	summary, err := s.backfill.Execute(ctx, itemIDs, timestep, time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to fetch price history from the OSRS Wiki: %w", err)
+	}
+	if summary.Failed == summary.Items {
+		return errors.New("failed to fetch price history from the OSRS Wiki")
+	}
+	return nil
+}
+
+// resolveItem looks an item up by numeric ID or by name. Names match exactly
+// (ignoring case) first, then by substring when that is unambiguous.
+func resolveItem(ctx context.Context, src source, ref string) (*domain.ItemPrice, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return src.Item(ctx, id)
+	}
+
+	matches, err := src.Search(ctx, domain.ItemFilter{Query: ref}, 100)
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		if strings.EqualFold(matches[i].Name, ref) {
+			return &matches[i], nil
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errItemNotFound
+	case 1:
+		return &matches[0], nil
+	}
+
+	names := make([]string, 0, 5)
+	for i := 0; i < len(matches) && i < 5; i++ {
+		names = append(names, fmt.Sprintf("%s (%d)", matches[i].Name, matches[i].ItemID))
+	}
+	return nil, fmt.Errorf("%q matches %d items, e.g. %s; use the item ID", ref, len(matches), strings.Join(names, ", "))
+}
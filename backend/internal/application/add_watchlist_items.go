@@ -0,0 +1,33 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AddWatchlistItemsUseCase handles adding items to a watchlist
+type AddWatchlistItemsUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewAddWatchlistItemsUseCase creates a new AddWatchlistItemsUseCase
+func NewAddWatchlistItemsUseCase(repo domain.WatchlistRepository) *AddWatchlistItemsUseCase {
+	return &AddWatchlistItemsUseCase{repo: repo}
+}
+
+// Execute appends the items that are not in the watchlist yet, ignoring the
+// others, and returns the watchlist updated
+func (uc *AddWatchlistItemsUseCase) Execute(ctx context.Context, userID, id string, itemIDs []int) (*domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "AddWatchlistItemsUseCase.Execute",
+		trace.WithAttributes(
+			attribute.String("watchlist.id", id),
+			attribute.Int("items.count", len(itemIDs)),
+		))
+	defer span.End()
+
+	return uc.repo.AddWatchlistItems(ctx, userID, id, itemIDs, time.Now().UTC())
+}
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// APIKeyAccess is the outcome of authenticating a request with an API key
+type APIKeyAccess struct {
+	Key        domain.APIKey
+	DailyQuota int // Effective quota, after applying the default; 0 means unlimited
+	Quota      domain.QuotaResult
+}
+
+// AuthenticateAPIKeyUseCase handles checking API keys and counting their usage
+type AuthenticateAPIKeyUseCase struct {
+	repo domain.APIKeyRepository
+}
+
+// NewAuthenticateAPIKeyUseCase creates a new AuthenticateAPIKeyUseCase
+func NewAuthenticateAPIKeyUseCase(repo domain.APIKeyRepository) *AuthenticateAPIKeyUseCase {
+	return &AuthenticateAPIKeyUseCase{repo: repo}
+}
+
+// Execute finds the key matching secret and counts the request against its
+// daily quota; keys without their own quota use defaultQuota. It returns
+// domain.ErrAPIKeyNotFound or domain.ErrAPIKeyRevoked for unusable keys.
+// A request over the quota is reported with Quota.Allowed false.
+func (uc *AuthenticateAPIKeyUseCase) Execute(ctx context.Context, secret string, defaultQuota int) (APIKeyAccess, error) {
+	ctx, span := tracer.Start(ctx, "AuthenticateAPIKeyUseCase.Execute")
+	defer span.End()
+
+	key, err := uc.repo.GetAPIKeyByHash(ctx, domain.HashAPIKey(secret))
+	if err != nil {
+		return APIKeyAccess{}, err
+	}
+	span.SetAttributes(attribute.String("api_key.id", key.ID))
+	if key.Revoked() {
+		return APIKeyAccess{}, domain.ErrAPIKeyRevoked
+	}
+
+	quota := key.DailyQuota
+	if quota == 0 {
+		quota = defaultQuota
+	}
+	result, err := uc.repo.ConsumeAPIKeyQuota(ctx, key.ID, time.Now().UTC(), quota)
+	if err != nil {
+		return APIKeyAccess{}, err
+	}
+	span.SetAttributes(attribute.Bool("api_key.quota_allowed", result.Allowed))
+
+	return APIKeyAccess{Key: *key, DailyQuota: quota, Quota: result}, nil
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BackfillSummary reports the outcome of a history backfill
+type BackfillSummary struct {
+	Items    int `json:"items"`
+	Failed   int `json:"failed"`
+	Fetched  int `json:"fetched"`
+	Inserted int `json:"inserted"`
+}
+
+// BackfillHistoryUseCase handles loading past prices from the provider into the repository
+type BackfillHistoryUseCase struct {
+	provider domain.HistoryProvider
+	repo     domain.ItemRepository
+}
+
+// NewBackfillHistoryUseCase creates a new BackfillHistoryUseCase
+func NewBackfillHistoryUseCase(provider domain.HistoryProvider, repo domain.ItemRepository) *BackfillHistoryUseCase {
+	return &BackfillHistoryUseCase{provider: provider, repo: repo}
+}
+
+// Execute fetches the history of each item and stores the points not stored yet.
+// Items are fetched one at a time with pause in between to stay polite to the
+// provider; a failing item is logged and skipped. When itemIDs is empty every
+// item in the repository is backfilled.
+func (uc *BackfillHistoryUseCase) Execute(ctx context.Context, itemIDs []int, timestep string, pause time.Duration) (BackfillSummary, error) {
+	ctx, span := tracer.Start(ctx, "BackfillHistoryUseCase.Execute",
+		trace.WithAttributes(attribute.String("backfill.timestep", timestep)))
+	defer span.End()
+
+	summary := BackfillSummary{}
+
+	if len(itemIDs) == 0 {
+		err := uc.repo.ForEachItem(ctx, domain.ItemFilter{}, func(item domain.ItemPrice) error {
+			itemIDs = append(itemIDs, item.ItemID)
+			return nil
+		})
+		if err != nil {
+			return summary, err
+		}
+	}
+
+	start := time.Now()
+	for i, itemID := range itemIDs {
+		if i > 0 && pause > 0 {
+			select {
+			case <-ctx.Done():
+				return summary, ctx.Err()
+			case <-time.After(pause):
+			}
+		}
+
+		summary.Items++
+		entries, err := uc.provider.FetchPriceHistory(ctx, itemID, timestep)
+		if err != nil {
+			if ctx.Err() != nil {
+				return summary, ctx.Err()
+			}
+			summary.Failed++
+			slog.WarnContext(ctx, "failed to fetch item history", "item_id", itemID, "error", err)
+			continue
+		}
+		summary.Fetched += len(entries)
+
+		if len(entries) == 0 {
+			continue
+		}
+		inserted, err := uc.repo.SavePriceHistoryBatch(ctx, entries)
+		if err != nil {
+			return summary, err
+		}
+		summary.Inserted += inserted
+
+		slog.DebugContext(ctx, "backfilled item history",
+			"item_id", itemID,
+			"fetched_count", len(entries),
+			"inserted_count", inserted,
+		)
+	}
+
+	span.SetAttributes(
+		attribute.Int("backfill.items", summary.Items),
+		attribute.Int("backfill.failed", summary.Failed),
+		attribute.Int("backfill.inserted", summary.Inserted),
+	)
+	slog.InfoContext(ctx, "history backfill finished",
+		"item_count", summary.Items,
+		"failed_count", summary.Failed,
+		"fetched_count", summary.Fetched,
+		"inserted_count", summary.Inserted,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return summary, nil
+}
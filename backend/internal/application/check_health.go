@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// UpdateStatusReporter exposes the outcome of the most recent price updates
+type UpdateStatusReporter interface {
+	Status() domain.UpdateStatus
+}
+
+// NamesCacheReporter exposes the age of a provider's item names cache
+type NamesCacheReporter interface {
+	NamesCacheAge() (age time.Duration, ttl time.Duration, ok bool)
+}
+
+// CheckHealthUseCase handles readiness checks of the service dependencies
+type CheckHealthUseCase struct {
+	repo           domain.ItemRepository
+	updates        UpdateStatusReporter
+	namesCache     NamesCacheReporter
+	updateInterval atomic.Int64 // time.Duration; changed on config reload
+}
+
+// NewCheckHealthUseCase creates a new CheckHealthUseCase.
+// updates and namesCache are nil when prices are updated by another process;
+// freshness is then judged from the repository's data version.
+func NewCheckHealthUseCase(
+	repo domain.ItemRepository,
+	updates UpdateStatusReporter,
+	namesCache NamesCacheReporter,
+	updateInterval time.Duration,
+) *CheckHealthUseCase {
+	uc := &CheckHealthUseCase{
+		repo:       repo,
+		updates:    updates,
+		namesCache: namesCache,
+	}
+	uc.SetUpdateInterval(updateInterval)
+	return uc
+}
+
+// SetUpdateInterval changes the interval price freshness is judged against
+func (uc *CheckHealthUseCase) SetUpdateInterval(interval time.Duration) {
+	uc.updateInterval.Store(int64(interval))
+}
+
+// Execute checks every component and aggregates them into a report.
+// The overall status is the worst status among the components.
+func (uc *CheckHealthUseCase) Execute(ctx context.Context) domain.HealthReport {
+	now := time.Now()
+
+	components := map[string]domain.ComponentHealth{
+		"repository": uc.checkRepository(ctx, now),
+	}
+	if uc.updates != nil {
+		status := uc.updates.Status()
+		components["price_data"] = uc.checkPriceFreshness(status.LastSuccessAt, now)
+		components["provider"] = uc.checkProvider(status, now)
+	} else {
+		components["price_data"] = uc.checkSharedPriceFreshness(ctx, now)
+	}
+	if uc.namesCache != nil {
+		components["names_cache"] = uc.checkNamesCache(now)
+	}
+
+	overall := domain.HealthOK
+	for _, c := range components {
+		if c.Status == domain.HealthDown {
+			overall = domain.HealthDown
+			break
+		}
+		if c.Status == domain.HealthDegraded {
+			overall = domain.HealthDegraded
+		}
+	}
+
+	return domain.HealthReport{
+		Status:     overall,
+		Components: components,
+	}
+}
+
+// staleAfter is how long price data may go without a successful update.
+// Allows one missed cycle before reporting degradation.
+func (uc *CheckHealthUseCase) staleAfter() time.Duration {
+	return 2 * time.Duration(uc.updateInterval.Load())
+}
+
+// checkRepository verifies the repository answers queries and holds items
+func (uc *CheckHealthUseCase) checkRepository(ctx context.Context, now time.Time) domain.ComponentHealth {
+	result, err := uc.repo.GetAllItemsPaginated(ctx, domain.NewPaginationParams(1, 1))
+	if err != nil {
+		return domain.ComponentHealth{Status: domain.HealthDown, Message: "repository unreachable", CheckedAt: now}
+	}
+	if result.Total == 0 {
+		return domain.ComponentHealth{Status: domain.HealthDegraded, Message: "repository is empty", CheckedAt: now}
+	}
+	return domain.ComponentHealth{Status: domain.HealthOK, Message: fmt.Sprintf("%d items", result.Total), CheckedAt: now}
+}
+
+// checkPriceFreshness verifies prices were updated within the expected interval
+func (uc *CheckHealthUseCase) checkPriceFreshness(lastSuccessAt time.Time, now time.Time) domain.ComponentHealth {
+	if lastSuccessAt.IsZero() {
+		return domain.ComponentHealth{Status: domain.HealthDegraded, Message: "no successful price update yet", CheckedAt: now}
+	}
+
+	age := now.Sub(lastSuccessAt)
+	if age > uc.staleAfter() {
+		return domain.ComponentHealth{
+			Status:    domain.HealthDegraded,
+			Message:   fmt.Sprintf("prices are stale: last update %s ago (interval %s)", age.Round(time.Second), time.Duration(uc.updateInterval.Load())),
+			CheckedAt: now,
+		}
+	}
+	return domain.ComponentHealth{
+		Status:    domain.HealthOK,
+		Message:   fmt.Sprintf("last update %s ago", age.Round(time.Second)),
+		CheckedAt: now,
+	}
+}
+
+// checkSharedPriceFreshness verifies the prices written by another process are recent
+func (uc *CheckHealthUseCase) checkSharedPriceFreshness(ctx context.Context, now time.Time) domain.ComponentHealth {
+	version, err := uc.repo.GetDataVersion(ctx)
+	if err != nil {
+		return domain.ComponentHealth{Status: domain.HealthDown, Message: "data version unavailable", CheckedAt: now}
+	}
+	return uc.checkPriceFreshness(version.UpdatedAt, now)
+}
+
+// checkProvider reports update errors, from the provider or the repository,
+// that happened within the freshness window and were not followed by a
+// successful update
+func (uc *CheckHealthUseCase) checkProvider(status domain.UpdateStatus, now time.Time) domain.ComponentHealth {
+	if status.LastErrorAt.IsZero() || now.Sub(status.LastErrorAt) > uc.staleAfter() {
+		return domain.ComponentHealth{Status: domain.HealthOK, CheckedAt: now}
+	}
+
+	ago := now.Sub(status.LastErrorAt).Round(time.Second)
+	if !status.LastErrorAt.After(status.LastSuccessAt) {
+		return domain.ComponentHealth{
+			Status:    domain.HealthOK,
+			Message:   fmt.Sprintf("recovered from update error %s ago: %s", ago, status.LastError),
+			CheckedAt: now,
+		}
+	}
+	return domain.ComponentHealth{
+		Status:    domain.HealthDegraded,
+		Message:   fmt.Sprintf("last update error %s ago: %s", ago, status.LastError),
+		CheckedAt: now,
+	}
+}
+
+// checkNamesCache verifies the item names mapping is being refreshed
+func (uc *CheckHealthUseCase) checkNamesCache(now time.Time) domain.ComponentHealth {
+	age, ttl, ok := uc.namesCache.NamesCacheAge()
+	if !ok {
+		return domain.ComponentHealth{Status: domain.HealthDegraded, Message: "item names not loaded", CheckedAt: now}
+	}
+
+	// Names are refreshed on the first update after the TTL expires
+	if age > ttl+uc.staleAfter() {
+		return domain.ComponentHealth{
+			Status:    domain.HealthDegraded,
+			Message:   fmt.Sprintf("item names cache is %s old (ttl %s)", age.Round(time.Second), ttl),
+			CheckedAt: now,
+		}
+	}
+	return domain.ComponentHealth{
+		Status:    domain.HealthOK,
+		Message:   fmt.Sprintf("cached %s ago", age.Round(time.Second)),
+		CheckedAt: now,
+	}
+}
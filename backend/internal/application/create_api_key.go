@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// apiKeySecretPrefix marks secrets so they are recognizable in logs and secret scanners
+const apiKeySecretPrefix = "osrs_"
+
+// apiKeyDisplayLength is how many characters of the secret are kept as the key prefix
+const apiKeyDisplayLength = len(apiKeySecretPrefix) + 8
+
+// CreateAPIKeyUseCase handles issuing new API keys
+type CreateAPIKeyUseCase struct {
+	repo domain.APIKeyRepository
+}
+
+// NewCreateAPIKeyUseCase creates a new CreateAPIKeyUseCase
+func NewCreateAPIKeyUseCase(repo domain.APIKeyRepository) *CreateAPIKeyUseCase {
+	return &CreateAPIKeyUseCase{repo: repo}
+}
+
+// Execute creates a key and returns it with its secret. The secret is not
+// stored and cannot be retrieved again. Zero limits use the configured defaults.
+func (uc *CreateAPIKeyUseCase) Execute(ctx context.Context, name string, rateLimit, dailyQuota int) (domain.APIKey, string, error) {
+	ctx, span := tracer.Start(ctx, "CreateAPIKeyUseCase.Execute")
+	defer span.End()
+
+	id, err := randomHex(8)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	span.SetAttributes(attribute.String("api_key.id", id))
+	secretBytes, err := randomHex(24)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	secret := apiKeySecretPrefix + secretBytes
+
+	key := domain.APIKey{
+		ID:         id,
+		Name:       name,
+		Prefix:     secret[:apiKeyDisplayLength],
+		Hash:       domain.HashAPIKey(secret),
+		RateLimit:  rateLimit,
+		DailyQuota: dailyQuota,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := uc.repo.CreateAPIKey(ctx, key); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return key, secret, nil
+}
+
+// randomHex returns n random bytes from crypto/rand, hex encoded
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CreateWatchlistUseCase handles creating watchlists
+type CreateWatchlistUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewCreateWatchlistUseCase creates a new CreateWatchlistUseCase
+func NewCreateWatchlistUseCase(repo domain.WatchlistRepository) *CreateWatchlistUseCase {
+	return &CreateWatchlistUseCase{repo: repo}
+}
+
+// Execute creates a watchlist for the user, optionally with initial items.
+// Users are limited to domain.MaxWatchlistsPerUser lists.
+func (uc *CreateWatchlistUseCase) Execute(ctx context.Context, userID, name string, itemIDs []int) (domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "CreateWatchlistUseCase.Execute")
+	defer span.End()
+
+	id, err := randomHex(8)
+	if err != nil {
+		return domain.Watchlist{}, err
+	}
+	span.SetAttributes(attribute.String("watchlist.id", id))
+
+	now := time.Now().UTC()
+	watchlist := domain.Watchlist{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		ItemIDs:   []int{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := watchlist.AddItems(itemIDs); err != nil {
+		return domain.Watchlist{}, err
+	}
+
+	if err := uc.repo.CreateWatchlist(ctx, watchlist, domain.MaxWatchlistsPerUser); err != nil {
+		return domain.Watchlist{}, err
+	}
+	return watchlist, nil
+}
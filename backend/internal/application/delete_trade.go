@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DeleteTradeUseCase handles removing a trade logged by mistake
+type DeleteTradeUseCase struct {
+	repo domain.TradeRepository
+}
+
+// NewDeleteTradeUseCase creates a new DeleteTradeUseCase
+func NewDeleteTradeUseCase(repo domain.TradeRepository) *DeleteTradeUseCase {
+	return &DeleteTradeUseCase{repo: repo}
+}
+
+// Execute deletes one of the user's trades
+func (uc *DeleteTradeUseCase) Execute(ctx context.Context, userID, id string) error {
+	ctx, span := tracer.Start(ctx, "DeleteTradeUseCase.Execute",
+		trace.WithAttributes(attribute.String("trade.id", id)))
+	defer span.End()
+
+	return uc.repo.DeleteTrade(ctx, userID, id)
+}
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DeleteWatchlistUseCase handles deleting watchlists
+type DeleteWatchlistUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewDeleteWatchlistUseCase creates a new DeleteWatchlistUseCase
+func NewDeleteWatchlistUseCase(repo domain.WatchlistRepository) *DeleteWatchlistUseCase {
+	return &DeleteWatchlistUseCase{repo: repo}
+}
+
+// Execute deletes one of the user's watchlists
+func (uc *DeleteWatchlistUseCase) Execute(ctx context.Context, userID, id string) error {
+	ctx, span := tracer.Start(ctx, "DeleteWatchlistUseCase.Execute",
+		trace.WithAttributes(attribute.String("watchlist.id", id)))
+	defer span.End()
+
+	return uc.repo.DeleteWatchlist(ctx, userID, id)
+}
@@ -0,0 +1,33 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExportItemsUseCase handles streaming the item catalog
+type ExportItemsUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewExportItemsUseCase creates a new ExportItemsUseCase
+func NewExportItemsUseCase(repo domain.ItemRepository) *ExportItemsUseCase {
+	return &ExportItemsUseCase{repo: repo}
+}
+
+// Execute calls fn for every item matching filter, ordered by ID, without
+// materializing the catalog. A zero filter exports every item.
+func (uc *ExportItemsUseCase) Execute(ctx context.Context, filter domain.ItemFilter, fn func(item domain.ItemPrice) error) error {
+	ctx, span := tracer.Start(ctx, "ExportItemsUseCase.Execute",
+		trace.WithAttributes(
+			attribute.String("search.query", filter.Query),
+			attribute.String("search.category", string(filter.Category)),
+			attribute.String("search.tag", filter.Tag),
+		))
+	defer span.End()
+
+	return uc.repo.ForEachItem(ctx, filter, fn)
+}
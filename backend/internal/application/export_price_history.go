@@ -0,0 +1,47 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExportPriceHistoryUseCase handles streaming price history for many items
+type ExportPriceHistoryUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewExportPriceHistoryUseCase creates a new ExportPriceHistoryUseCase
+func NewExportPriceHistoryUseCase(repo domain.ItemRepository) *ExportPriceHistoryUseCase {
+	return &ExportPriceHistoryUseCase{repo: repo}
+}
+
+// Execute calls fn for every history entry of the last days days, for each
+// item matching filter. Entries are grouped by item ID and ordered by date.
+// Only one item's history is held in memory at a time.
+func (uc *ExportPriceHistoryUseCase) Execute(ctx context.Context, filter domain.ItemFilter, days int, fn func(entry domain.PriceHistory) error) error {
+	ctx, span := tracer.Start(ctx, "ExportPriceHistoryUseCase.Execute",
+		trace.WithAttributes(
+			attribute.String("search.query", filter.Query),
+			attribute.String("search.category", string(filter.Category)),
+			attribute.String("search.tag", filter.Tag),
+			attribute.Int("history.days", days),
+		))
+	defer span.End()
+
+	return uc.repo.ForEachItem(ctx, filter, func(item domain.ItemPrice) error {
+		history, err := uc.repo.GetPriceHistory(ctx, item.ItemID, days)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range history {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
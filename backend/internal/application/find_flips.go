@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FindFlipsUseCase handles ranking items by flipping potential
+type FindFlipsUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewFindFlipsUseCase creates a new FindFlipsUseCase
+func NewFindFlipsUseCase(repo domain.ItemRepository) *FindFlipsUseCase {
+	return &FindFlipsUseCase{repo: repo}
+}
+
+// Execute returns profitable flips matching the filter, best first.
+// Items without both a buy and a sell price are skipped.
+func (uc *FindFlipsUseCase) Execute(ctx context.Context, filter domain.FlipFilter) ([]domain.Flip, error) {
+	ctx, span := tracer.Start(ctx, "FindFlipsUseCase.Execute",
+		trace.WithAttributes(
+			attribute.Float64("flips.min_margin", filter.MinMargin),
+			attribute.Int("flips.min_volume", filter.MinVolume),
+			attribute.String("flips.sort", string(filter.SortBy)),
+			attribute.Bool("flips.exclude_anomalies", filter.ExcludeAnomalies),
+			attribute.String("flips.category", string(filter.Category)),
+			attribute.String("flips.tag", filter.Tag),
+		))
+	defer span.End()
+
+	items, err := uc.repo.GetAllItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category := domain.ItemFilter{Category: filter.Category, Tag: filter.Tag}
+	flips := make([]domain.Flip, 0)
+	for _, item := range items {
+		if item.Low <= 0 || item.High <= 0 {
+			continue
+		}
+		if filter.ExcludeAnomalies && item.Suspicious() {
+			continue
+		}
+		if !category.Matches(item) {
+			continue
+		}
+
+		flip := domain.NewFlip(item)
+		if flip.Profit <= 0 || flip.Margin < filter.MinMargin || flip.Volume < filter.MinVolume {
+			continue
+		}
+		if filter.BuyLimits != nil {
+			status, bought := filter.BuyLimits[item.ItemID]
+			flip.ApplyBuyLimit(status, bought)
+		}
+		flips = append(flips, flip)
+	}
+
+	sortFlips(flips, filter.SortBy)
+	// Capped items cannot be bought until their limit resets, so rank them last
+	sort.SliceStable(flips, func(i, j int) bool {
+		return !flips[i].Capped && flips[j].Capped
+	})
+
+	if filter.Limit > 0 && len(flips) > filter.Limit {
+		flips = flips[:filter.Limit]
+	}
+
+	span.SetAttributes(attribute.Int("flips.count", len(flips)))
+	return flips, nil
+}
+
+// sortFlips orders flips by the requested key, descending, with item ID as tie-breaker
+func sortFlips(flips []domain.Flip, by domain.FlipSort) {
+	less := func(a, b domain.Flip) bool { return a.Profit > b.Profit }
+	switch by {
+	case domain.FlipSortMargin:
+		less = func(a, b domain.Flip) bool { return a.Margin > b.Margin }
+	case domain.FlipSortVolume:
+		less = func(a, b domain.Flip) bool { return a.Volume > b.Volume }
+	}
+
+	sort.Slice(flips, func(i, j int) bool {
+		if less(flips[i], flips[j]) {
+			return true
+		}
+		if less(flips[j], flips[i]) {
+			return false
+		}
+		return flips[i].ItemID < flips[j].ItemID
+	})
+}
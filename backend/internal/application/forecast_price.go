@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/forecast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ItemForecast is the price forecast of an item
+type ItemForecast struct {
+	ItemID int    `json:"item_id"`
+	Name   string `json:"name"`
+	Price  int    `json:"price"` // Current price
+	forecast.Forecast
+}
+
+// ForecastPriceUseCase handles forecasting item prices from their stored history
+type ForecastPriceUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewForecastPriceUseCase creates a new ForecastPriceUseCase
+func NewForecastPriceUseCase(repo domain.ItemRepository) *ForecastPriceUseCase {
+	return &ForecastPriceUseCase{repo: repo}
+}
+
+// Execute forecasts the item's price over forecast.Horizons from the last
+// days of history and its current price. It returns
+// forecast.ErrInsufficientHistory when the history is too short.
+func (uc *ForecastPriceUseCase) Execute(ctx context.Context, itemID int, days int) (ItemForecast, error) {
+	ctx, span := tracer.Start(ctx, "ForecastPriceUseCase.Execute",
+		trace.WithAttributes(attribute.Int("item.id", itemID), attribute.Int("forecast.days", days)))
+	defer span.End()
+
+	item, err := uc.repo.GetItemByID(ctx, itemID)
+	if err != nil {
+		return ItemForecast{}, errors.New("item not found")
+	}
+
+	history, err := uc.repo.GetPriceHistory(ctx, itemID, days)
+	if err != nil {
+		return ItemForecast{}, err
+	}
+	points := make([]forecast.Point, 0, len(history)+1)
+	for _, entry := range history {
+		points = append(points, forecast.Point{Time: entry.Date, Price: float64(entry.Price)})
+	}
+	// The current price is the latest observation when newer than the history
+	if item.Price > 0 && (len(history) == 0 || item.UpdatedAt.After(history[len(history)-1].Date)) {
+		points = append(points, forecast.Point{Time: item.UpdatedAt, Price: float64(item.Price)})
+	}
+
+	f, err := forecast.Run(points, forecast.Horizons)
+	if err != nil {
+		return ItemForecast{}, err
+	}
+	span.SetAttributes(
+		attribute.String("forecast.method", f.Method),
+		attribute.Int("forecast.points", f.Points),
+	)
+	return ItemForecast{ItemID: item.ItemID, Name: item.Name, Price: item.Price, Forecast: f}, nil
+}
@@ -0,0 +1,36 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetAPIKeyUsageUseCase handles retrieving a key with its usage counters
+type GetAPIKeyUsageUseCase struct {
+	repo domain.APIKeyRepository
+}
+
+// NewGetAPIKeyUsageUseCase creates a new GetAPIKeyUsageUseCase
+func NewGetAPIKeyUsageUseCase(repo domain.APIKeyRepository) *GetAPIKeyUsageUseCase {
+	return &GetAPIKeyUsageUseCase{repo: repo}
+}
+
+// Execute returns the key and its daily counters for the last days, newest first
+func (uc *GetAPIKeyUsageUseCase) Execute(ctx context.Context, id string, days int) (*domain.APIKey, []domain.APIKeyUsage, error) {
+	ctx, span := tracer.Start(ctx, "GetAPIKeyUsageUseCase.Execute",
+		trace.WithAttributes(attribute.String("api_key.id", id), attribute.Int("days", days)))
+	defer span.End()
+
+	key, err := uc.repo.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	usage, err := uc.repo.GetAPIKeyUsage(ctx, id, days)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, usage, nil
+}
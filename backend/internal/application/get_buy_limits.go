@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetBuyLimitsUseCase handles tracking a user's purchases against GE buy limits
+type GetBuyLimitsUseCase struct {
+	trades domain.TradeRepository
+	items  domain.ItemRepository
+}
+
+// NewGetBuyLimitsUseCase creates a new GetBuyLimitsUseCase
+func NewGetBuyLimitsUseCase(trades domain.TradeRepository, items domain.ItemRepository) *GetBuyLimitsUseCase {
+	return &GetBuyLimitsUseCase{trades: trades, items: items}
+}
+
+// Execute returns the buy limit status of every item the user bought within
+// the last domain.BuyLimitWindow, counting the buys logged in their trade
+// journal. With itemID set, only that item's status is returned, even
+// without recent buys.
+func (uc *GetBuyLimitsUseCase) Execute(ctx context.Context, userID string, itemID int) ([]domain.BuyLimitStatus, error) {
+	ctx, span := tracer.Start(ctx, "GetBuyLimitsUseCase.Execute",
+		trace.WithAttributes(attribute.Int("item.id", itemID)))
+	defer span.End()
+
+	now := time.Now()
+	trades, err := uc.trades.ListTrades(ctx, userID, domain.TradeFilter{
+		ItemID: itemID,
+		From:   now.Add(-domain.BuyLimitWindow),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	itemIDs := []int{}
+	if itemID != 0 {
+		seen[itemID] = true
+		itemIDs = append(itemIDs, itemID)
+	}
+	for _, trade := range trades {
+		if !seen[trade.ItemID] {
+			seen[trade.ItemID] = true
+			itemIDs = append(itemIDs, trade.ItemID)
+		}
+	}
+	items, err := uc.items.GetItemsByIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []domain.BuyLimitStatus
+	if itemID != 0 {
+		item, ok := items[itemID]
+		if !ok {
+			item = domain.ItemPrice{ItemID: itemID}
+		}
+		statuses = []domain.BuyLimitStatus{domain.NewBuyLimitStatus(item, trades, now)}
+	} else {
+		statuses = domain.BuyLimitStatuses(trades, items, now)
+	}
+
+	span.SetAttributes(attribute.Int("limits.count", len(statuses)))
+	return statuses, nil
+}
@@ -0,0 +1,22 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// GetDataVersionUseCase handles retrieving the version of the stored price data
+type GetDataVersionUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewGetDataVersionUseCase creates a new GetDataVersionUseCase
+func NewGetDataVersionUseCase(repo domain.ItemRepository) *GetDataVersionUseCase {
+	return &GetDataVersionUseCase{repo: repo}
+}
+
+// Execute returns the current data version, used to validate HTTP caches
+func (uc *GetDataVersionUseCase) Execute(ctx context.Context) (domain.DataVersion, error) {
+	return uc.repo.GetDataVersion(ctx)
+}
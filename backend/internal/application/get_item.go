@@ -6,6 +6,8 @@ import (
 	"strconv"
 
 	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // GetItemUseCase handles retrieving an item by ID
@@ -20,6 +22,10 @@ func NewGetItemUseCase(repo domain.ItemRepository) *GetItemUseCase {
 
 // Execute retrieves an item by its ID
 func (uc *GetItemUseCase) Execute(ctx context.Context, idStr string) (*domain.ItemPrice, error) {
+	ctx, span := tracer.Start(ctx, "GetItemUseCase.Execute",
+		trace.WithAttributes(attribute.String("item.id", idStr)))
+	defer span.End()
+
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		return nil, errors.New("invalid item ID")
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetItemsBatchUseCase handles retrieving many items by ID in one call
+type GetItemsBatchUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewGetItemsBatchUseCase creates a new GetItemsBatchUseCase
+func NewGetItemsBatchUseCase(repo domain.ItemRepository) *GetItemsBatchUseCase {
+	return &GetItemsBatchUseCase{repo: repo}
+}
+
+// Execute returns one result per requested ID, marking IDs that were not found
+func (uc *GetItemsBatchUseCase) Execute(ctx context.Context, ids []int) (map[int]domain.BatchItemResult, error) {
+	ctx, span := tracer.Start(ctx, "GetItemsBatchUseCase.Execute",
+		trace.WithAttributes(attribute.Int("items.requested", len(ids))))
+	defer span.End()
+
+	items, err := uc.repo.GetItemsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]domain.BatchItemResult, len(ids))
+	for _, id := range ids {
+		item, found := items[id]
+		if !found {
+			results[id] = domain.BatchItemResult{Found: false}
+			continue
+		}
+		results[id] = domain.BatchItemResult{Found: true, Item: &item}
+	}
+
+	span.SetAttributes(attribute.Int("items.found", len(items)))
+	return results, nil
+}
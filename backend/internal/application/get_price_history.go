@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // GetPriceHistoryUseCase handles retrieving price history for an item
@@ -22,6 +24,10 @@ func NewGetPriceHistoryUseCase(repo domain.ItemRepository) *GetPriceHistoryUseCa
 // Execute retrieves price history for an item
 // days defaults to 7 if not provided or invalid
 func (uc *GetPriceHistoryUseCase) Execute(ctx context.Context, idStr string, daysStr string) ([]domain.PriceHistoryEntry, error) {
+	ctx, span := tracer.Start(ctx, "GetPriceHistoryUseCase.Execute",
+		trace.WithAttributes(attribute.String("item.id", idStr), attribute.String("history.days", daysStr)))
+	defer span.End()
+
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		return nil, errors.New("invalid item ID")
@@ -48,6 +54,8 @@ func (uc *GetPriceHistoryUseCase) Execute(ctx context.Context, idStr string, day
 		return nil, err
 	}
 
+	span.SetAttributes(attribute.Int("history.entries", len(history)))
+
 	// Convert to API response format
 	entries := make([]domain.PriceHistoryEntry, len(history))
 	for i, h := range history {
@@ -0,0 +1,57 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetTradeReportUseCase handles computing profit and loss from a user's trades
+type GetTradeReportUseCase struct {
+	trades domain.TradeRepository
+	items  domain.ItemRepository
+}
+
+// NewGetTradeReportUseCase creates a new GetTradeReportUseCase
+func NewGetTradeReportUseCase(trades domain.TradeRepository, items domain.ItemRepository) *GetTradeReportUseCase {
+	return &GetTradeReportUseCase{trades: trades, items: items}
+}
+
+// Execute reports realized profit of the flips sold within filter's time
+// range, grouped by item and period, and marks open positions to current
+// prices. Every trade up to filter.To is matched, since sales in the range
+// may close buys made before it.
+func (uc *GetTradeReportUseCase) Execute(ctx context.Context, userID string, filter domain.TradeFilter, period domain.ReportPeriod) (domain.TradeReport, error) {
+	ctx, span := tracer.Start(ctx, "GetTradeReportUseCase.Execute",
+		trace.WithAttributes(
+			attribute.Int("item.id", filter.ItemID),
+			attribute.String("report.period", string(period)),
+		))
+	defer span.End()
+
+	trades, err := uc.trades.ListTrades(ctx, userID, domain.TradeFilter{ItemID: filter.ItemID, To: filter.To})
+	if err != nil {
+		return domain.TradeReport{}, err
+	}
+
+	seen := make(map[int]bool)
+	var itemIDs []int
+	for _, trade := range trades {
+		if !seen[trade.ItemID] {
+			seen[trade.ItemID] = true
+			itemIDs = append(itemIDs, trade.ItemID)
+		}
+	}
+	prices, err := uc.items.GetItemsByIDs(ctx, itemIDs)
+	if err != nil {
+		return domain.TradeReport{}, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("trades.count", len(trades)),
+		attribute.Int("items.count", len(itemIDs)),
+	)
+	return domain.BuildTradeReport(trades, filter, period, prices), nil
+}
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetUserUseCase handles retrieving a user account
+type GetUserUseCase struct {
+	users domain.UserRepository
+}
+
+// NewGetUserUseCase creates a new GetUserUseCase
+func NewGetUserUseCase(users domain.UserRepository) *GetUserUseCase {
+	return &GetUserUseCase{users: users}
+}
+
+// Execute returns the user with the given ID
+func (uc *GetUserUseCase) Execute(ctx context.Context, id string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "GetUserUseCase.Execute",
+		trace.WithAttributes(attribute.String("user.id", id)))
+	defer span.End()
+
+	return uc.users.GetUserByID(ctx, id)
+}
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetWatchlistUseCase handles retrieving one watchlist
+type GetWatchlistUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewGetWatchlistUseCase creates a new GetWatchlistUseCase
+func NewGetWatchlistUseCase(repo domain.WatchlistRepository) *GetWatchlistUseCase {
+	return &GetWatchlistUseCase{repo: repo}
+}
+
+// Execute returns one of the user's watchlists
+func (uc *GetWatchlistUseCase) Execute(ctx context.Context, userID, id string) (*domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "GetWatchlistUseCase.Execute",
+		trace.WithAttributes(attribute.String("watchlist.id", id)))
+	defer span.End()
+
+	return uc.repo.GetWatchlist(ctx, userID, id)
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetWatchlistItemsUseCase handles retrieving the market data of a watchlist
+type GetWatchlistItemsUseCase struct {
+	watchlists domain.WatchlistRepository
+	items      domain.ItemRepository
+}
+
+// NewGetWatchlistItemsUseCase creates a new GetWatchlistItemsUseCase
+func NewGetWatchlistItemsUseCase(watchlists domain.WatchlistRepository, items domain.ItemRepository) *GetWatchlistItemsUseCase {
+	return &GetWatchlistItemsUseCase{watchlists: watchlists, items: items}
+}
+
+// Execute returns the current prices and margins of every item in the
+// watchlist, in the watchlist's order, with one item lookup for all of them
+func (uc *GetWatchlistItemsUseCase) Execute(ctx context.Context, userID, id string) ([]domain.WatchlistItem, error) {
+	ctx, span := tracer.Start(ctx, "GetWatchlistItemsUseCase.Execute",
+		trace.WithAttributes(attribute.String("watchlist.id", id)))
+	defer span.End()
+
+	watchlist, err := uc.watchlists.GetWatchlist(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := uc.items.GetItemsByIDs(ctx, watchlist.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.WatchlistItem, 0, len(watchlist.ItemIDs))
+	for _, itemID := range watchlist.ItemIDs {
+		var price *domain.ItemPrice
+		if found, ok := prices[itemID]; ok {
+			price = &found
+		}
+		items = append(items, domain.NewWatchlistItem(itemID, price))
+	}
+
+	span.SetAttributes(
+		attribute.Int("items.requested", len(watchlist.ItemIDs)),
+		attribute.Int("items.found", len(prices)),
+	)
+	return items, nil
+}
@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// importBatchSize bounds how many accepted rows are buffered before being stored
+	importBatchSize = 5000
+	// maxReportedRejections caps the rejections kept in the summary
+	maxReportedRejections = 1000
+	maxImportItemID       = 10000000
+)
+
+// osrsLaunch is the earliest plausible Grand Exchange price date
+var osrsLaunch = time.Date(2013, time.February, 22, 0, 0, 0, 0, time.UTC)
+
+// ImportPriceHistoryUseCase handles bulk-loading historical prices from dumps
+type ImportPriceHistoryUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewImportPriceHistoryUseCase creates a new ImportPriceHistoryUseCase
+func NewImportPriceHistoryUseCase(repo domain.ItemRepository) *ImportPriceHistoryUseCase {
+	return &ImportPriceHistoryUseCase{repo: repo}
+}
+
+// historyKey identifies a price observation for de-duplication; at 16 bytes
+// a key, the set over a whole dump stays small next to the rows themselves
+type historyKey struct {
+	itemID int
+	date   int64
+}
+
+// Execute validates every record, drops duplicate (item_id, timestamp) pairs
+// and stores the rest in batches. With dryRun nothing is stored.
+//
+// Duplicates are tracked across the whole run, so a dry run counts them all;
+// rows already stored by an earlier import are skipped by the repository and
+// end up in Accepted but not in Inserted. Errors reading the source wrap
+// domain.ErrInvalidImport; on any error the summary holds the counts so far,
+// and the batches already flushed stay stored.
+func (uc *ImportPriceHistoryUseCase) Execute(ctx context.Context, source domain.HistoryRecordSource, dryRun bool) (domain.ImportSummary, error) {
+	ctx, span := tracer.Start(ctx, "ImportPriceHistoryUseCase.Execute",
+		trace.WithAttributes(attribute.Bool("import.dry_run", dryRun)))
+	defer span.End()
+
+	start := time.Now()
+	summary := domain.ImportSummary{DryRun: dryRun, Rejections: []domain.ImportRejection{}}
+	seen := make(map[historyKey]bool, importBatchSize)
+	batch := make([]domain.PriceHistory, 0, importBatchSize)
+
+	flush := func() error {
+		if dryRun || len(batch) == 0 {
+			batch = batch[:0]
+			return nil
+		}
+		inserted, err := uc.repo.SavePriceHistoryBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		summary.Inserted += inserted
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, fmt.Errorf("%w: %w", domain.ErrInvalidImport, err)
+		}
+		summary.Rows++
+
+		if reason := validateImportRecord(record, time.Now()); reason != "" {
+			summary.Rejected++
+			if len(summary.Rejections) < maxReportedRejections {
+				summary.Rejections = append(summary.Rejections, domain.ImportRejection{Line: record.Line, Reason: reason})
+			}
+			continue
+		}
+
+		key := historyKey{itemID: record.Entry.ItemID, date: record.Entry.Date.UnixNano()}
+		if seen[key] {
+			summary.Duplicates++
+			continue
+		}
+		seen[key] = true
+		summary.Accepted++
+
+		batch = append(batch, record.Entry)
+		if len(batch) >= importBatchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return summary, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("import.rows", summary.Rows),
+		attribute.Int("import.accepted", summary.Accepted),
+		attribute.Int("import.rejected", summary.Rejected),
+		attribute.Int("import.inserted", summary.Inserted),
+	)
+	slog.InfoContext(ctx, "price history import finished",
+		"dry_run", dryRun,
+		"rows", summary.Rows,
+		"accepted", summary.Accepted,
+		"duplicates", summary.Duplicates,
+		"rejected", summary.Rejected,
+		"inserted", summary.Inserted,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	return summary, nil
+}
+
+// validateImportRecord returns why a record is rejected, or "" when it is valid
+func validateImportRecord(record domain.HistoryImportRecord, now time.Time) string {
+	if record.Err != nil {
+		return record.Err.Error()
+	}
+
+	entry := record.Entry
+	switch {
+	case entry.ItemID < 1 || entry.ItemID > maxImportItemID:
+		return fmt.Sprintf("item_id %d out of valid range", entry.ItemID)
+	case entry.Price <= 0:
+		return fmt.Sprintf("price %d must be positive", entry.Price)
+	case entry.Date.IsZero():
+		return "timestamp is required"
+	case entry.Date.Before(osrsLaunch):
+		return fmt.Sprintf("timestamp %s is before the Grand Exchange existed", entry.Date.Format(time.RFC3339))
+	case entry.Date.After(now.Add(time.Hour)):
+		return fmt.Sprintf("timestamp %s is in the future", entry.Date.Format(time.RFC3339))
+	}
+	return ""
+}
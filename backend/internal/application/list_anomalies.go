@@ -0,0 +1,65 @@
+package application
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ListAnomaliesUseCase handles listing the items flagged by the anomaly detector
+type ListAnomaliesUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewListAnomaliesUseCase creates a new ListAnomaliesUseCase
+func NewListAnomaliesUseCase(repo domain.ItemRepository) *ListAnomaliesUseCase {
+	return &ListAnomaliesUseCase{repo: repo}
+}
+
+// Execute returns up to limit flagged items, strongest flag first. With
+// anomalyType set, only items carrying that flag are returned.
+func (uc *ListAnomaliesUseCase) Execute(ctx context.Context, anomalyType domain.AnomalyType, limit int) ([]domain.ItemPrice, error) {
+	ctx, span := tracer.Start(ctx, "ListAnomaliesUseCase.Execute",
+		trace.WithAttributes(attribute.String("anomalies.type", string(anomalyType))))
+	defer span.End()
+
+	items, err := uc.repo.GetAllItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flagged := make([]domain.ItemPrice, 0)
+	for _, item := range items {
+		if !item.Suspicious() || anomalyType != "" && !item.HasAnomaly(anomalyType) {
+			continue
+		}
+		flagged = append(flagged, item)
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		a, b := strongestAnomaly(flagged[i]), strongestAnomaly(flagged[j])
+		if a != b {
+			return a > b
+		}
+		return flagged[i].ItemID < flagged[j].ItemID
+	})
+	if limit > 0 && len(flagged) > limit {
+		flagged = flagged[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("anomalies.count", len(flagged)))
+	return flagged, nil
+}
+
+// strongestAnomaly is the largest absolute score of the item's flags
+func strongestAnomaly(item domain.ItemPrice) float64 {
+	strongest := 0.0
+	for _, anomaly := range item.Anomalies {
+		strongest = math.Max(strongest, math.Abs(anomaly.Score))
+	}
+	return strongest
+}
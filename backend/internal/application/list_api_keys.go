@@ -0,0 +1,25 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// ListAPIKeysUseCase handles listing the issued API keys
+type ListAPIKeysUseCase struct {
+	repo domain.APIKeyRepository
+}
+
+// NewListAPIKeysUseCase creates a new ListAPIKeysUseCase
+func NewListAPIKeysUseCase(repo domain.APIKeyRepository) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{repo: repo}
+}
+
+// Execute returns every key, revoked ones included, oldest first
+func (uc *ListAPIKeysUseCase) Execute(ctx context.Context) ([]domain.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "ListAPIKeysUseCase.Execute")
+	defer span.End()
+
+	return uc.repo.ListAPIKeys(ctx)
+}
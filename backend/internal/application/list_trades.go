@@ -0,0 +1,33 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ListTradesUseCase handles listing a user's trades
+type ListTradesUseCase struct {
+	repo domain.TradeRepository
+}
+
+// NewListTradesUseCase creates a new ListTradesUseCase
+func NewListTradesUseCase(repo domain.TradeRepository) *ListTradesUseCase {
+	return &ListTradesUseCase{repo: repo}
+}
+
+// Execute returns the user's trades matching filter, oldest first
+func (uc *ListTradesUseCase) Execute(ctx context.Context, userID string, filter domain.TradeFilter) ([]domain.Trade, error) {
+	ctx, span := tracer.Start(ctx, "ListTradesUseCase.Execute",
+		trace.WithAttributes(attribute.Int("item.id", filter.ItemID)))
+	defer span.End()
+
+	trades, err := uc.repo.ListTrades(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("trades.count", len(trades)))
+	return trades, nil
+}
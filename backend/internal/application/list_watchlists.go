@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ListWatchlistsUseCase handles listing a user's watchlists
+type ListWatchlistsUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewListWatchlistsUseCase creates a new ListWatchlistsUseCase
+func NewListWatchlistsUseCase(repo domain.WatchlistRepository) *ListWatchlistsUseCase {
+	return &ListWatchlistsUseCase{repo: repo}
+}
+
+// Execute returns the user's watchlists, oldest first
+func (uc *ListWatchlistsUseCase) Execute(ctx context.Context, userID string) ([]domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "ListWatchlistsUseCase.Execute",
+		trace.WithAttributes(attribute.String("user.id", userID)))
+	defer span.End()
+
+	return uc.repo.ListWatchlists(ctx, userID)
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogTradesUseCase handles recording trades in a user's journal
+type LogTradesUseCase struct {
+	repo domain.TradeRepository
+}
+
+// NewLogTradesUseCase creates a new LogTradesUseCase
+func NewLogTradesUseCase(repo domain.TradeRepository) *LogTradesUseCase {
+	return &LogTradesUseCase{repo: repo}
+}
+
+// Execute stores the trades, which must already be validated, for the user
+// and returns them with their IDs. Either every trade is stored or none.
+func (uc *LogTradesUseCase) Execute(ctx context.Context, userID string, trades []domain.Trade) ([]domain.Trade, error) {
+	ctx, span := tracer.Start(ctx, "LogTradesUseCase.Execute",
+		trace.WithAttributes(attribute.Int("trades.count", len(trades))))
+	defer span.End()
+
+	now := time.Now().UTC()
+	logged := make([]domain.Trade, len(trades))
+	for i, trade := range trades {
+		id, err := randomHex(8)
+		if err != nil {
+			return nil, err
+		}
+		trade.ID = id
+		trade.UserID = userID
+		trade.TradedAt = trade.TradedAt.UTC()
+		// Offset creation times so trades of one batch keep their order at equal traded_at
+		trade.CreatedAt = now.Add(time.Duration(i) * time.Microsecond)
+		logged[i] = trade
+	}
+
+	if err := uc.repo.CreateTrades(ctx, logged); err != nil {
+		return nil, err
+	}
+	return logged, nil
+}
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LoginUserUseCase handles exchanging credentials for a session
+type LoginUserUseCase struct {
+	users      domain.UserRepository
+	hasher     domain.PasswordHasher
+	tokens     domain.AccessTokenIssuer
+	refreshTTL time.Duration
+
+	// dummyHash is compared against when the email is unknown, so both
+	// failures take as long and do not reveal which emails have accounts
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewLoginUserUseCase creates a new LoginUserUseCase
+func NewLoginUserUseCase(users domain.UserRepository, hasher domain.PasswordHasher, tokens domain.AccessTokenIssuer, refreshTTL time.Duration) *LoginUserUseCase {
+	return &LoginUserUseCase{users: users, hasher: hasher, tokens: tokens, refreshTTL: refreshTTL}
+}
+
+// Execute checks the credentials and starts a session. Wrong emails and
+// wrong passwords both return domain.ErrInvalidCredentials.
+func (uc *LoginUserUseCase) Execute(ctx context.Context, email, password string) (domain.Session, error) {
+	ctx, span := tracer.Start(ctx, "LoginUserUseCase.Execute")
+	defer span.End()
+
+	user, err := uc.users.GetUserByEmail(ctx, domain.NormalizeEmail(email))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		uc.dummyOnce.Do(func() {
+			uc.dummyHash, _ = uc.hasher.Hash("not a real password")
+		})
+		uc.hasher.Compare(uc.dummyHash, password)
+		return domain.Session{}, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return domain.Session{}, err
+	}
+	span.SetAttributes(attribute.String("user.id", user.ID))
+
+	if err := uc.hasher.Compare(user.PasswordHash, password); err != nil {
+		return domain.Session{}, err
+	}
+
+	session, refresh, err := newSession(uc.tokens, user.ID, time.Now().UTC(), uc.refreshTTL)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if err := uc.users.SaveRefreshToken(ctx, refresh); err != nil {
+		return domain.Session{}, err
+	}
+	return session, nil
+}
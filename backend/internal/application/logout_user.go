@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// LogoutUserUseCase handles ending sessions
+type LogoutUserUseCase struct {
+	users domain.UserRepository
+}
+
+// NewLogoutUserUseCase creates a new LogoutUserUseCase
+func NewLogoutUserUseCase(users domain.UserRepository) *LogoutUserUseCase {
+	return &LogoutUserUseCase{users: users}
+}
+
+// Execute revokes the refresh token, or every session of its user when
+// everywhere is set. Unknown tokens are ignored so logging out is idempotent.
+// Access tokens already issued stay valid until they expire.
+func (uc *LogoutUserUseCase) Execute(ctx context.Context, refreshToken string, everywhere bool) error {
+	ctx, span := tracer.Start(ctx, "LogoutUserUseCase.Execute")
+	defer span.End()
+
+	stored, err := uc.users.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(refreshToken))
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if everywhere {
+		return uc.users.RevokeUserRefreshTokens(ctx, stored.UserID, now)
+	}
+	return uc.users.RevokeRefreshToken(ctx, stored.ID, now)
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OptimizeAllocationUseCase handles suggesting how to spread cash over the GE slots
+type OptimizeAllocationUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewOptimizeAllocationUseCase creates a new OptimizeAllocationUseCase
+func NewOptimizeAllocationUseCase(repo domain.ItemRepository) *OptimizeAllocationUseCase {
+	return &OptimizeAllocationUseCase{repo: repo}
+}
+
+// Execute suggests the flips and quantities with the highest expected
+// post-tax profit for req. buyLimits holds a user's buy limit status of the
+// items they bought recently; nil assumes every limit is untouched.
+func (uc *OptimizeAllocationUseCase) Execute(ctx context.Context, req domain.AllocationRequest, buyLimits map[int]domain.BuyLimitStatus) (domain.AllocationPlan, error) {
+	ctx, span := tracer.Start(ctx, "OptimizeAllocationUseCase.Execute",
+		trace.WithAttributes(
+			attribute.Int("allocation.cash", req.Cash),
+			attribute.Int("allocation.slots", req.Slots),
+			attribute.String("allocation.risk", string(req.Risk)),
+			attribute.Float64("allocation.horizon_hours", req.Horizon.Hours()),
+			attribute.String("allocation.category", string(req.Category)),
+			attribute.String("allocation.tag", req.Tag),
+		))
+	defer span.End()
+
+	items, err := uc.repo.GetAllItems(ctx)
+	if err != nil {
+		return domain.AllocationPlan{}, err
+	}
+
+	category := domain.ItemFilter{Category: req.Category, Tag: req.Tag}
+	flips := make([]domain.Flip, 0, len(items))
+	for _, item := range items {
+		if item.Low <= 0 || item.High <= 0 || !category.Matches(item) {
+			continue
+		}
+		flip := domain.NewFlip(item)
+		if buyLimits != nil {
+			status, bought := buyLimits[item.ItemID]
+			flip.ApplyBuyLimit(status, bought)
+		}
+		flips = append(flips, flip)
+	}
+
+	plan := domain.OptimizeAllocation(flips, req)
+	span.SetAttributes(
+		attribute.Int("allocation.items", len(plan.Allocations)),
+		attribute.Int("allocation.expected_profit", plan.ExpectedProfit),
+	)
+	return plan, nil
+}
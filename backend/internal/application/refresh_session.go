@@ -0,0 +1,57 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RefreshSessionUseCase handles exchanging a refresh token for a new session
+type RefreshSessionUseCase struct {
+	users      domain.UserRepository
+	tokens     domain.AccessTokenIssuer
+	refreshTTL time.Duration
+}
+
+// NewRefreshSessionUseCase creates a new RefreshSessionUseCase
+func NewRefreshSessionUseCase(users domain.UserRepository, tokens domain.AccessTokenIssuer, refreshTTL time.Duration) *RefreshSessionUseCase {
+	return &RefreshSessionUseCase{users: users, tokens: tokens, refreshTTL: refreshTTL}
+}
+
+// Execute rotates the refresh token: the presented one is revoked and a new
+// session is returned. Presenting a token that was already rotated means it
+// leaked or was replayed, so every session of its user is ended.
+func (uc *RefreshSessionUseCase) Execute(ctx context.Context, refreshToken string) (domain.Session, error) {
+	ctx, span := tracer.Start(ctx, "RefreshSessionUseCase.Execute")
+	defer span.End()
+
+	stored, err := uc.users.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(refreshToken))
+	if err != nil {
+		return domain.Session{}, err
+	}
+	span.SetAttributes(attribute.String("user.id", stored.UserID))
+
+	now := time.Now().UTC()
+	if stored.RevokedAt != nil && stored.ReplacedBy != "" {
+		slog.WarnContext(ctx, "rotated refresh token reused; ending all sessions of the user", "user_id", stored.UserID)
+		if err := uc.users.RevokeUserRefreshTokens(ctx, stored.UserID, now); err != nil {
+			return domain.Session{}, err
+		}
+		return domain.Session{}, domain.ErrInvalidToken
+	}
+	if !stored.Usable(now) {
+		return domain.Session{}, domain.ErrInvalidToken
+	}
+
+	session, next, err := newSession(uc.tokens, stored.UserID, now, uc.refreshTTL)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if err := uc.users.RotateRefreshToken(ctx, stored.ID, next, now); err != nil {
+		return domain.Session{}, err
+	}
+	return session, nil
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RegisterUserUseCase handles creating user accounts
+type RegisterUserUseCase struct {
+	users  domain.UserRepository
+	hasher domain.PasswordHasher
+}
+
+// NewRegisterUserUseCase creates a new RegisterUserUseCase
+func NewRegisterUserUseCase(users domain.UserRepository, hasher domain.PasswordHasher) *RegisterUserUseCase {
+	return &RegisterUserUseCase{users: users, hasher: hasher}
+}
+
+// Execute creates an account for email, which must already be validated.
+// It returns domain.ErrEmailTaken when the email has an account.
+func (uc *RegisterUserUseCase) Execute(ctx context.Context, email, password string) (domain.User, error) {
+	ctx, span := tracer.Start(ctx, "RegisterUserUseCase.Execute")
+	defer span.End()
+
+	id, err := randomHex(8)
+	if err != nil {
+		return domain.User{}, err
+	}
+	span.SetAttributes(attribute.String("user.id", id))
+
+	hash, err := uc.hasher.Hash(password)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user := domain.User{
+		ID:           id,
+		Email:        domain.NormalizeEmail(email),
+		PasswordHash: hash,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := uc.users.CreateUser(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
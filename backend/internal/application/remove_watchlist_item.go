@@ -0,0 +1,32 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RemoveWatchlistItemUseCase handles removing an item from a watchlist
+type RemoveWatchlistItemUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewRemoveWatchlistItemUseCase creates a new RemoveWatchlistItemUseCase
+func NewRemoveWatchlistItemUseCase(repo domain.WatchlistRepository) *RemoveWatchlistItemUseCase {
+	return &RemoveWatchlistItemUseCase{repo: repo}
+}
+
+// Execute removes the item and returns the watchlist updated
+func (uc *RemoveWatchlistItemUseCase) Execute(ctx context.Context, userID, id string, itemID int) (*domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "RemoveWatchlistItemUseCase.Execute",
+		trace.WithAttributes(
+			attribute.String("watchlist.id", id),
+			attribute.Int("item.id", itemID),
+		))
+	defer span.End()
+
+	return uc.repo.RemoveWatchlistItem(ctx, userID, id, itemID, time.Now().UTC())
+}
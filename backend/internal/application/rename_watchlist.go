@@ -0,0 +1,29 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RenameWatchlistUseCase handles renaming watchlists
+type RenameWatchlistUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewRenameWatchlistUseCase creates a new RenameWatchlistUseCase
+func NewRenameWatchlistUseCase(repo domain.WatchlistRepository) *RenameWatchlistUseCase {
+	return &RenameWatchlistUseCase{repo: repo}
+}
+
+// Execute renames one of the user's watchlists and returns it updated
+func (uc *RenameWatchlistUseCase) Execute(ctx context.Context, userID, id, name string) (*domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "RenameWatchlistUseCase.Execute",
+		trace.WithAttributes(attribute.String("watchlist.id", id)))
+	defer span.End()
+
+	return uc.repo.RenameWatchlist(ctx, userID, id, name, time.Now().UTC())
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReorderWatchlistUseCase handles changing the order of a watchlist
+type ReorderWatchlistUseCase struct {
+	repo domain.WatchlistRepository
+}
+
+// NewReorderWatchlistUseCase creates a new ReorderWatchlistUseCase
+func NewReorderWatchlistUseCase(repo domain.WatchlistRepository) *ReorderWatchlistUseCase {
+	return &ReorderWatchlistUseCase{repo: repo}
+}
+
+// Execute puts the watchlist's items in the given order, which must list
+// each of them exactly once, and returns the watchlist updated
+func (uc *ReorderWatchlistUseCase) Execute(ctx context.Context, userID, id string, itemIDs []int) (*domain.Watchlist, error) {
+	ctx, span := tracer.Start(ctx, "ReorderWatchlistUseCase.Execute",
+		trace.WithAttributes(attribute.String("watchlist.id", id)))
+	defer span.End()
+
+	return uc.repo.ReorderWatchlist(ctx, userID, id, itemIDs, time.Now().UTC())
+}
@@ -0,0 +1,29 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RevokeAPIKeyUseCase handles revoking API keys
+type RevokeAPIKeyUseCase struct {
+	repo domain.APIKeyRepository
+}
+
+// NewRevokeAPIKeyUseCase creates a new RevokeAPIKeyUseCase
+func NewRevokeAPIKeyUseCase(repo domain.APIKeyRepository) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{repo: repo}
+}
+
+// Execute revokes the key. Keys are kept, with their usage, for auditing.
+func (uc *RevokeAPIKeyUseCase) Execute(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "RevokeAPIKeyUseCase.Execute",
+		trace.WithAttributes(attribute.String("api_key.id", id)))
+	defer span.End()
+
+	return uc.repo.RevokeAPIKey(ctx, id, time.Now().UTC())
+}
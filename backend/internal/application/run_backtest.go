@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/backtest"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxBacktestItems bounds the items replayed by one backtest
+const MaxBacktestItems = 50
+
+// BacktestRequest describes a backtest: the strategy, the items and days of
+// price history replayed, and the simulated account
+type BacktestRequest struct {
+	Strategy backtest.StrategyConfig `json:"strategy"`
+	// ItemIDs are the items replayed; when empty, the MaxBacktestItems
+	// items with the highest current volume of at least MinVolume are
+	ItemIDs   []int `json:"item_ids,omitempty"`
+	MinVolume int   `json:"min_volume,omitempty"`
+	Days      int   `json:"days"`
+	backtest.Config
+}
+
+// RunBacktestUseCase handles replaying stored price history through a strategy
+type RunBacktestUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewRunBacktestUseCase creates a new RunBacktestUseCase
+func NewRunBacktestUseCase(repo domain.ItemRepository) *RunBacktestUseCase {
+	return &RunBacktestUseCase{repo: repo}
+}
+
+// Execute runs the backtest described by req. It returns an error wrapping
+// backtest.ErrUnknownStrategy when the strategy does not exist.
+func (uc *RunBacktestUseCase) Execute(ctx context.Context, req BacktestRequest) (backtest.Report, error) {
+	ctx, span := tracer.Start(ctx, "RunBacktestUseCase.Execute",
+		trace.WithAttributes(
+			attribute.String("backtest.strategy", req.Strategy.Name),
+			attribute.Int("backtest.days", req.Days),
+			attribute.Int("backtest.items_requested", len(req.ItemIDs)),
+		))
+	defer span.End()
+
+	strategy, err := backtest.NewStrategy(req.Strategy, req.Slots)
+	if err != nil {
+		return backtest.Report{}, err
+	}
+
+	items, err := uc.backtestItems(ctx, req)
+	if err != nil {
+		return backtest.Report{}, err
+	}
+
+	var bars []backtest.Bar
+	for id := range items {
+		history, err := uc.repo.GetPriceHistory(ctx, id, req.Days)
+		if err != nil {
+			return backtest.Report{}, err
+		}
+		for _, entry := range history {
+			bars = append(bars, backtest.Bar{ItemID: id, Time: entry.Date, Price: entry.Price})
+		}
+	}
+
+	report := backtest.Run(strategy, items, bars, req.Config)
+	span.SetAttributes(
+		attribute.Int("backtest.items", report.Items),
+		attribute.Int("backtest.bars", report.Bars),
+		attribute.Int("backtest.trades", report.Trades),
+	)
+	return report, nil
+}
+
+// backtestItems returns the requested items, or the most traded ones
+func (uc *RunBacktestUseCase) backtestItems(ctx context.Context, req BacktestRequest) (map[int]domain.ItemPrice, error) {
+	if len(req.ItemIDs) > 0 {
+		items, err := uc.repo.GetItemsByIDs(ctx, req.ItemIDs)
+		if err != nil {
+			return nil, err
+		}
+		// Items without a current price may still have history
+		for _, id := range req.ItemIDs {
+			if _, ok := items[id]; !ok {
+				items[id] = domain.ItemPrice{ItemID: id}
+			}
+		}
+		return items, nil
+	}
+
+	all, err := uc.repo.GetAllItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]domain.ItemPrice, 0, len(all))
+	for _, item := range all {
+		if item.Volume >= req.MinVolume {
+			candidates = append(candidates, item)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Volume != candidates[j].Volume {
+			return candidates[i].Volume > candidates[j].Volume
+		}
+		return candidates[i].ItemID < candidates[j].ItemID
+	})
+
+	items := make(map[int]domain.ItemPrice, MaxBacktestItems)
+	for _, item := range candidates[:min(len(candidates), MaxBacktestItems)] {
+		items[item.ItemID] = item
+	}
+	return items, nil
+}
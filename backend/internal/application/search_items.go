@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SearchItemsUseCase handles searching items by name
@@ -18,6 +20,10 @@ func NewSearchItemsUseCase(repo domain.ItemRepository) *SearchItemsUseCase {
 
 // Execute searches for items matching the query
 func (uc *SearchItemsUseCase) Execute(ctx context.Context, query string) ([]domain.ItemPrice, error) {
+	ctx, span := tracer.Start(ctx, "SearchItemsUseCase.Execute",
+		trace.WithAttributes(attribute.String("search.query", query)))
+	defer span.End()
+
 	if query == "" {
 		// If no query, return all items
 		return uc.repo.GetAllItems(ctx)
@@ -31,11 +37,21 @@ func (uc *SearchItemsUseCase) Execute(ctx context.Context, query string) ([]doma
 	return items, nil
 }
 
-// ExecutePaginated searches for items with pagination
-func (uc *SearchItemsUseCase) ExecutePaginated(ctx context.Context, query string, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
-	if query == "" {
+// ExecutePaginated searches for items matching filter with pagination
+func (uc *SearchItemsUseCase) ExecutePaginated(ctx context.Context, filter domain.ItemFilter, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
+	ctx, span := tracer.Start(ctx, "SearchItemsUseCase.ExecutePaginated",
+		trace.WithAttributes(
+			attribute.String("search.query", filter.Query),
+			attribute.String("search.category", string(filter.Category)),
+			attribute.String("search.tag", filter.Tag),
+			attribute.Int("pagination.page", params.Page),
+			attribute.Int("pagination.limit", params.Limit),
+		))
+	defer span.End()
+
+	if filter.IsZero() {
 		return uc.repo.GetAllItemsPaginated(ctx, params)
 	}
 
-	return uc.repo.SearchItemsPaginated(ctx, query, params)
+	return uc.repo.SearchItemsPaginated(ctx, filter, params)
 }
@@ -0,0 +1,42 @@
+package application
+
+import (
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// newSession signs an access token for userID and creates the refresh token
+// that goes with it. The refresh token is returned unsaved, for the caller
+// to store or rotate in.
+func newSession(tokens domain.AccessTokenIssuer, userID string, now time.Time, refreshTTL time.Duration) (domain.Session, domain.RefreshToken, error) {
+	accessToken, accessExpiresAt, err := tokens.Issue(userID, now)
+	if err != nil {
+		return domain.Session{}, domain.RefreshToken{}, err
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return domain.Session{}, domain.RefreshToken{}, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return domain.Session{}, domain.RefreshToken{}, err
+	}
+
+	refresh := domain.RefreshToken{
+		ID:        id,
+		UserID:    userID,
+		Hash:      domain.HashRefreshToken(secret),
+		CreatedAt: now,
+		ExpiresAt: now.Add(refreshTTL),
+	}
+	session := domain.Session{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     secret,
+		RefreshExpiresAt: refresh.ExpiresAt,
+		TokenType:        "Bearer",
+	}
+	return session, refresh, nil
+}
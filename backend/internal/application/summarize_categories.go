@@ -0,0 +1,32 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SummarizeCategoriesUseCase handles aggregating item figures per category
+type SummarizeCategoriesUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewSummarizeCategoriesUseCase creates a new SummarizeCategoriesUseCase
+func NewSummarizeCategoriesUseCase(repo domain.ItemRepository) *SummarizeCategoriesUseCase {
+	return &SummarizeCategoriesUseCase{repo: repo}
+}
+
+// Execute returns the statistics of every category, in the order of domain.Categories
+func (uc *SummarizeCategoriesUseCase) Execute(ctx context.Context) ([]domain.CategoryStats, error) {
+	ctx, span := tracer.Start(ctx, "SummarizeCategoriesUseCase.Execute")
+	defer span.End()
+
+	items, err := uc.repo.GetAllItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("categories.items", len(items)))
+	return domain.SummarizeCategories(items), nil
+}
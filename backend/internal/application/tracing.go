@@ -0,0 +1,6 @@
+package application
+
+import "go.opentelemetry.io/otel"
+
+// tracer creates spans for use case executions
+var tracer = otel.Tracer("github.com/gabv/osrs-good-to-flip/backend/internal/application")
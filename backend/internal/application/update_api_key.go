@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// APIKeyChanges lists the fields of an API key to change; nil fields are kept
+type APIKeyChanges struct {
+	Name       *string
+	RateLimit  *int
+	DailyQuota *int
+}
+
+// UpdateAPIKeyUseCase handles renaming keys and changing their limits
+type UpdateAPIKeyUseCase struct {
+	repo domain.APIKeyRepository
+}
+
+// NewUpdateAPIKeyUseCase creates a new UpdateAPIKeyUseCase
+func NewUpdateAPIKeyUseCase(repo domain.APIKeyRepository) *UpdateAPIKeyUseCase {
+	return &UpdateAPIKeyUseCase{repo: repo}
+}
+
+// Execute applies changes to the key and returns it updated
+func (uc *UpdateAPIKeyUseCase) Execute(ctx context.Context, id string, changes APIKeyChanges) (*domain.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "UpdateAPIKeyUseCase.Execute",
+		trace.WithAttributes(attribute.String("api_key.id", id)))
+	defer span.End()
+
+	key, err := uc.repo.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Name != nil {
+		key.Name = *changes.Name
+	}
+	if changes.RateLimit != nil {
+		key.RateLimit = *changes.RateLimit
+	}
+	if changes.DailyQuota != nil {
+		key.DailyQuota = *changes.DailyQuota
+	}
+
+	if err := uc.repo.UpdateAPIKey(ctx, *key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
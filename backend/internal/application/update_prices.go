@@ -3,45 +3,83 @@ package application
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // UpdatePricesUseCase handles updating item prices
 type UpdatePricesUseCase struct {
 	provider domain.PriceProvider
 	repo     domain.ItemRepository
+	catalog  *domain.Catalog
+
+	statusMu sync.RWMutex
+	status   domain.UpdateStatus
+
+	listenersMu sync.RWMutex
+	listeners   []func(ctx context.Context)
 }
 
-// NewUpdatePricesUseCase creates a new UpdatePricesUseCase
-func NewUpdatePricesUseCase(provider domain.PriceProvider, repo domain.ItemRepository) *UpdatePricesUseCase {
+// NewUpdatePricesUseCase creates a new UpdatePricesUseCase. The catalog
+// categorizes every updated item; a nil catalog uses the name heuristics alone.
+func NewUpdatePricesUseCase(provider domain.PriceProvider, repo domain.ItemRepository, catalog *domain.Catalog) *UpdatePricesUseCase {
 	return &UpdatePricesUseCase{
 		provider: provider,
 		repo:     repo,
+		catalog:  catalog,
 	}
 }
 
 // Execute fetches latest prices and updates the repository
 func (uc *UpdatePricesUseCase) Execute(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "UpdatePricesUseCase.Execute")
+	defer span.End()
+
+	start := time.Now()
+
 	// Fetch latest prices from provider
 	snapshots, err := uc.provider.FetchLatestPrices(ctx)
 	if err != nil {
+		uc.recordError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "fetch latest prices failed")
 		return err
 	}
+	slog.DebugContext(ctx, "fetched latest prices", "snapshot_count", len(snapshots))
 
-	// Fetch item names mapping (only if we have new items)
-	itemNames, err := uc.provider.FetchItemNames(ctx)
+	// Fetch item mapping for names of new items and buy limits
+	mapping, err := uc.provider.FetchItemMapping(ctx)
 	if err != nil {
-		log.Printf("Warning: Failed to fetch item names: %v", err)
-		// Continue without names - will use existing names or fallback
-		itemNames = make(map[int]string)
+		slog.WarnContext(ctx, "failed to fetch item mapping", "error", err)
+		uc.recordError(err)
+		span.RecordError(err)
+		// Continue without the mapping - will use existing names and limits or fallback
+		mapping = make(map[int]domain.ItemMapping)
+	}
+
+	// Load existing items in one call to preserve names and averages
+	ids := make([]int, 0, len(snapshots))
+	for itemID := range snapshots {
+		ids = append(ids, itemID)
+	}
+	existingItems, err := uc.repo.GetItemsByIDs(ctx, ids)
+	if err != nil {
+		uc.recordError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load existing items failed")
+		return err
 	}
 
 	// Convert map to ItemPrice slice
 	items := make([]domain.ItemPrice, 0, len(snapshots))
 	now := time.Now()
+	newItems := 0
+	flagged := 0
 
 	for itemID, snap := range snapshots {
 		// choose a representative price; we use High as current price
@@ -55,8 +93,7 @@ func (uc *UpdatePricesUseCase) Execute(ctx context.Context) error {
 			continue
 		}
 
-		// Get existing item to preserve name and averages
-		existing, _ := uc.repo.GetItemByID(ctx, itemID)
+		existing, exists := existingItems[itemID]
 
 		item := domain.ItemPrice{
 			ItemID:    itemID,
@@ -67,7 +104,13 @@ func (uc *UpdatePricesUseCase) Execute(ctx context.Context) error {
 			UpdatedAt: now,
 		}
 
-		if existing != nil {
+		// Buy limits occasionally change, so refresh them for every item
+		item.BuyLimit = existing.BuyLimit
+		if info, ok := mapping[itemID]; ok && info.BuyLimit > 0 {
+			item.BuyLimit = info.BuyLimit
+		}
+
+		if exists {
 			// Preserve existing name and averages
 			item.Name = existing.Name
 			item.Avg24h = existing.Avg24h
@@ -76,20 +119,94 @@ func (uc *UpdatePricesUseCase) Execute(ctx context.Context) error {
 			item.Trend = domain.CalculateTrend(price, existing.Avg24h)
 		} else {
 			// For new items, try to get name from mapping
-			if name, ok := itemNames[itemID]; ok && name != "" {
-				item.Name = name
+			if info, ok := mapping[itemID]; ok && info.Name != "" {
+				item.Name = info.Name
 			} else {
 				// Fallback: use placeholder name
 				item.Name = fmt.Sprintf("Item %d", itemID)
 			}
+			newItems++
 			// Set defaults for new items
 			item.Avg24h = price
 			item.Avg7d = price
 			item.Trend = domain.TrendFlat
 		}
 
+		// Categorize every time, so mapping changes reach stored items
+		uc.catalog.Apply(&item)
+
+		// New items start their rolling statistics from this update
+		domain.DetectAnomalies(existing, &item, now)
+		if item.Suspicious() {
+			flagged++
+		}
+
 		items = append(items, item)
 	}
 
-	return uc.repo.SavePrices(ctx, items)
+	if err := uc.repo.SavePrices(ctx, items); err != nil {
+		uc.recordError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "save prices failed")
+		return err
+	}
+
+	span.SetAttributes(
+		attribute.Int("update.snapshots", len(snapshots)),
+		attribute.Int("update.items", len(items)),
+		attribute.Int("update.new_items", newItems),
+		attribute.Int("update.flagged_items", flagged),
+	)
+
+	uc.recordSuccess(len(items))
+	uc.notifyListeners(ctx)
+	slog.InfoContext(ctx, "saved item prices",
+		"snapshot_count", len(snapshots),
+		"item_count", len(items),
+		"new_item_count", newItems,
+		"flagged_item_count", flagged,
+		"skipped_count", len(snapshots)-len(items),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// OnUpdate registers a function called after every successful update.
+// Listeners run synchronously, so they should return quickly.
+func (uc *UpdatePricesUseCase) OnUpdate(fn func(ctx context.Context)) {
+	uc.listenersMu.Lock()
+	defer uc.listenersMu.Unlock()
+	uc.listeners = append(uc.listeners, fn)
+}
+
+// notifyListeners calls every registered update listener
+func (uc *UpdatePricesUseCase) notifyListeners(ctx context.Context) {
+	uc.listenersMu.RLock()
+	defer uc.listenersMu.RUnlock()
+	for _, fn := range uc.listeners {
+		fn(ctx)
+	}
+}
+
+// Status returns the outcome of the most recent updates
+func (uc *UpdatePricesUseCase) Status() domain.UpdateStatus {
+	uc.statusMu.RLock()
+	defer uc.statusMu.RUnlock()
+	return uc.status
+}
+
+// recordError stores the last error of an update, from the provider or the repository
+func (uc *UpdatePricesUseCase) recordError(err error) {
+	uc.statusMu.Lock()
+	defer uc.statusMu.Unlock()
+	uc.status.LastErrorAt = time.Now()
+	uc.status.LastError = err.Error()
+}
+
+// recordSuccess stores the time and size of the last successful update
+func (uc *UpdatePricesUseCase) recordSuccess(itemCount int) {
+	uc.statusMu.Lock()
+	defer uc.statusMu.Unlock()
+	uc.status.LastSuccessAt = time.Now()
+	uc.status.LastItemCount = itemCount
 }
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ValuePortfolioUseCase handles valuing a bank or inventory snapshot
+type ValuePortfolioUseCase struct {
+	repo domain.ItemRepository
+}
+
+// NewValuePortfolioUseCase creates a new ValuePortfolioUseCase
+func NewValuePortfolioUseCase(repo domain.ItemRepository) *ValuePortfolioUseCase {
+	return &ValuePortfolioUseCase{repo: repo}
+}
+
+// Execute values holdings at current prices and compares them with the
+// price history from 24 hours and 7 days ago. A past price is the latest
+// history entry at most domain.PortfolioHistoryTolerance older than that time.
+func (uc *ValuePortfolioUseCase) Execute(ctx context.Context, holdings []domain.Holding) (domain.Portfolio, error) {
+	holdings = domain.MergeHoldings(holdings)
+
+	ctx, span := tracer.Start(ctx, "ValuePortfolioUseCase.Execute",
+		trace.WithAttributes(attribute.Int("portfolio.items", len(holdings))))
+	defer span.End()
+
+	ids := make([]int, len(holdings))
+	for i, h := range holdings {
+		ids[i] = h.ItemID
+	}
+	items, err := uc.repo.GetItemsByIDs(ctx, ids)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+
+	now := time.Now()
+	past := make([]map[int]domain.PriceHistory, 2)
+	for i, at := range []time.Time{now.Add(-24 * time.Hour), now.AddDate(0, 0, -7)} {
+		past[i], err = uc.repo.GetHistoricalPrices(ctx, ids, at.Add(-domain.PortfolioHistoryTolerance), at)
+		if err != nil {
+			return domain.Portfolio{}, err
+		}
+	}
+
+	portfolio := domain.BuildPortfolio(holdings, items, past[0], past[1])
+	span.SetAttributes(attribute.Int("portfolio.value_mid", portfolio.Value.Mid))
+	return portfolio, nil
+}
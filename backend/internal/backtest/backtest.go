@@ -0,0 +1,217 @@
+// Package backtest replays stored price history through a simulated Grand
+// Exchange to evaluate flipping strategies before risking gold.
+package backtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// Bar is the price of an item at one point of the replay
+type Bar struct {
+	ItemID int       `json:"item_id"`
+	Time   time.Time `json:"time"`
+	Price  int       `json:"price"`
+}
+
+// Order is a GE offer placed by a strategy
+type Order struct {
+	ItemID   int
+	Side     domain.TradeSide
+	Quantity int
+	Price    int // Limit price per item
+}
+
+// Market is a strategy's view of the simulation at the current bar
+type Market interface {
+	Time() time.Time
+	// Cash is the coins not held by open buy offers
+	Cash() int
+	// Holding is the quantity of an item owned and not offered for sale
+	Holding(itemID int) int
+	FreeSlots() int
+	HasOffer(itemID int) bool
+	// BuyLimitRemaining is how many more of an item may be bought now,
+	// counting open buy offers; -1 when the limit is unknown
+	BuyLimitRemaining(itemID int) int
+	// Bars returns the item's bars up to and including the current one, oldest first
+	Bars(itemID int) []Bar
+}
+
+// Strategy decides which offers to place as prices are replayed. OnBar is
+// called for every bar, after the offers open on that item were matched
+// against it; the orders returned, for any item, are matched from that
+// item's first bar after the current time.
+type Strategy interface {
+	Name() string
+	OnBar(m Market, bar Bar) []Order
+}
+
+// Config sets the capital and the fill assumptions of the simulated GE
+type Config struct {
+	Cash  int `json:"cash"`
+	Slots int `json:"slots"` // Offers open at once; default domain.GESlots
+	// FillRatio is the share of an offer's remaining quantity filled on each
+	// bar whose price reaches the offer's price; at least one item fills.
+	// Default 1, filling the whole offer.
+	FillRatio float64 `json:"fill_ratio"`
+	// OrderTTL is how many bars of its item an offer stays open before the
+	// rest is canceled; default 1
+	OrderTTL int `json:"order_ttl"`
+}
+
+// withDefaults fills unset settings
+func (c Config) withDefaults() Config {
+	if c.Slots <= 0 {
+		c.Slots = domain.GESlots
+	}
+	if c.FillRatio <= 0 || c.FillRatio > 1 {
+		c.FillRatio = 1
+	}
+	if c.OrderTTL <= 0 {
+		c.OrderTTL = 1
+	}
+	return c
+}
+
+// CurvePoint is the state of the account after the bars of one time
+type CurvePoint struct {
+	Time           time.Time `json:"time"`
+	Equity         int       `json:"equity"` // Cash plus items at their last price, after tax
+	Cash           int       `json:"cash"`   // Including coins held by open buy offers
+	RealizedProfit int       `json:"realized_profit"`
+	Drawdown       float64   `json:"drawdown"` // Percentage below the highest equity so far
+}
+
+// Report summarizes a backtest
+type Report struct {
+	Strategy     string    `json:"strategy"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Items        int       `json:"items"`
+	Bars         int       `json:"bars"`
+	StartingCash int       `json:"starting_cash"`
+	FinalEquity  int       `json:"final_equity"`
+	Return       float64   `json:"return"` // Percentage of the starting cash
+
+	RealizedProfit int `json:"realized_profit"`
+	Tax            int `json:"tax"`
+	Trades         int `json:"trades"`          // Fills, partial fills included
+	RejectedOrders int `json:"rejected_orders"` // No free slot, cash, holding or buy limit
+
+	// Flips are completed buy and sell pairs, matched FIFO
+	Flips   int     `json:"flips"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"win_rate"` // Percentage of flips with a profit
+
+	MaxDrawdown        int     `json:"max_drawdown"`
+	MaxDrawdownPercent float64 `json:"max_drawdown_percent"`
+
+	OpenPositions []domain.OpenLot `json:"open_positions"`
+	Curve         []CurvePoint     `json:"curve"` // One point per distinct bar time
+}
+
+// Run replays bars through strategy starting with cfg.Cash. items holds the
+// static data of the replayed items; their buy limits apply per
+// domain.BuyLimitWindow and unknown limits do not restrict buys.
+func Run(strategy Strategy, items map[int]domain.ItemPrice, bars []Bar, cfg Config) Report {
+	cfg = cfg.withDefaults()
+	ordered := make([]Bar, len(bars))
+	copy(ordered, bars)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].Time.Equal(ordered[j].Time) {
+			return ordered[i].Time.Before(ordered[j].Time)
+		}
+		return ordered[i].ItemID < ordered[j].ItemID
+	})
+
+	sim := newSimulator(cfg, items)
+	report := Report{
+		Strategy:      strategy.Name(),
+		StartingCash:  cfg.Cash,
+		FinalEquity:   cfg.Cash,
+		Bars:          len(ordered),
+		OpenPositions: []domain.OpenLot{},
+		Curve:         []CurvePoint{},
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < len(ordered); {
+		now := ordered[i].Time
+		sim.now = now
+		for ; i < len(ordered) && ordered[i].Time.Equal(now); i++ {
+			bar := ordered[i]
+			seen[bar.ItemID] = true
+			sim.bars[bar.ItemID] = append(sim.bars[bar.ItemID], bar)
+			sim.match(bar)
+			for _, order := range strategy.OnBar(sim, bar) {
+				if !sim.place(order) {
+					report.RejectedOrders++
+				}
+			}
+		}
+		report.Curve = append(report.Curve, CurvePoint{Time: now, Equity: sim.equity(), Cash: sim.cash + sim.escrow()})
+	}
+	if len(ordered) > 0 {
+		report.Start, report.End = ordered[0].Time, ordered[len(ordered)-1].Time
+		report.FinalEquity = report.Curve[len(report.Curve)-1].Equity
+	}
+	report.Items = len(seen)
+	if cfg.Cash > 0 {
+		report.Return = float64(report.FinalEquity-cfg.Cash) / float64(cfg.Cash) * 100
+	}
+
+	report.Trades = len(sim.fills)
+	match := domain.MatchTrades(sim.fills)
+	report.Flips = len(match.Flips)
+	for _, flip := range match.Flips {
+		report.RealizedProfit += flip.Profit
+		report.Tax += flip.Tax
+		if flip.Profit > 0 {
+			report.Wins++
+		}
+	}
+	if report.Flips > 0 {
+		report.WinRate = float64(report.Wins) / float64(report.Flips) * 100
+	}
+	if match.Open != nil {
+		report.OpenPositions = match.Open
+	}
+
+	addRealizedProfit(report.Curve, match.Flips)
+	report.MaxDrawdown, report.MaxDrawdownPercent = drawdown(report.Curve)
+	return report
+}
+
+// addRealizedProfit sets the cumulative profit of the flips sold by each point
+func addRealizedProfit(curve []CurvePoint, flips []domain.CompletedFlip) {
+	sorted := make([]domain.CompletedFlip, len(flips))
+	copy(sorted, flips)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].SoldAt.Before(sorted[j].SoldAt) })
+
+	realized, next := 0, 0
+	for i := range curve {
+		for ; next < len(sorted) && !sorted[next].SoldAt.After(curve[i].Time); next++ {
+			realized += sorted[next].Profit
+		}
+		curve[i].RealizedProfit = realized
+	}
+}
+
+// drawdown sets each point's drawdown and returns the largest fall from a
+// peak of equity, in gp and as a percentage of that peak
+func drawdown(curve []CurvePoint) (int, float64) {
+	peak, maxDrop, maxPercent := 0, 0, 0.0
+	for i := range curve {
+		peak = max(peak, curve[i].Equity)
+		drop := peak - curve[i].Equity
+		if peak > 0 {
+			curve[i].Drawdown = float64(drop) / float64(peak) * 100
+		}
+		maxDrop = max(maxDrop, drop)
+		maxPercent = max(maxPercent, curve[i].Drawdown)
+	}
+	return maxDrop, maxPercent
+}
@@ -0,0 +1,266 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+var replayStart = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func hour(h int) time.Time {
+	return replayStart.Add(time.Duration(h) * time.Hour)
+}
+
+func bar(itemID, h, price int) Bar {
+	return Bar{ItemID: itemID, Time: hour(h), Price: price}
+}
+
+// barKey identifies the bar a scripted order is placed on
+type barKey struct {
+	itemID int
+	hour   int
+}
+
+// scripted places fixed orders on given bars and records the cash it sees
+type scripted struct {
+	orders map[barKey][]Order
+	cash   map[barKey]int
+}
+
+func (s *scripted) Name() string { return "scripted" }
+
+func (s *scripted) OnBar(m Market, bar Bar) []Order {
+	key := barKey{bar.ItemID, int(bar.Time.Sub(replayStart).Hours())}
+	if s.cash == nil {
+		s.cash = make(map[barKey]int)
+	}
+	s.cash[key] = m.Cash()
+	return s.orders[key]
+}
+
+func limitBuy(itemID, quantity, price int) Order {
+	return Order{ItemID: itemID, Side: domain.TradeBuy, Quantity: quantity, Price: price}
+}
+
+func limitSell(itemID, quantity, price int) Order {
+	return Order{ItemID: itemID, Side: domain.TradeSell, Quantity: quantity, Price: price}
+}
+
+func TestRunFills(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		items    map[int]domain.ItemPrice
+		orders   map[barKey][]Order
+		bars     []Bar
+		trades   int // Fills, partial fills included
+		held     int // Items bought and not sold
+		rejected int
+		cash     int // Including coins held by open buy offers
+	}{
+		{
+			name:   "buy fills at the offer price on the next bar",
+			cfg:    Config{Cash: 10000},
+			orders: map[barKey][]Order{{1, 0}: {limitBuy(1, 10, 100)}},
+			bars:   []Bar{bar(1, 0, 100), bar(1, 1, 90)},
+			trades: 1,
+			held:   10,
+			cash:   9000,
+		},
+		{
+			name:   "not filled on the bar it was placed on",
+			cfg:    Config{Cash: 10000},
+			orders: map[barKey][]Order{{1, 0}: {limitBuy(1, 10, 100)}},
+			bars:   []Bar{bar(1, 0, 50)},
+			cash:   10000,
+		},
+		{
+			name:   "price not reached",
+			cfg:    Config{Cash: 10000},
+			orders: map[barKey][]Order{{1, 0}: {limitBuy(1, 10, 100)}},
+			bars:   []Bar{bar(1, 0, 100), bar(1, 1, 101)},
+			cash:   10000,
+		},
+		{
+			name:   "partial fills until the offer expires",
+			cfg:    Config{Cash: 10000, FillRatio: 0.5, OrderTTL: 3},
+			orders: map[barKey][]Order{{1, 0}: {limitBuy(1, 10, 100)}},
+			bars:   []Bar{bar(1, 0, 100), bar(1, 1, 100), bar(1, 2, 100), bar(1, 3, 100), bar(1, 4, 100)},
+			trades: 3, // 5, then 2, then 1
+			held:   8,
+			cash:   9200,
+		},
+		{
+			name: "sell after buy",
+			cfg:  Config{Cash: 10000},
+			orders: map[barKey][]Order{
+				{1, 0}: {limitBuy(1, 10, 100)},
+				{1, 1}: {limitSell(1, 10, 200)},
+			},
+			bars:   []Bar{bar(1, 0, 100), bar(1, 1, 100), bar(1, 2, 200)},
+			trades: 2,
+			cash:   9000 + 10*(200-2),
+		},
+		{
+			name:   "buy limit trims the order",
+			cfg:    Config{Cash: 10000},
+			items:  map[int]domain.ItemPrice{1: {ItemID: 1, BuyLimit: 4}},
+			orders: map[barKey][]Order{{1, 0}: {limitBuy(1, 10, 100)}},
+			bars:   []Bar{bar(1, 0, 100), bar(1, 1, 100)},
+			trades: 1,
+			held:   4,
+			cash:   9600,
+		},
+		{
+			name: "orders beyond the holding, cash or slots are rejected",
+			cfg:  Config{Cash: 150, Slots: 1},
+			orders: map[barKey][]Order{
+				{1, 0}: {limitSell(1, 1, 100), limitBuy(1, 1, 200), limitBuy(1, 1, 100), limitBuy(1, 1, 10)},
+			},
+			bars:     []Bar{bar(1, 0, 100), bar(1, 1, 100)},
+			trades:   1,
+			held:     1,
+			rejected: 3,
+			cash:     50,
+		},
+		{
+			name: "orders placed on another item's bar skip that item's bar at the same time",
+			cfg:  Config{Cash: 10000},
+			// Item 1's bar is handled first at hour 0; item 2's bar at hour 0
+			// would fill the offer, the one at hour 1 does not
+			orders: map[barKey][]Order{{1, 0}: {limitBuy(2, 10, 100)}},
+			bars:   []Bar{bar(2, 0, 50), bar(1, 0, 100), bar(2, 1, 150)},
+			cash:   10000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Run(&scripted{orders: tt.orders}, tt.items, tt.bars, tt.cfg)
+
+			if report.Trades != tt.trades {
+				t.Errorf("trades = %d, want %d", report.Trades, tt.trades)
+			}
+			held := 0
+			for _, lot := range report.OpenPositions {
+				held += lot.Quantity
+			}
+			if held != tt.held {
+				t.Errorf("held = %d, want %d", held, tt.held)
+			}
+			if report.RejectedOrders != tt.rejected {
+				t.Errorf("rejected orders = %d, want %d", report.RejectedOrders, tt.rejected)
+			}
+			if cash := report.Curve[len(report.Curve)-1].Cash; cash != tt.cash {
+				t.Errorf("final cash = %d, want %d", cash, tt.cash)
+			}
+		})
+	}
+}
+
+func TestRunEscrow(t *testing.T) {
+	strategy := &scripted{orders: map[barKey][]Order{
+		{1, 0}: {limitBuy(1, 10, 100)},
+		{2, 0}: {limitBuy(2, 5, 200)},
+	}}
+	// Item 1 never reaches its offer, which expires on its bar at hour 2;
+	// item 2 fills at hour 1
+	bars := []Bar{bar(1, 0, 100), bar(2, 0, 200), bar(1, 1, 150), bar(2, 1, 200), bar(1, 2, 150)}
+	report := Run(strategy, nil, bars, Config{Cash: 5000, OrderTTL: 2})
+
+	seen := []struct {
+		key  barKey
+		cash int
+	}{
+		{barKey{1, 0}, 5000},
+		{barKey{2, 0}, 4000},
+		{barKey{1, 1}, 3000},
+		{barKey{2, 1}, 3000},
+		{barKey{1, 2}, 4000},
+	}
+	for _, want := range seen {
+		if cash := strategy.cash[want.key]; cash != want.cash {
+			t.Errorf("cash seen on item %d at hour %d = %d, want %d", want.key.itemID, want.key.hour, cash, want.cash)
+		}
+	}
+
+	// The curve counts coins held by open buy offers as cash
+	wantCurve := []CurvePoint{
+		{Time: hour(0), Equity: 5000, Cash: 5000},
+		{Time: hour(1), Equity: 4000 + 5*(200-2), Cash: 4000},
+		{Time: hour(2), Equity: 4000 + 5*(200-2), Cash: 4000},
+	}
+	if len(report.Curve) != len(wantCurve) {
+		t.Fatalf("curve has %d points, want %d", len(report.Curve), len(wantCurve))
+	}
+	for i, want := range wantCurve {
+		got := report.Curve[i]
+		if !got.Time.Equal(want.Time) || got.Equity != want.Equity || got.Cash != want.Cash {
+			t.Errorf("curve[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestRunFees(t *testing.T) {
+	strategy := &scripted{orders: map[barKey][]Order{
+		{1, 0}: {limitBuy(1, 10, 1000)},
+		{1, 1}: {limitSell(1, 6, 1500)},
+		{1, 2}: {limitSell(1, 4, 900)},
+	}}
+	bars := []Bar{bar(1, 0, 1000), bar(1, 1, 1000), bar(1, 2, 1500), bar(1, 3, 900)}
+	report := Run(strategy, nil, bars, Config{Cash: 20000})
+
+	// 6 sold at 1500 with 15 tax each, then 4 at 900 with 9 tax each
+	wantTax := 6*15 + 4*9
+	wantProfit := 6*(1500-15-1000) + 4*(900-9-1000)
+	if report.Tax != wantTax {
+		t.Errorf("tax = %d, want %d", report.Tax, wantTax)
+	}
+	if report.RealizedProfit != wantProfit {
+		t.Errorf("realized profit = %d, want %d", report.RealizedProfit, wantProfit)
+	}
+	if report.FinalEquity != 20000+wantProfit {
+		t.Errorf("final equity = %d, want %d", report.FinalEquity, 20000+wantProfit)
+	}
+	if report.Flips != 2 || report.Wins != 1 || report.WinRate != 50 {
+		t.Errorf("flips = %d, wins = %d, win rate = %v; want 2, 1, 50", report.Flips, report.Wins, report.WinRate)
+	}
+	if got := report.Curve[2].RealizedProfit; got != 6*(1500-15-1000) {
+		t.Errorf("realized profit at hour 2 = %d, want %d", got, 6*(1500-15-1000))
+	}
+}
+
+func TestRunDrawdown(t *testing.T) {
+	strategy := &scripted{orders: map[barKey][]Order{{1, 0}: {limitBuy(1, 10, 1000)}}}
+	// Held items are valued at the last price after tax
+	bars := []Bar{bar(1, 0, 1000), bar(1, 1, 1000), bar(1, 2, 1200), bar(1, 3, 600), bar(1, 4, 800)}
+	report := Run(strategy, nil, bars, Config{Cash: 10000})
+
+	equity := func(price int) int { return 10 * (price - price/100) }
+	wantEquity := []int{10000, equity(1000), equity(1200), equity(600), equity(800)}
+	for i, want := range wantEquity {
+		if report.Curve[i].Equity != want {
+			t.Errorf("equity at hour %d = %d, want %d", i, report.Curve[i].Equity, want)
+		}
+	}
+
+	peak := equity(1200)
+	if report.MaxDrawdown != peak-equity(600) {
+		t.Errorf("max drawdown = %d, want %d", report.MaxDrawdown, peak-equity(600))
+	}
+	if want := float64(peak-equity(600)) / float64(peak) * 100; report.MaxDrawdownPercent != want {
+		t.Errorf("max drawdown percent = %v, want %v", report.MaxDrawdownPercent, want)
+	}
+	if want := float64(peak-equity(800)) / float64(peak) * 100; report.Curve[4].Drawdown != want {
+		t.Errorf("drawdown at hour 4 = %v, want %v", report.Curve[4].Drawdown, want)
+	}
+	// Buying costs the tax of selling again; a new peak clears the drawdown
+	if report.Curve[1].Drawdown != 1 {
+		t.Errorf("drawdown at hour 1 = %v, want 1", report.Curve[1].Drawdown)
+	}
+	if report.Curve[2].Drawdown != 0 {
+		t.Errorf("drawdown at hour 2 = %v, want 0", report.Curve[2].Drawdown)
+	}
+}
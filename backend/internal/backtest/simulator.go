@@ -0,0 +1,197 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// offer is an order open on the simulated GE
+type offer struct {
+	Order
+	remaining int
+	age       int       // Bars of the item seen since the offer was placed
+	placedAt  time.Time // The offer is matched from the first bar after it
+}
+
+// simulator is the simulated GE and account; it implements Market
+type simulator struct {
+	cfg      Config
+	items    map[int]domain.ItemPrice
+	now      time.Time
+	cash     int // Not held by buy offers
+	holdings map[int]int
+	offers   []*offer
+	bars     map[int][]Bar
+	fills    []domain.Trade
+}
+
+func newSimulator(cfg Config, items map[int]domain.ItemPrice) *simulator {
+	return &simulator{
+		cfg:      cfg,
+		items:    items,
+		cash:     cfg.Cash,
+		holdings: make(map[int]int),
+		bars:     make(map[int][]Bar),
+	}
+}
+
+func (s *simulator) Time() time.Time        { return s.now }
+func (s *simulator) Cash() int              { return s.cash }
+func (s *simulator) Holding(itemID int) int { return s.holdings[itemID] }
+func (s *simulator) FreeSlots() int         { return s.cfg.Slots - len(s.offers) }
+func (s *simulator) Bars(itemID int) []Bar  { return s.bars[itemID] }
+
+func (s *simulator) HasOffer(itemID int) bool {
+	for _, o := range s.offers {
+		if o.ItemID == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+// BuyLimitRemaining counts the buys filled within the limit window and the
+// rest of the open buy offers against the item's limit
+func (s *simulator) BuyLimitRemaining(itemID int) int {
+	item, ok := s.items[itemID]
+	if !ok || item.BuyLimit <= 0 {
+		return -1
+	}
+	status := domain.NewBuyLimitStatus(item, s.fills, s.now)
+	remaining := *status.Remaining
+	for _, o := range s.offers {
+		if o.ItemID == itemID && o.Side == domain.TradeBuy {
+			remaining -= o.remaining
+		}
+	}
+	return max(remaining, 0)
+}
+
+// place opens an offer for order, trimmed to the buy limit and the cash or
+// holding available. It reports false when nothing could be offered.
+func (s *simulator) place(order Order) bool {
+	if order.Quantity <= 0 || order.Price <= 0 || s.FreeSlots() <= 0 {
+		return false
+	}
+
+	quantity := order.Quantity
+	switch order.Side {
+	case domain.TradeBuy:
+		if limit := s.BuyLimitRemaining(order.ItemID); limit >= 0 {
+			quantity = min(quantity, limit)
+		}
+		quantity = min(quantity, s.cash/order.Price)
+		if quantity <= 0 {
+			return false
+		}
+		s.cash -= quantity * order.Price
+	case domain.TradeSell:
+		quantity = min(quantity, s.holdings[order.ItemID])
+		if quantity <= 0 {
+			return false
+		}
+		s.holdings[order.ItemID] -= quantity
+	default:
+		return false
+	}
+
+	order.Quantity = quantity
+	s.offers = append(s.offers, &offer{Order: order, remaining: quantity, placedAt: s.now})
+	return true
+}
+
+// match fills the offers open on the bar's item whose price the bar
+// reaches, at the offer's price, and cancels the rest of expired offers.
+// Offers placed at the bar's time are left alone: a strategy handling
+// another item's bar at that time has not seen this bar.
+func (s *simulator) match(bar Bar) {
+	open := s.offers[:0]
+	for _, o := range s.offers {
+		if o.ItemID != bar.ItemID || !o.placedAt.Before(bar.Time) {
+			open = append(open, o)
+			continue
+		}
+
+		crossed := o.Side == domain.TradeBuy && bar.Price <= o.Price ||
+			o.Side == domain.TradeSell && bar.Price >= o.Price
+		if crossed {
+			s.fill(o, max(int(float64(o.remaining)*s.cfg.FillRatio), 1))
+		}
+
+		o.age++
+		if o.remaining > 0 && o.age >= s.cfg.OrderTTL {
+			s.cancel(o)
+		}
+		if o.remaining > 0 {
+			open = append(open, o)
+		}
+	}
+	s.offers = open
+}
+
+// fill completes quantity items of an offer
+func (s *simulator) fill(o *offer, quantity int) {
+	quantity = min(quantity, o.remaining)
+	o.remaining -= quantity
+	if o.Side == domain.TradeBuy {
+		s.holdings[o.ItemID] += quantity
+	} else {
+		s.cash += (o.Price - domain.CalculateGETax(o.Price)) * quantity
+	}
+	s.fills = append(s.fills, domain.Trade{
+		ID:        fmt.Sprintf("fill-%d", len(s.fills)+1),
+		ItemID:    o.ItemID,
+		Side:      o.Side,
+		Quantity:  quantity,
+		Price:     o.Price,
+		TradedAt:  s.now,
+		CreatedAt: s.now.Add(time.Duration(len(s.fills))),
+	})
+}
+
+// cancel returns what an offer still holds to the account
+func (s *simulator) cancel(o *offer) {
+	if o.Side == domain.TradeBuy {
+		s.cash += o.remaining * o.Price
+	} else {
+		s.holdings[o.ItemID] += o.remaining
+	}
+	o.remaining = 0
+}
+
+// escrow is the cash held by open buy offers
+func (s *simulator) escrow() int {
+	total := 0
+	for _, o := range s.offers {
+		if o.Side == domain.TradeBuy {
+			total += o.remaining * o.Price
+		}
+	}
+	return total
+}
+
+// equity values the account with items at their last price, after tax
+func (s *simulator) equity() int {
+	quantities := make(map[int]int, len(s.holdings))
+	for id, quantity := range s.holdings {
+		quantities[id] += quantity
+	}
+	for _, o := range s.offers {
+		if o.Side == domain.TradeSell {
+			quantities[o.ItemID] += o.remaining
+		}
+	}
+
+	equity := s.cash + s.escrow()
+	for id, quantity := range quantities {
+		bars := s.bars[id]
+		if quantity == 0 || len(bars) == 0 {
+			continue
+		}
+		price := bars[len(bars)-1].Price
+		equity += (price - domain.CalculateGETax(price)) * quantity
+	}
+	return equity
+}
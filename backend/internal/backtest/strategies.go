@@ -0,0 +1,138 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// ErrUnknownStrategy is returned for a strategy name without an implementation
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// Names of the built-in strategies
+const (
+	StrategyMargin        = "margin"
+	StrategyMeanReversion = "mean_reversion"
+)
+
+// defaultLookback is the rolling window of the built-in strategies, in bars
+const defaultLookback = 7
+
+// StrategyConfig selects a built-in strategy and its parameters; each
+// strategy reads the fields it needs
+type StrategyConfig struct {
+	Name string `json:"name"`
+	// Lookback is the number of bars in the rolling window; default 7
+	Lookback int `json:"lookback,omitempty"`
+	// MinMargin (margin) is the lowest post-tax margin, in percent, between
+	// the window's low and high worth trading
+	MinMargin float64 `json:"min_margin,omitempty"`
+	// Threshold (mean_reversion) is how far below the window's mean, in
+	// percent, to bid
+	Threshold float64 `json:"threshold,omitempty"`
+	// MaxShare is the most of the cash, in percent, one buy offer may take;
+	// default an even split over the slots
+	MaxShare float64 `json:"max_share,omitempty"`
+}
+
+// NewStrategy builds the built-in strategy described by cfg for an account
+// with slots GE slots
+func NewStrategy(cfg StrategyConfig, slots int) (Strategy, error) {
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = defaultLookback
+	}
+	if cfg.MaxShare <= 0 {
+		if slots <= 0 {
+			slots = domain.GESlots
+		}
+		cfg.MaxShare = 100 / float64(slots)
+	}
+
+	switch cfg.Name {
+	case StrategyMargin:
+		return &marginStrategy{cfg: cfg}, nil
+	case StrategyMeanReversion:
+		return &meanReversionStrategy{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("%w %q (expected %s or %s)", ErrUnknownStrategy, cfg.Name, StrategyMargin, StrategyMeanReversion)
+	}
+}
+
+// marginStrategy bids the low of the rolling window when selling at its
+// high would clear MinMargin after tax, then offers what it bought at the
+// window's current high
+type marginStrategy struct {
+	cfg StrategyConfig
+}
+
+func (s *marginStrategy) Name() string { return StrategyMargin }
+
+func (s *marginStrategy) OnBar(m Market, bar Bar) []Order {
+	window, ok := lastBars(m, bar.ItemID, s.cfg.Lookback)
+	if !ok || m.HasOffer(bar.ItemID) {
+		return nil
+	}
+	low, high := window[0].Price, window[0].Price
+	for _, b := range window[1:] {
+		low, high = min(low, b.Price), max(high, b.Price)
+	}
+
+	if held := m.Holding(bar.ItemID); held > 0 {
+		return []Order{{ItemID: bar.ItemID, Side: domain.TradeSell, Quantity: held, Price: high}}
+	}
+	if low <= 0 || domain.CalculateMargin(low, high-domain.CalculateGETax(high)) < s.cfg.MinMargin {
+		return nil
+	}
+	return buyOrder(m, bar.ItemID, low, s.cfg.MaxShare)
+}
+
+// meanReversionStrategy bids Threshold percent below the rolling mean and
+// offers what it bought at the mean
+type meanReversionStrategy struct {
+	cfg StrategyConfig
+}
+
+func (s *meanReversionStrategy) Name() string { return StrategyMeanReversion }
+
+func (s *meanReversionStrategy) OnBar(m Market, bar Bar) []Order {
+	window, ok := lastBars(m, bar.ItemID, s.cfg.Lookback)
+	if !ok || m.HasOffer(bar.ItemID) {
+		return nil
+	}
+	sum := 0
+	for _, b := range window {
+		sum += b.Price
+	}
+	mean := sum / len(window)
+
+	if held := m.Holding(bar.ItemID); held > 0 {
+		return []Order{{ItemID: bar.ItemID, Side: domain.TradeSell, Quantity: held, Price: mean}}
+	}
+	return buyOrder(m, bar.ItemID, int(float64(mean)*(1-s.cfg.Threshold/100)), s.cfg.MaxShare)
+}
+
+// lastBars returns the item's last n bars, or false while fewer were seen
+func lastBars(m Market, itemID, n int) ([]Bar, bool) {
+	bars := m.Bars(itemID)
+	if len(bars) < n {
+		return nil, false
+	}
+	return bars[len(bars)-n:], true
+}
+
+// buyOrder bids price for as many items as maxShare percent of the cash
+// buys, within the item's buy limit
+func buyOrder(m Market, itemID, price int, maxShare float64) []Order {
+	if price <= 0 || m.FreeSlots() == 0 {
+		return nil
+	}
+	quantity := int(float64(m.Cash()) * maxShare / 100 / float64(price))
+	if limit := m.BuyLimitRemaining(itemID); limit >= 0 {
+		quantity = min(quantity, limit)
+	}
+	if quantity <= 0 {
+		return nil
+	}
+	return []Order{{ItemID: itemID, Side: domain.TradeBuy, Quantity: quantity, Price: price}}
+}
@@ -0,0 +1,281 @@
+// Package config loads the service configuration from defaults, a YAML or
+// TOML file, environment variables and command-line flags, in that order of
+// precedence, and validates it before anything starts.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/logging"
+)
+
+// Config holds every setting of the API, the worker and the CLI subcommands
+type Config struct {
+	Env       string          `yaml:"env" toml:"env" json:"env"`
+	Server    ServerConfig    `yaml:"server" toml:"server" json:"server"`
+	OSRSWiki  OSRSWikiConfig  `yaml:"osrs_wiki" toml:"osrs_wiki" json:"osrs_wiki"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit" json:"rate_limit"`
+	CORS      CORSConfig      `yaml:"cors" toml:"cors" json:"cors"`
+	Worker    WorkerConfig    `yaml:"worker" toml:"worker" json:"worker"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database" json:"database"`
+	Log       LogConfig       `yaml:"log" toml:"log" json:"log"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing" json:"tracing"`
+	Admin     AdminConfig     `yaml:"admin" toml:"admin" json:"admin"`
+	Auth      AuthConfig      `yaml:"auth" toml:"auth" json:"auth"`
+}
+
+// ServerConfig configures the HTTP listener
+type ServerConfig struct {
+	Port int `yaml:"port" toml:"port" json:"port"`
+}
+
+// OSRSWikiConfig configures the OSRS Wiki prices client
+type OSRSWikiConfig struct {
+	BaseURL       string   `yaml:"base_url" toml:"base_url" json:"base_url"`
+	UserAgent     string   `yaml:"user_agent" toml:"user_agent" json:"user_agent"`
+	Timeout       Duration `yaml:"timeout" toml:"timeout" json:"timeout"`
+	CacheTTL      Duration `yaml:"cache_ttl" toml:"cache_ttl" json:"cache_ttl"`
+	NamesCacheTTL Duration `yaml:"names_cache_ttl" toml:"names_cache_ttl" json:"names_cache_ttl"`
+}
+
+// RateLimitConfig configures the rate limiter. Anonymous requests are limited
+// per IP; requests with an API key are limited per key, with higher defaults
+// that each key may override.
+type RateLimitConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute" toml:"requests_per_minute" json:"requests_per_minute"`
+	// KeyRequestsPerMinute is the default per-minute limit of an API key
+	KeyRequestsPerMinute int `yaml:"key_requests_per_minute" toml:"key_requests_per_minute" json:"key_requests_per_minute"`
+	// KeyDailyQuota is the default number of requests per UTC day of an API key; 0 means unlimited
+	KeyDailyQuota int `yaml:"key_daily_quota" toml:"key_daily_quota" json:"key_daily_quota"`
+}
+
+// CORSConfig lists origins allowed in addition to the built-in frontend origins
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins" json:"allowed_origins"`
+}
+
+// WorkerConfig configures the price updater
+type WorkerConfig struct {
+	UpdateInterval Duration `yaml:"update_interval" toml:"update_interval" json:"update_interval"`
+	// Mode is embedded, leader or off; empty picks leader with a database and embedded without
+	Mode string `yaml:"mode" toml:"mode" json:"mode"`
+}
+
+// DatabaseConfig configures the shared repository; an empty URL keeps data in memory
+type DatabaseConfig struct {
+	URL string `yaml:"url" toml:"url" json:"url"`
+}
+
+// LogConfig configures structured logging
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level" json:"level"`
+}
+
+// TracingConfig configures OpenTelemetry span export
+type TracingConfig struct {
+	Exporter     string `yaml:"exporter" toml:"exporter" json:"exporter"`
+	OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint" json:"otlp_endpoint"`
+	ServiceName  string `yaml:"service_name" toml:"service_name" json:"service_name"`
+}
+
+// AdminConfig configures the /admin routes; an empty token disables them
+type AdminConfig struct {
+	Token string `yaml:"token" toml:"token" json:"token"`
+}
+
+// AuthConfig configures user sessions
+type AuthConfig struct {
+	// JWTSecret signs access tokens; it must be shared by every replica.
+	// When empty, a random secret is generated at startup (outside production).
+	JWTSecret       string   `yaml:"jwt_secret" toml:"jwt_secret" json:"jwt_secret"`
+	AccessTokenTTL  Duration `yaml:"access_token_ttl" toml:"access_token_ttl" json:"access_token_ttl"`
+	RefreshTokenTTL Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl" json:"refresh_token_ttl"`
+}
+
+// minJWTSecretLength is the shortest accepted signing secret, in bytes
+const minJWTSecretLength = 32
+
+// Duration is a time.Duration written as "90s" or "5m" in files and JSON
+type Duration time.Duration
+
+// UnmarshalText parses a Go duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Std returns the value as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// Default returns the configuration used when no source sets a value
+func Default() Config {
+	return Config{
+		Env:    "development",
+		Server: ServerConfig{Port: 8080},
+		OSRSWiki: OSRSWikiConfig{
+			BaseURL:       "https://prices.runescape.wiki/api/v1/osrs",
+			UserAgent:     "OSRS-GoodToFlip/1.0 (contact: your-email@example.com)",
+			Timeout:       Duration(10 * time.Second),
+			CacheTTL:      Duration(60 * time.Second),
+			NamesCacheTTL: Duration(time.Hour),
+		},
+		RateLimit: RateLimitConfig{RequestsPerMinute: 100, KeyRequestsPerMinute: 600, KeyDailyQuota: 100000},
+		Worker:    WorkerConfig{UpdateInterval: Duration(5 * time.Minute)},
+		Log:       LogConfig{Level: "info"},
+		Tracing:   TracingConfig{Exporter: "none", ServiceName: "osrs-good-to-flip"},
+		Auth: AuthConfig{
+			AccessTokenTTL:  Duration(15 * time.Minute),
+			RefreshTokenTTL: Duration(30 * 24 * time.Hour),
+		},
+	}
+}
+
+// IsProduction reports whether detailed error messages must be hidden
+func (c *Config) IsProduction() bool {
+	return c.Env == "production" || c.Env == "prod"
+}
+
+// validEnvs are the accepted Env values; a typo would otherwise silently
+// leave production error masking off
+var validEnvs = map[string]bool{
+	"development": true, "dev": true, "test": true, "staging": true, "production": true, "prod": true,
+}
+
+// Validate checks every setting and reports all problems at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validEnvs[c.Env], "env: invalid value %q (expected development, dev, test, staging, production or prod)", c.Env)
+	check(c.Server.Port >= 1 && c.Server.Port <= 65535, "server.port: must be between 1 and 65535, got %d", c.Server.Port)
+
+	check(isHTTPURL(c.OSRSWiki.BaseURL), "osrs_wiki.base_url: must be an absolute http(s) URL, got %q", c.OSRSWiki.BaseURL)
+	check(strings.TrimSpace(c.OSRSWiki.UserAgent) != "", "osrs_wiki.user_agent: must not be empty")
+	check(c.OSRSWiki.Timeout > 0, "osrs_wiki.timeout: must be positive")
+	check(c.OSRSWiki.CacheTTL > 0, "osrs_wiki.cache_ttl: must be positive")
+	check(c.OSRSWiki.NamesCacheTTL > 0, "osrs_wiki.names_cache_ttl: must be positive")
+
+	check(c.RateLimit.RequestsPerMinute > 0, "rate_limit.requests_per_minute: must be positive, got %d", c.RateLimit.RequestsPerMinute)
+	check(c.RateLimit.KeyRequestsPerMinute > 0, "rate_limit.key_requests_per_minute: must be positive, got %d", c.RateLimit.KeyRequestsPerMinute)
+	check(c.RateLimit.KeyDailyQuota >= 0, "rate_limit.key_daily_quota: must not be negative, got %d", c.RateLimit.KeyDailyQuota)
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(isOrigin(origin), "cors.allowed_origins: %q is not an origin like https://example.com", origin)
+	}
+
+	check(c.Worker.UpdateInterval.Std() >= time.Minute, "worker.update_interval: must be at least 1m, got %s", c.Worker.UpdateInterval.Std())
+	switch c.Worker.Mode {
+	case "", "embedded", "leader", "off":
+	default:
+		check(false, "worker.mode: invalid value %q (expected embedded, leader or off)", c.Worker.Mode)
+	}
+
+	if c.Database.URL != "" && strings.Contains(c.Database.URL, "://") {
+		u, err := url.Parse(c.Database.URL)
+		check(err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql"), "database.url: must be a postgres:// URL")
+	}
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level: invalid value %q (expected debug, info, warn or error)", c.Log.Level)
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		check(false, "tracing.exporter: invalid value %q (expected none, stdout or otlp)", c.Tracing.Exporter)
+	}
+	if c.Tracing.OTLPEndpoint != "" {
+		check(isHTTPURL(c.Tracing.OTLPEndpoint), "tracing.otlp_endpoint: must be an absolute http(s) URL, got %q", c.Tracing.OTLPEndpoint)
+	}
+	check(c.Tracing.ServiceName != "", "tracing.service_name: must not be empty")
+
+	if c.Auth.JWTSecret != "" {
+		check(len(c.Auth.JWTSecret) >= minJWTSecretLength, "auth.jwt_secret: must be at least %d bytes", minJWTSecretLength)
+	} else {
+		check(!c.IsProduction(), "auth.jwt_secret: required in production (sessions would not survive restarts or work across replicas)")
+	}
+	check(c.Auth.AccessTokenTTL.Std() >= time.Minute, "auth.access_token_ttl: must be at least 1m, got %s", c.Auth.AccessTokenTTL.Std())
+	check(c.Auth.RefreshTokenTTL.Std() > c.Auth.AccessTokenTTL.Std(), "auth.refresh_token_ttl: must be longer than auth.access_token_ttl")
+
+	return errors.Join(errs...)
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isOrigin reports whether s is a scheme and host with no path
+func isOrigin(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == ""
+}
+
+// redacted replaces a secret with a marker, keeping empty values empty
+// so it stays visible whether the secret is set
+const redacted = "[REDACTED]"
+
+// Redacted returns a copy that is safe to expose: the admin token, JWT
+// secret and database passwords are masked
+func (c Config) Redacted() Config {
+	if c.Admin.Token != "" {
+		c.Admin.Token = redacted
+	}
+	if c.Auth.JWTSecret != "" {
+		c.Auth.JWTSecret = redacted
+	}
+	if c.Database.URL != "" {
+		c.Database.URL = redactDatabaseURL(c.Database.URL)
+	}
+	c.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+	return c
+}
+
+// secretDSNParams are the query parameters of a database URL holding passwords
+var secretDSNParams = []string{"password", "sslpassword"}
+
+// redactDatabaseURL masks the passwords of a database URL, in the userinfo
+// and in the query, the way url.URL.Redacted does
+func redactDatabaseURL(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || !strings.Contains(dsn, "://") {
+		// Key/value DSNs may carry password=...; hide them entirely
+		return redacted
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		// A malformed query could still hold a password
+		return redacted
+	}
+	masked := false
+	for _, param := range secretDSNParams {
+		if query.Has(param) {
+			query.Set(param, "xxxxx")
+			masked = true
+		}
+	}
+	if masked {
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from the defaults, the file named by -config
+// or CONFIG_FILE, the environment and the flags that were set, in that order,
+// and validates the result. Call it after the command's flag set is parsed.
+func (f *Flags) Load() (*Config, error) {
+	cfg := Default()
+
+	if path := f.File(); path != "" {
+		if err := cfg.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	f.apply(&cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return &cfg, nil
+}
+
+// File returns the config file named by -config or CONFIG_FILE, if any
+func (f *Flags) File() string {
+	if *f.file != "" {
+		return *f.file
+	}
+	return os.Getenv("CONFIG_FILE")
+}
+
+// LoadFile merges a YAML (.yaml, .yml) or TOML (.toml) file into c.
+// Keys missing from the file keep their current values; unknown keys are errors.
+func (c *Config) LoadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		// An empty file decodes to io.EOF and leaves c unchanged
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(content), c)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported extension (expected .yaml, .yml or .toml)", path)
+	}
+	return nil
+}
+
+// ApplyEnv overrides c with the environment variables found by lookup.
+// Malformed values are reported instead of being ignored.
+func (c *Config) ApplyEnv(lookup func(key string) (string, bool)) error {
+	var errs []error
+	str := func(key string, dst *string) {
+		if v, ok := lookup(key); ok && v != "" {
+			*dst = strings.TrimSpace(v)
+		}
+	}
+	integer := func(key string, dst *int) {
+		if v, ok := lookup(key); ok && v != "" {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not an integer", key, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	// Legacy variables carry a number in a fixed unit
+	duration := func(key string, unit time.Duration, dst *Duration) {
+		if v, ok := lookup(key); ok && v != "" {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not an integer", key, v))
+				return
+			}
+			*dst = Duration(time.Duration(n) * unit)
+		}
+	}
+
+	// Newer variables take Go durations such as "15m" or "720h"
+	goDuration := func(key string, dst *Duration) {
+		if v, ok := lookup(key); ok && v != "" {
+			d, err := time.ParseDuration(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration like 15m or 720h", key, v))
+				return
+			}
+			*dst = Duration(d)
+		}
+	}
+
+	str("ENV", &c.Env)
+	integer("PORT", &c.Server.Port)
+
+	str("OSRS_WIKI_BASE_URL", &c.OSRSWiki.BaseURL)
+	str("OSRS_WIKI_USER_AGENT", &c.OSRSWiki.UserAgent)
+	duration("OSRS_WIKI_TIMEOUT_MS", time.Millisecond, &c.OSRSWiki.Timeout)
+	duration("OSRS_WIKI_CACHE_TTL_SEC", time.Second, &c.OSRSWiki.CacheTTL)
+	duration("OSRS_WIKI_NAMES_CACHE_TTL_SEC", time.Second, &c.OSRSWiki.NamesCacheTTL)
+
+	integer("RATE_LIMIT_REQUESTS_PER_MINUTE", &c.RateLimit.RequestsPerMinute)
+	integer("RATE_LIMIT_KEY_REQUESTS_PER_MINUTE", &c.RateLimit.KeyRequestsPerMinute)
+	integer("API_KEY_DAILY_QUOTA", &c.RateLimit.KeyDailyQuota)
+	if v, ok := lookup("ALLOWED_ORIGINS"); ok && v != "" {
+		c.CORS.AllowedOrigins = splitList(v)
+	}
+
+	duration("PRICE_UPDATE_INTERVAL_MIN", time.Minute, &c.Worker.UpdateInterval)
+	str("WORKER_MODE", &c.Worker.Mode)
+	str("DATABASE_URL", &c.Database.URL)
+	str("LOG_LEVEL", &c.Log.Level)
+
+	str("OTEL_TRACES_EXPORTER", &c.Tracing.Exporter)
+	str("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	str("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+
+	str("ADMIN_TOKEN", &c.Admin.Token)
+
+	str("JWT_SECRET", &c.Auth.JWTSecret)
+	goDuration("ACCESS_TOKEN_TTL", &c.Auth.AccessTokenTTL)
+	goDuration("REFRESH_TOKEN_TTL", &c.Auth.RefreshTokenTTL)
+
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma-separated list, dropping blanks
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Flags holds the configuration flags registered on a command's flag set
+type Flags struct {
+	fs             *flag.FlagSet
+	file           *string
+	env            *string
+	port           *int
+	logLevel       *string
+	workerMode     *string
+	updateInterval *time.Duration
+	rateLimit      *int
+	allowedOrigins *string
+	traceExporter  *string
+}
+
+// RegisterFlags adds the configuration flags to fs. Secrets have no flags
+// since command lines are visible to other processes.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	return &Flags{
+		fs:             fs,
+		file:           fs.String("config", "", "YAML or TOML config file (env CONFIG_FILE)"),
+		env:            fs.String("env", "", "environment: development, staging, production... (env ENV)"),
+		port:           fs.Int("port", 0, "HTTP port (env PORT, default 8080)"),
+		logLevel:       fs.String("log-level", "", "debug, info, warn or error (env LOG_LEVEL)"),
+		workerMode:     fs.String("worker-mode", "", "price updater: embedded, leader or off (env WORKER_MODE; default: leader with a database, embedded otherwise)"),
+		updateInterval: fs.Duration("update-interval", 0, "price update interval (env PRICE_UPDATE_INTERVAL_MIN, default 5m)"),
+		rateLimit:      fs.Int("rate-limit", 0, "requests per minute per IP (env RATE_LIMIT_REQUESTS_PER_MINUTE, default 100)"),
+		allowedOrigins: fs.String("allowed-origins", "", "extra CORS origins, comma-separated (env ALLOWED_ORIGINS)"),
+		traceExporter:  fs.String("trace-exporter", "", "none, stdout or otlp (env OTEL_TRACES_EXPORTER)"),
+	}
+}
+
+// apply copies the flags that were explicitly set on the command line
+func (f *Flags) apply(c *Config) {
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "env":
+			c.Env = *f.env
+		case "port":
+			c.Server.Port = *f.port
+		case "log-level":
+			c.Log.Level = *f.logLevel
+		case "worker-mode":
+			c.Worker.Mode = *f.workerMode
+		case "update-interval":
+			c.Worker.UpdateInterval = Duration(*f.updateInterval)
+		case "rate-limit":
+			c.RateLimit.RequestsPerMinute = *f.rateLimit
+		case "allowed-origins":
+			c.CORS.AllowedOrigins = splitList(*f.allowedOrigins)
+		case "trace-exporter":
+			c.Tracing.Exporter = *f.traceExporter
+		}
+	})
+}
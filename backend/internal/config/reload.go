@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// RestartRequiredError rejects a reload that changes settings only read at startup
+type RestartRequiredError struct {
+	Keys []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	return fmt.Sprintf("settings require a restart and were not reloaded: %s", strings.Join(e.Keys, ", "))
+}
+
+// Reloader holds the running configuration and replaces it when the sources
+// change. Only runtime-safe settings (CORS origins, rate limits, cache TTLs,
+// update interval and log level) may differ between reloads; anything else
+// rejects the whole reload, so a configuration is either applied in full or
+// not at all.
+type Reloader struct {
+	current   atomic.Pointer[Config]
+	load      func() (*Config, error)
+	mu        sync.Mutex // serializes reloads and listener changes
+	listeners []func(*Config)
+}
+
+// NewReloader starts from initial and reloads by calling load
+func NewReloader(initial *Config, load func() (*Config, error)) *Reloader {
+	r := &Reloader{load: load}
+	r.current.Store(initial)
+	return r
+}
+
+// Current returns the configuration in effect. It must not be modified.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload registers fn to be called with the new configuration after each
+// successful reload that changed something
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload loads and validates the configuration again and applies it when
+// only runtime settings changed. It returns the keys that changed; a reload
+// touching restart-only settings fails with a *RestartRequiredError.
+func (r *Reloader) Reload() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	old := r.current.Load()
+	if keys := restartKeys(old, next); len(keys) > 0 {
+		return nil, &RestartRequiredError{Keys: keys}
+	}
+
+	changed := runtimeKeys(old, next)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	r.current.Store(next)
+	for _, fn := range r.listeners {
+		fn(next)
+	}
+	return changed, nil
+}
+
+// reloadAndLog runs Reload and logs the outcome, naming what triggered it
+func (r *Reloader) reloadAndLog(trigger string) {
+	changed, err := r.Reload()
+	var restart *RestartRequiredError
+	switch {
+	case errors.As(err, &restart):
+		slog.Error("config reload rejected", "trigger", trigger, "restart_required", restart.Keys)
+	case err != nil:
+		slog.Error("config reload failed", "trigger", trigger, "error", err)
+	case len(changed) == 0:
+		slog.Info("config reloaded without changes", "trigger", trigger)
+	default:
+		slog.Info("config reloaded", "trigger", trigger, "changed", changed)
+	}
+}
+
+// WatchSignals reloads on SIGHUP until ctx is cancelled
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-hup:
+			r.reloadAndLog("SIGHUP")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// WatchFile reloads whenever the file at path changes, checking its size and
+// modification time every interval until ctx is cancelled. Polling works the
+// same on every platform and with editors that replace files on save.
+func (r *Reloader) WatchFile(ctx context.Context, path string, interval time.Duration) {
+	last, _ := os.Stat(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				// Possibly mid-replace; check again on the next tick
+				continue
+			}
+			if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+			last = info
+			r.reloadAndLog("file " + path)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// restartKeys lists the settings that differ and are only read at startup
+func restartKeys(old, next *Config) []string {
+	var keys []string
+	diff := func(changed bool, key string) {
+		if changed {
+			keys = append(keys, key)
+		}
+	}
+	diff(old.Env != next.Env, "env")
+	diff(old.Server.Port != next.Server.Port, "server.port")
+	diff(old.OSRSWiki.BaseURL != next.OSRSWiki.BaseURL, "osrs_wiki.base_url")
+	diff(old.OSRSWiki.UserAgent != next.OSRSWiki.UserAgent, "osrs_wiki.user_agent")
+	diff(old.OSRSWiki.Timeout != next.OSRSWiki.Timeout, "osrs_wiki.timeout")
+	diff(old.Worker.Mode != next.Worker.Mode, "worker.mode")
+	diff(old.Database.URL != next.Database.URL, "database.url")
+	diff(old.Tracing != next.Tracing, "tracing")
+	diff(old.Admin.Token != next.Admin.Token, "admin.token")
+	diff(old.Auth != next.Auth, "auth")
+	return keys
+}
+
+// runtimeKeys lists the settings that differ and can be applied while running
+func runtimeKeys(old, next *Config) []string {
+	var keys []string
+	diff := func(changed bool, key string) {
+		if changed {
+			keys = append(keys, key)
+		}
+	}
+	diff(!slices.Equal(old.CORS.AllowedOrigins, next.CORS.AllowedOrigins), "cors.allowed_origins")
+	diff(old.RateLimit.RequestsPerMinute != next.RateLimit.RequestsPerMinute, "rate_limit.requests_per_minute")
+	diff(old.RateLimit.KeyRequestsPerMinute != next.RateLimit.KeyRequestsPerMinute, "rate_limit.key_requests_per_minute")
+	diff(old.RateLimit.KeyDailyQuota != next.RateLimit.KeyDailyQuota, "rate_limit.key_daily_quota")
+	diff(old.OSRSWiki.CacheTTL != next.OSRSWiki.CacheTTL, "osrs_wiki.cache_ttl")
+	diff(old.OSRSWiki.NamesCacheTTL != next.OSRSWiki.NamesCacheTTL, "osrs_wiki.names_cache_ttl")
+	diff(old.Worker.UpdateInterval != next.Worker.UpdateInterval, "worker.update_interval")
+	diff(old.Log.Level != next.Log.Level, "log.level")
+	return keys
+}
@@ -0,0 +1,286 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// GESlots is the number of Grand Exchange offers a member can have open
+const GESlots = 8
+
+const (
+	// allocationUnits is how many equal parts the cash is split into by the
+	// optimizer; allocations are exact to 1/allocationUnits of the cash
+	// before the leftover is spread
+	allocationUnits = 100
+	// maxAllocationCandidates bounds the items considered, keeping the best
+	// by the profit they could make on their own
+	maxAllocationCandidates = 200
+)
+
+// RiskTolerance sets how aggressively the optimizer allocates capital
+type RiskTolerance string
+
+const (
+	RiskLow    RiskTolerance = "low"
+	RiskMedium RiskTolerance = "medium"
+	RiskHigh   RiskTolerance = "high"
+)
+
+// riskProfile holds the caps a risk tolerance applies
+type riskProfile struct {
+	maxShare       float64 // Most of the cash one item may take
+	volumeShare    float64 // Most of an item's traded volume we expect to fill
+	avoidDown      bool    // Skip items trending down
+	avoidAnomalies bool    // Skip items with anomaly flags
+}
+
+var riskProfiles = map[RiskTolerance]riskProfile{
+	RiskLow:    {maxShare: 0.20, volumeShare: 0.05, avoidDown: true, avoidAnomalies: true},
+	RiskMedium: {maxShare: 0.35, volumeShare: 0.10, avoidAnomalies: true},
+	RiskHigh:   {maxShare: 0.60, volumeShare: 0.25},
+}
+
+// Reasons an allocation's quantity is not higher
+const (
+	LimitedByBuyLimit      = "buy_limit"
+	LimitedByVolume        = "volume"
+	LimitedByConcentration = "concentration"
+	LimitedByCash          = "cash"
+)
+
+// AllocationRequest describes the capital to allocate
+type AllocationRequest struct {
+	Cash    int
+	Slots   int
+	Risk    RiskTolerance
+	Horizon time.Duration // How long the flips may take
+	// Category and Tag, when set, restrict the candidates like FlipFilter
+	Category Category
+	Tag      string
+}
+
+// Allocation is a quantity of an item to flip
+type Allocation struct {
+	ItemID         int     `json:"item_id"`
+	Name           string  `json:"name"`
+	Quantity       int     `json:"quantity"`
+	BuyPrice       int     `json:"buy_price"`
+	SellPrice      int     `json:"sell_price"`
+	Profit         int     `json:"profit"` // Per item after GE tax
+	Cost           int     `json:"cost"`
+	ExpectedProfit int     `json:"expected_profit"`
+	CapitalShare   float64 `json:"capital_share"` // Percentage of the cash
+	LimitedBy      string  `json:"limited_by"`
+}
+
+// AllocationPlan is the suggested use of the GE slots
+type AllocationPlan struct {
+	Cash           int           `json:"cash"`
+	Slots          int           `json:"slots"`
+	Risk           RiskTolerance `json:"risk"`
+	HorizonHours   float64       `json:"horizon_hours"`
+	Invested       int           `json:"invested"`
+	ExpectedProfit int           `json:"expected_profit"`
+	ROI            float64       `json:"roi"`         // Expected profit as a percentage of the invested cash
+	Allocations    []Allocation  `json:"allocations"` // Highest expected profit first
+}
+
+// allocationCandidate is a flip with the most that may be bought of it
+type allocationCandidate struct {
+	flip      Flip
+	maxQty    int
+	limitedBy string
+}
+
+// OptimizeAllocation picks up to req.Slots flips and their quantities to
+// maximize post-tax profit within req.Cash. Each item's quantity is capped by
+// its buy limit over the horizon (the user's remaining limit in the first
+// window when flips carry it), by the share of its volume the risk tolerance
+// expects to fill, and by the most of the cash one item may take.
+//
+// The cash is split into allocationUnits parts and a knapsack over
+// (slots, parts) chooses how many parts each item gets; cash left over by
+// the rounding then tops up the chosen items, most profitable per gp first.
+func OptimizeAllocation(flips []Flip, req AllocationRequest) AllocationPlan {
+	profile := riskProfiles[req.Risk]
+	plan := AllocationPlan{
+		Cash:         req.Cash,
+		Slots:        req.Slots,
+		Risk:         req.Risk,
+		HorizonHours: req.Horizon.Hours(),
+		Allocations:  []Allocation{},
+	}
+
+	candidates := allocationCandidates(flips, req, profile)
+	if len(candidates) == 0 || req.Slots <= 0 {
+		return plan
+	}
+
+	unit := req.Cash / allocationUnits
+	if unit == 0 {
+		unit = 1
+	}
+	units := req.Cash / unit
+
+	// best[s][c] is the highest profit using s slots and c units over the
+	// candidates seen so far; choice[i][s][c] the units given to candidate i
+	best := make([][]int, req.Slots+1)
+	for s := range best {
+		best[s] = make([]int, units+1)
+	}
+	choice := make([][][]int16, len(candidates))
+	for i, cand := range candidates {
+		choice[i] = make([][]int16, req.Slots+1)
+		for s := range choice[i] {
+			choice[i][s] = make([]int16, units+1)
+		}
+		maxUnits := min(units, ceilDiv(cand.maxQty*cand.flip.BuyPrice, unit))
+		for s := req.Slots; s >= 1; s-- {
+			for c := units; c >= 1; c-- {
+				prevQty := 0
+				for k := 1; k <= min(c, maxUnits); k++ {
+					qty := min(cand.maxQty, k*unit/cand.flip.BuyPrice)
+					if qty == prevQty {
+						continue
+					}
+					prevQty = qty
+					if value := best[s-1][c-k] + qty*cand.flip.Profit; value > best[s][c] {
+						best[s][c] = value
+						choice[i][s][c] = int16(k)
+					}
+				}
+			}
+		}
+	}
+
+	// Walk the choices back from the full budget
+	quantities := make(map[int]int)
+	s, c := req.Slots, units
+	for i := len(candidates) - 1; i >= 0 && s > 0; i-- {
+		if k := int(choice[i][s][c]); k > 0 {
+			quantities[i] = min(candidates[i].maxQty, k*unit/candidates[i].flip.BuyPrice)
+			s, c = s-1, c-k
+		}
+	}
+
+	chosen := make([]int, 0, len(quantities))
+	spent := 0
+	for i, qty := range quantities {
+		chosen = append(chosen, i)
+		spent += qty * candidates[i].flip.BuyPrice
+	}
+	sort.Slice(chosen, func(a, b int) bool {
+		fa, fb := candidates[chosen[a]].flip, candidates[chosen[b]].flip
+		if profitPerGP(fa) != profitPerGP(fb) {
+			return profitPerGP(fa) > profitPerGP(fb)
+		}
+		return fa.ItemID < fb.ItemID
+	})
+	for _, i := range chosen {
+		price := candidates[i].flip.BuyPrice
+		extra := min(candidates[i].maxQty-quantities[i], (req.Cash-spent)/price)
+		quantities[i] += extra
+		spent += extra * price
+	}
+
+	for _, i := range chosen {
+		cand, qty := candidates[i], quantities[i]
+		limitedBy := LimitedByCash
+		if qty == cand.maxQty {
+			limitedBy = cand.limitedBy
+		}
+		allocation := Allocation{
+			ItemID:         cand.flip.ItemID,
+			Name:           cand.flip.Name,
+			Quantity:       qty,
+			BuyPrice:       cand.flip.BuyPrice,
+			SellPrice:      cand.flip.SellPrice,
+			Profit:         cand.flip.Profit,
+			Cost:           qty * cand.flip.BuyPrice,
+			ExpectedProfit: qty * cand.flip.Profit,
+			CapitalShare:   float64(qty*cand.flip.BuyPrice) / float64(req.Cash) * 100,
+			LimitedBy:      limitedBy,
+		}
+		plan.Invested += allocation.Cost
+		plan.ExpectedProfit += allocation.ExpectedProfit
+		plan.Allocations = append(plan.Allocations, allocation)
+	}
+	if plan.Invested > 0 {
+		plan.ROI = float64(plan.ExpectedProfit) / float64(plan.Invested) * 100
+	}
+	sort.Slice(plan.Allocations, func(a, b int) bool {
+		if plan.Allocations[a].ExpectedProfit != plan.Allocations[b].ExpectedProfit {
+			return plan.Allocations[a].ExpectedProfit > plan.Allocations[b].ExpectedProfit
+		}
+		return plan.Allocations[a].ItemID < plan.Allocations[b].ItemID
+	})
+	return plan
+}
+
+// allocationCandidates caps the quantity of each profitable flip and keeps
+// the maxAllocationCandidates that could make the most profit on their own
+func allocationCandidates(flips []Flip, req AllocationRequest, profile riskProfile) []allocationCandidate {
+	windows := int(math.Ceil(req.Horizon.Hours() / BuyLimitWindow.Hours()))
+
+	candidates := make([]allocationCandidate, 0, len(flips))
+	for _, flip := range flips {
+		if flip.Profit <= 0 || flip.BuyPrice <= 0 {
+			continue
+		}
+		if profile.avoidDown && flip.Trend == TrendDown {
+			continue
+		}
+		if profile.avoidAnomalies && len(flip.Anomalies) > 0 {
+			continue
+		}
+
+		cand := allocationCandidate{
+			flip:      flip,
+			maxQty:    int(float64(req.Cash) * profile.maxShare / float64(flip.BuyPrice)),
+			limitedBy: LimitedByConcentration,
+		}
+		if flip.BuyLimit > 0 {
+			limit := flip.BuyLimit * windows
+			if flip.LimitRemaining != nil {
+				limit = *flip.LimitRemaining + flip.BuyLimit*(windows-1)
+			}
+			if limit < cand.maxQty {
+				cand.maxQty, cand.limitedBy = limit, LimitedByBuyLimit
+			}
+		}
+		// Volume is per day; zero means the provider did not report it
+		if flip.Volume > 0 {
+			volume := int(float64(flip.Volume) * req.Horizon.Hours() / 24 * profile.volumeShare)
+			if volume < cand.maxQty {
+				cand.maxQty, cand.limitedBy = volume, LimitedByVolume
+			}
+		}
+		if cand.maxQty > 0 {
+			candidates = append(candidates, cand)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i].maxQty*candidates[i].flip.Profit, candidates[j].maxQty*candidates[j].flip.Profit
+		if a != b {
+			return a > b
+		}
+		return candidates[i].flip.ItemID < candidates[j].flip.ItemID
+	})
+	if len(candidates) > maxAllocationCandidates {
+		candidates = candidates[:maxAllocationCandidates]
+	}
+	return candidates
+}
+
+// profitPerGP is the post-tax profit of a flip per gp invested
+func profitPerGP(flip Flip) float64 {
+	return float64(flip.Profit) / float64(flip.BuyPrice)
+}
+
+// ceilDiv divides rounding up
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
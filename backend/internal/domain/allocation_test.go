@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestOptimizeAllocationCaps(t *testing.T) {
+	tests := []struct {
+		name  string
+		flips []Flip
+		req   AllocationRequest
+		want  []Allocation // Only ItemID, Quantity and LimitedBy are compared
+	}{
+		{
+			name:  "buy limit over the horizon",
+			flips: []Flip{{ItemID: 1, BuyPrice: 100, Profit: 10, BuyLimit: 5}},
+			req:   AllocationRequest{Cash: 100000, Slots: 8, Risk: RiskHigh, Horizon: 8 * time.Hour},
+			want:  []Allocation{{ItemID: 1, Quantity: 10, LimitedBy: LimitedByBuyLimit}},
+		},
+		{
+			name:  "remaining buy limit in the first window",
+			flips: []Flip{{ItemID: 1, BuyPrice: 100, Profit: 10, BuyLimit: 5, LimitRemaining: intPtr(2)}},
+			req:   AllocationRequest{Cash: 100000, Slots: 8, Risk: RiskHigh, Horizon: 8 * time.Hour},
+			want:  []Allocation{{ItemID: 1, Quantity: 7, LimitedBy: LimitedByBuyLimit}},
+		},
+		{
+			name:  "share of the volume",
+			flips: []Flip{{ItemID: 1, BuyPrice: 100, Profit: 10, BuyLimit: 1000, Volume: 240}},
+			req:   AllocationRequest{Cash: 100000, Slots: 8, Risk: RiskHigh, Horizon: 4 * time.Hour},
+			want:  []Allocation{{ItemID: 1, Quantity: 10, LimitedBy: LimitedByVolume}},
+		},
+		{
+			name:  "concentration",
+			flips: []Flip{{ItemID: 1, BuyPrice: 100, Profit: 10}},
+			req:   AllocationRequest{Cash: 10000, Slots: 8, Risk: RiskMedium, Horizon: 4 * time.Hour},
+			want:  []Allocation{{ItemID: 1, Quantity: 35, LimitedBy: LimitedByConcentration}},
+		},
+		{
+			name: "cash",
+			flips: []Flip{
+				{ItemID: 1, BuyPrice: 10, Profit: 5},
+				{ItemID: 2, BuyPrice: 10, Profit: 4},
+			},
+			req: AllocationRequest{Cash: 1000, Slots: 8, Risk: RiskHigh, Horizon: 4 * time.Hour},
+			want: []Allocation{
+				{ItemID: 1, Quantity: 60, LimitedBy: LimitedByConcentration},
+				{ItemID: 2, Quantity: 40, LimitedBy: LimitedByCash},
+			},
+		},
+		{
+			name: "slots",
+			flips: []Flip{
+				{ItemID: 1, BuyPrice: 100, Profit: 10, BuyLimit: 10},
+				{ItemID: 2, BuyPrice: 100, Profit: 30, BuyLimit: 10},
+				{ItemID: 3, BuyPrice: 100, Profit: 20, BuyLimit: 10},
+			},
+			req: AllocationRequest{Cash: 100000, Slots: 2, Risk: RiskHigh, Horizon: 4 * time.Hour},
+			want: []Allocation{
+				{ItemID: 2, Quantity: 10, LimitedBy: LimitedByBuyLimit},
+				{ItemID: 3, Quantity: 10, LimitedBy: LimitedByBuyLimit},
+			},
+		},
+		{
+			name: "ties go to the lower item ID",
+			flips: []Flip{
+				{ItemID: 7, BuyPrice: 100, Profit: 10, BuyLimit: 10},
+				{ItemID: 3, BuyPrice: 100, Profit: 10, BuyLimit: 10},
+			},
+			req:  AllocationRequest{Cash: 100000, Slots: 1, Risk: RiskHigh, Horizon: 4 * time.Hour},
+			want: []Allocation{{ItemID: 3, Quantity: 10, LimitedBy: LimitedByBuyLimit}},
+		},
+		{
+			name: "equal expected profits listed by item ID",
+			flips: []Flip{
+				{ItemID: 7, BuyPrice: 100, Profit: 10, BuyLimit: 10},
+				{ItemID: 3, BuyPrice: 50, Profit: 20, BuyLimit: 5},
+			},
+			req: AllocationRequest{Cash: 100000, Slots: 2, Risk: RiskHigh, Horizon: 4 * time.Hour},
+			want: []Allocation{
+				{ItemID: 3, Quantity: 5, LimitedBy: LimitedByBuyLimit},
+				{ItemID: 7, Quantity: 10, LimitedBy: LimitedByBuyLimit},
+			},
+		},
+		{
+			name: "low risk skips falling and flagged items",
+			flips: []Flip{
+				{ItemID: 1, BuyPrice: 100, Profit: 50, BuyLimit: 10, Trend: TrendDown},
+				{ItemID: 2, BuyPrice: 100, Profit: 50, BuyLimit: 10, Anomalies: []AnomalyType{AnomalyPriceSpike}},
+				{ItemID: 3, BuyPrice: 100, Profit: 5, BuyLimit: 10},
+			},
+			req:  AllocationRequest{Cash: 100000, Slots: 8, Risk: RiskLow, Horizon: 4 * time.Hour},
+			want: []Allocation{{ItemID: 3, Quantity: 10, LimitedBy: LimitedByBuyLimit}},
+		},
+		{
+			name: "unprofitable items are skipped",
+			flips: []Flip{
+				{ItemID: 1, BuyPrice: 100, Profit: 0, BuyLimit: 10},
+				{ItemID: 2, BuyPrice: 100, Profit: -5, BuyLimit: 10},
+			},
+			req:  AllocationRequest{Cash: 100000, Slots: 8, Risk: RiskHigh, Horizon: 4 * time.Hour},
+			want: []Allocation{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := OptimizeAllocation(tt.flips, tt.req)
+			if len(plan.Allocations) != len(tt.want) {
+				t.Fatalf("allocations = %+v, want %+v", plan.Allocations, tt.want)
+			}
+			for i, want := range tt.want {
+				got := plan.Allocations[i]
+				if got.ItemID != want.ItemID || got.Quantity != want.Quantity || got.LimitedBy != want.LimitedBy {
+					t.Errorf("allocation %d = item %d x%d limited by %s, want item %d x%d limited by %s",
+						i, got.ItemID, got.Quantity, got.LimitedBy, want.ItemID, want.Quantity, want.LimitedBy)
+				}
+			}
+		})
+	}
+}
+
+// TestOptimizeAllocationMatchesBruteForce compares plans against every
+// combination of quantities on small random inputs. Below 200gp of cash an
+// allocation unit is 1gp, so the knapsack is exact.
+func TestOptimizeAllocationMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(46))
+	for n := 0; n < 300; n++ {
+		flips := make([]Flip, 2+rng.Intn(4))
+		for i := range flips {
+			price := 5 + rng.Intn(56)
+			flips[i] = Flip{
+				ItemID:   i + 1,
+				BuyPrice: price,
+				Profit:   1 + rng.Intn(price/2+1),
+				BuyLimit: rng.Intn(12),
+			}
+		}
+		req := AllocationRequest{
+			Cash:    50 + rng.Intn(150),
+			Slots:   1 + rng.Intn(3),
+			Risk:    RiskHigh,
+			Horizon: 4 * time.Hour,
+		}
+
+		plan := OptimizeAllocation(flips, req)
+		checkPlan(t, flips, req, plan)
+
+		candidates := allocationCandidates(flips, req, riskProfiles[req.Risk])
+		if want := bruteForceProfit(candidates, req.Slots, req.Cash); plan.ExpectedProfit != want {
+			t.Fatalf("case %d: expected profit = %d, brute force = %d\nflips: %+v\nrequest: %+v\nplan: %+v",
+				n, plan.ExpectedProfit, want, flips, req, plan.Allocations)
+		}
+	}
+}
+
+// TestOptimizeAllocationStaysWithinCaps checks larger random inputs, where
+// the knapsack is approximate, against the slot, cash and per-item caps
+func TestOptimizeAllocationStaysWithinCaps(t *testing.T) {
+	rng := rand.New(rand.NewSource(47))
+	risks := []RiskTolerance{RiskLow, RiskMedium, RiskHigh}
+	for n := 0; n < 100; n++ {
+		flips := make([]Flip, 1+rng.Intn(30))
+		for i := range flips {
+			price := 1 + rng.Intn(100000)
+			flips[i] = Flip{
+				ItemID:   i + 1,
+				BuyPrice: price,
+				Profit:   rng.Intn(price/10+2) - 1,
+				BuyLimit: rng.Intn(20000),
+				Volume:   rng.Intn(1000000),
+			}
+			if rng.Intn(3) == 0 {
+				flips[i].LimitRemaining = intPtr(rng.Intn(flips[i].BuyLimit + 1))
+			}
+		}
+		req := AllocationRequest{
+			Cash:    1 + rng.Intn(500000000),
+			Slots:   1 + rng.Intn(GESlots),
+			Risk:    risks[rng.Intn(len(risks))],
+			Horizon: time.Duration(1+rng.Intn(48)) * time.Hour,
+		}
+		checkPlan(t, flips, req, OptimizeAllocation(flips, req))
+	}
+}
+
+// checkPlan fails the test when plan breaks one of the request's caps
+func checkPlan(t *testing.T, flips []Flip, req AllocationRequest, plan AllocationPlan) {
+	t.Helper()
+	if len(plan.Allocations) > req.Slots {
+		t.Fatalf("%d allocations for %d slots", len(plan.Allocations), req.Slots)
+	}
+	if plan.Invested > req.Cash {
+		t.Fatalf("invested %d of %d cash", plan.Invested, req.Cash)
+	}
+
+	maxQty := make(map[int]int)
+	for _, cand := range allocationCandidates(flips, req, riskProfiles[req.Risk]) {
+		maxQty[cand.flip.ItemID] = cand.maxQty
+	}
+	for _, a := range plan.Allocations {
+		if a.Quantity <= 0 || a.Quantity > maxQty[a.ItemID] {
+			t.Fatalf("item %d: quantity %d outside 1..%d", a.ItemID, a.Quantity, maxQty[a.ItemID])
+		}
+	}
+}
+
+// bruteForceProfit returns the highest profit of any quantities of at most
+// slots candidates costing at most cash
+func bruteForceProfit(candidates []allocationCandidate, slots, cash int) int {
+	if len(candidates) == 0 || slots == 0 {
+		return 0
+	}
+	cand, rest := candidates[0], candidates[1:]
+	best := bruteForceProfit(rest, slots, cash)
+	for qty := 1; qty <= cand.maxQty && qty*cand.flip.BuyPrice <= cash; qty++ {
+		profit := qty*cand.flip.Profit + bruteForceProfit(rest, slots-1, cash-qty*cand.flip.BuyPrice)
+		best = max(best, profit)
+	}
+	return best
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// AnomalyType names a kind of suspicious price movement
+type AnomalyType string
+
+const (
+	// AnomalyPriceSpike is a price change far outside the item's usual volatility
+	AnomalyPriceSpike AnomalyType = "price_spike"
+	// AnomalyVolumeBurst is a traded volume many times the item's usual volume
+	AnomalyVolumeBurst AnomalyType = "volume_burst"
+	// AnomalySpreadBlowout is a gap between the high and low prices far wider than usual
+	AnomalySpreadBlowout AnomalyType = "spread_blowout"
+)
+
+const (
+	// AnomalyTTL is how long a flag stays on an item after it was last detected
+	AnomalyTTL = 2 * time.Hour
+
+	// anomalyWindow is the span, in updates, of the rolling statistics
+	anomalyWindow = 48
+	// anomalyMinSamples is how many updates an item needs before it is checked
+	anomalyMinSamples = 12
+	// priceSpikeZ and spreadBlowoutZ are the z-scores that flag an update
+	priceSpikeZ    = 4.0
+	spreadBlowoutZ = 4.0
+	// volumeBurstRatio is the multiple of the usual volume that flags an update
+	volumeBurstRatio = 5.0
+	// minReturnStdDev and minSpreadStdDev keep flat items from flagging
+	// ordinary ticks: price moves under 0.5% and spread changes under 0.5
+	// points never count as far from the mean
+	minReturnStdDev = 0.005
+	minSpreadStdDev = 0.005
+)
+
+// Anomaly flags a suspicious movement of an item's price or volume
+type Anomaly struct {
+	Type AnomalyType `json:"type"`
+	// Score is the z-score of the update (the volume ratio for volume
+	// bursts); negative price spikes are drops
+	Score      float64   `json:"score"`
+	Value      float64   `json:"value"`    // The update's price change, volume or spread
+	Baseline   float64   `json:"baseline"` // The rolling mean it was compared with
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// AnomalyStats are the rolling statistics the detector keeps per item:
+// exponentially weighted means and variances over anomalyWindow updates
+type AnomalyStats struct {
+	Samples    int     `json:"samples"`
+	ReturnMean float64 `json:"return_mean"` // Log change of the price per update
+	ReturnVar  float64 `json:"return_var"`
+	VolumeMean float64 `json:"volume_mean"`
+	SpreadMean float64 `json:"spread_mean"` // (high - low) / low
+	SpreadVar  float64 `json:"spread_var"`
+}
+
+// DetectAnomalies checks an updated item against the rolling statistics of
+// its previous state, then sets the item's flags and statistics. Flags not
+// detected again are kept until AnomalyTTL after they were last detected.
+func DetectAnomalies(previous ItemPrice, item *ItemPrice, now time.Time) {
+	stats := previous.AnomalyStats
+	var detected []Anomaly
+
+	ret, hasReturn := 0.0, previous.Price > 0 && item.Price > 0
+	if hasReturn {
+		ret = math.Log(float64(item.Price) / float64(previous.Price))
+	}
+	volume := float64(item.Volume)
+	spread, hasSpread := 0.0, item.Low > 0 && item.High > 0
+	if hasSpread {
+		spread = float64(item.High-item.Low) / float64(item.Low)
+	}
+
+	if stats.Samples >= anomalyMinSamples {
+		if hasReturn {
+			if z := zScore(ret, stats.ReturnMean, stats.ReturnVar, minReturnStdDev); math.Abs(z) >= priceSpikeZ {
+				detected = append(detected, Anomaly{Type: AnomalyPriceSpike, Score: z, Value: ret, Baseline: stats.ReturnMean, DetectedAt: now})
+				// Outliers enter the statistics clamped to the threshold, so
+				// one does not hide the next
+				ret = stats.ReturnMean + math.Copysign(priceSpikeZ, z)*math.Max(math.Sqrt(stats.ReturnVar), minReturnStdDev)
+			}
+		}
+		if item.Volume > 0 && stats.VolumeMean > 0 {
+			if ratio := float64(item.Volume) / stats.VolumeMean; ratio >= volumeBurstRatio {
+				detected = append(detected, Anomaly{Type: AnomalyVolumeBurst, Score: ratio, Value: volume, Baseline: stats.VolumeMean, DetectedAt: now})
+				volume = stats.VolumeMean * volumeBurstRatio
+			}
+		}
+		if hasSpread {
+			if z := zScore(spread, stats.SpreadMean, stats.SpreadVar, minSpreadStdDev); z >= spreadBlowoutZ {
+				detected = append(detected, Anomaly{Type: AnomalySpreadBlowout, Score: z, Value: spread, Baseline: stats.SpreadMean, DetectedAt: now})
+				spread = stats.SpreadMean + spreadBlowoutZ*math.Max(math.Sqrt(stats.SpreadVar), minSpreadStdDev)
+			}
+		}
+	}
+
+	// The first update of an item has no previous price to compare with
+	if previous.Price > 0 {
+		stats.Samples++
+	}
+	alpha := 2.0 / (anomalyWindow + 1)
+	if stats.Samples <= 1 {
+		alpha = 1
+	}
+	if hasReturn {
+		stats.ReturnMean, stats.ReturnVar = ewma(stats.ReturnMean, stats.ReturnVar, ret, alpha)
+	}
+	if item.Volume > 0 {
+		stats.VolumeMean += alpha * (volume - stats.VolumeMean)
+	}
+	if hasSpread {
+		stats.SpreadMean, stats.SpreadVar = ewma(stats.SpreadMean, stats.SpreadVar, spread, alpha)
+	}
+	item.AnomalyStats = stats
+
+	item.Anomalies = detected
+	for _, anomaly := range previous.Anomalies {
+		if now.Sub(anomaly.DetectedAt) < AnomalyTTL && !hasAnomaly(detected, anomaly.Type) {
+			item.Anomalies = append(item.Anomalies, anomaly)
+		}
+	}
+	sort.Slice(item.Anomalies, func(i, j int) bool { return item.Anomalies[i].Type < item.Anomalies[j].Type })
+}
+
+// Suspicious reports whether the item carries any anomaly flag
+func (i ItemPrice) Suspicious() bool {
+	return len(i.Anomalies) > 0
+}
+
+// AnomalyTypes returns the types of the item's flags
+func (i ItemPrice) AnomalyTypes() []AnomalyType {
+	if len(i.Anomalies) == 0 {
+		return nil
+	}
+	types := make([]AnomalyType, len(i.Anomalies))
+	for j, anomaly := range i.Anomalies {
+		types[j] = anomaly.Type
+	}
+	return types
+}
+
+// HasAnomaly reports whether the item carries a flag of type t
+func (i ItemPrice) HasAnomaly(t AnomalyType) bool {
+	return hasAnomaly(i.Anomalies, t)
+}
+
+func hasAnomaly(anomalies []Anomaly, t AnomalyType) bool {
+	for _, anomaly := range anomalies {
+		if anomaly.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// zScore is how many standard deviations x is from mean, with the standard
+// deviation floored at minStdDev
+func zScore(x, mean, variance, minStdDev float64) float64 {
+	return (x - mean) / math.Max(math.Sqrt(variance), minStdDev)
+}
+
+// ewma adds x to an exponentially weighted mean and variance
+func ewma(mean, variance, x, alpha float64) (float64, float64) {
+	diff := x - mean
+	mean += alpha * diff
+	variance = (1 - alpha) * (variance + alpha*diff*diff)
+	return mean, variance
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// anomalyStep is the interval between synthetic price updates
+const anomalyStep = 5 * time.Minute
+
+var anomalyStart = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// seasonalItems returns n updates of an item whose price follows a daily
+// cycle of ±3% with 0.2% noise, with a 2% spread and volume around 1000
+func seasonalItems(n int, seed int64) []ItemPrice {
+	rng := rand.New(rand.NewSource(seed))
+	perDay := int(24 * time.Hour / anomalyStep)
+	items := make([]ItemPrice, n)
+	for t := range items {
+		price := 10000 * (1 + 0.03*math.Sin(2*math.Pi*float64(t)/float64(perDay))) * (1 + 0.002*rng.NormFloat64())
+		spread := 0.02 * (1 + 0.05*rng.NormFloat64())
+		items[t] = ItemPrice{
+			ItemID: 1,
+			Price:  int(price),
+			Low:    int(price),
+			High:   int(price * (1 + spread)),
+			Volume: 1000 + rng.Intn(400) - 200,
+		}
+	}
+	return items
+}
+
+// replayAnomalies runs the detector over items in order and returns, per anomaly
+// type, the indices of the updates where it was detected
+func replayAnomalies(items []ItemPrice) (map[AnomalyType][]int, []ItemPrice) {
+	detected := make(map[AnomalyType][]int)
+	results := make([]ItemPrice, len(items))
+	previous := ItemPrice{}
+	for t := range items {
+		now := anomalyStart.Add(time.Duration(t) * anomalyStep)
+		item := items[t]
+		DetectAnomalies(previous, &item, now)
+		for _, anomaly := range item.Anomalies {
+			if anomaly.DetectedAt.Equal(now) {
+				detected[anomaly.Type] = append(detected[anomaly.Type], t)
+			}
+		}
+		results[t] = item
+		previous = item
+	}
+	return detected, results
+}
+
+func TestDetectAnomaliesCleanSeries(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		detected, _ := replayAnomalies(seasonalItems(3*288, seed))
+		if len(detected) > 0 {
+			t.Errorf("seed %d: clean seasonal series flagged at %v", seed, detected)
+		}
+	}
+}
+
+func TestDetectAnomaliesPlanted(t *testing.T) {
+	items := seasonalItems(2*288, 48)
+
+	// A price spike is flagged when it happens and again when the price
+	// falls back, as a move as large the other way
+	for _, at := range []int{100, 300, 301} {
+		items[at].Price = items[at].Price * 13 / 10
+	}
+	for _, at := range []int{150, 400} {
+		items[at].Volume *= 8
+	}
+	for _, at := range []int{200, 450} {
+		items[at].High = items[at].Low * 112 / 100
+	}
+	// Too early for the detector, which waits for anomalyMinSamples updates
+	items[5].Price *= 2
+
+	detected, results := replayAnomalies(items)
+
+	want := map[AnomalyType][]int{
+		AnomalyPriceSpike:    {100, 101, 300, 302},
+		AnomalyVolumeBurst:   {150, 400},
+		AnomalySpreadBlowout: {200, 450},
+	}
+	if !reflect.DeepEqual(detected, want) {
+		t.Errorf("detected = %v, want %v", detected, want)
+	}
+
+	spike := findAnomaly(results[100], AnomalyPriceSpike)
+	if spike.Score < priceSpikeZ || math.Abs(spike.Value-math.Log(1.3)) > 0.01 {
+		t.Errorf("spike at 100 = %+v, want a score of at least %v and a change of about log(1.3)", spike, priceSpikeZ)
+	}
+	if drop := findAnomaly(results[101], AnomalyPriceSpike); drop.Score > -priceSpikeZ {
+		t.Errorf("drop at 101 = %+v, want a score of at most %v", drop, -priceSpikeZ)
+	}
+	if burst := findAnomaly(results[150], AnomalyVolumeBurst); burst.Score < volumeBurstRatio || burst.Baseline < 800 || burst.Baseline > 1200 {
+		t.Errorf("burst at 150 = %+v, want a ratio of at least %v over a baseline near 1000", burst, volumeBurstRatio)
+	}
+}
+
+func TestDetectAnomaliesTTL(t *testing.T) {
+	items := seasonalItems(288, 7)
+	items[100].Volume *= 10
+	_, results := replayAnomalies(items)
+
+	ttlSteps := int(AnomalyTTL / anomalyStep)
+	for _, at := range []int{100, 101, 100 + ttlSteps - 1} {
+		if !results[at].HasAnomaly(AnomalyVolumeBurst) {
+			t.Errorf("update %d: volume burst flag missing", at)
+		}
+		if got := findAnomaly(results[at], AnomalyVolumeBurst).DetectedAt; !got.Equal(anomalyStart.Add(100 * anomalyStep)) {
+			t.Errorf("update %d: flag detected at %v, want the time of update 100", at, got)
+		}
+	}
+	if results[100+ttlSteps].Suspicious() {
+		t.Errorf("update %d: flags %v kept past the TTL", 100+ttlSteps, results[100+ttlSteps].AnomalyTypes())
+	}
+}
+
+func TestDetectAnomaliesFlagsSorted(t *testing.T) {
+	items := seasonalItems(100, 3)
+	items[50].Price = items[50].Price * 15 / 10
+	items[50].Low = items[50].Price
+	items[50].High = items[50].Price * 115 / 100
+	items[50].Volume *= 10
+	_, results := replayAnomalies(items)
+
+	types := results[50].AnomalyTypes()
+	want := []AnomalyType{AnomalyPriceSpike, AnomalySpreadBlowout, AnomalyVolumeBurst}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("flags = %v, want %v", types, want)
+	}
+}
+
+func findAnomaly(item ItemPrice, t AnomalyType) Anomaly {
+	for _, anomaly := range item.Anomalies {
+		if anomaly.Type == t {
+			return anomaly
+		}
+	}
+	return Anomaly{}
+}
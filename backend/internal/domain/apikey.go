@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAPIKeyNotFound is returned when no API key matches an ID or hash
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrAPIKeyRevoked is returned when a revoked key is used
+	ErrAPIKeyRevoked = errors.New("api key revoked")
+)
+
+// APIKey identifies a client of the API. Only the SHA-256 hash of the secret
+// is stored; the secret itself is shown once, when the key is created.
+type APIKey struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"` // First characters of the secret, to recognize keys in listings
+	Hash   string `json:"-"`
+	// RateLimit is the number of requests per minute per endpoint; 0 uses the configured default
+	RateLimit int `json:"rate_limit"`
+	// DailyQuota is the number of requests per UTC day; 0 uses the configured default
+	DailyQuota int        `json:"daily_quota"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Revoked reports whether the key can no longer be used
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HashAPIKey returns the hex SHA-256 of a key secret. Secrets are long
+// random strings, so a fast hash is enough and allows lookups by hash.
+func HashAPIKey(secret string) string {
+	return sha256Hex(secret)
+}
+
+// sha256Hex returns the hex-encoded SHA-256 of s
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyUsage counts the requests made with a key during one UTC day
+type APIKeyUsage struct {
+	Day      time.Time `json:"day"`
+	Requests int64     `json:"requests"` // Requests accepted within the quota
+	Rejected int64     `json:"rejected"` // Requests rejected because the quota was used up
+}
+
+// QuotaResult is the outcome of consuming one request from a daily quota
+type QuotaResult struct {
+	Allowed bool
+	Used    int64 // Requests accepted so far today, including this one when allowed
+}
+
+// UsageDay truncates t to the start of its UTC day, the period of daily quotas
+func UsageDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// APIKeyRepository defines the interface for API key storage and usage counters
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key APIKey) error
+	GetAPIKey(ctx context.Context, id string) (*APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+	// UpdateAPIKey changes the name and limits of a key
+	UpdateAPIKey(ctx context.Context, key APIKey) error
+	RevokeAPIKey(ctx context.Context, id string, at time.Time) error
+	// ConsumeAPIKeyQuota atomically counts one request for the key on the day
+	// of at, accepting it while fewer than quota requests were accepted that
+	// day (quota <= 0 means unlimited) and counting it as rejected otherwise
+	ConsumeAPIKeyQuota(ctx context.Context, id string, at time.Time, quota int) (QuotaResult, error)
+	// GetAPIKeyUsage returns the daily counters of the last days, newest first
+	GetAPIKeyUsage(ctx context.Context, id string, days int) ([]APIKeyUsage, error)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// BuyLimitWindow is how long a purchase counts against the item's GE buy limit
+const BuyLimitWindow = 4 * time.Hour
+
+// BuyLimitStatus is how much of an item's buy limit the user has left. The
+// window is rolling: each purchase counts for BuyLimitWindow after it was made.
+type BuyLimitStatus struct {
+	ItemID int    `json:"item_id"`
+	Name   string `json:"name,omitempty"`
+	Limit  int    `json:"limit"` // 0 when the item's limit is unknown
+	Bought int    `json:"bought"`
+	// Remaining is nil when the limit is unknown
+	Remaining *int `json:"remaining"`
+	// ResetsAt is when the oldest purchase in the window stops counting and
+	// FullResetAt when the last one does; both nil without purchases
+	ResetsAt    *time.Time `json:"resets_at"`
+	FullResetAt *time.Time `json:"full_reset_at"`
+	Capped      bool       `json:"capped"` // No quantity left until ResetsAt
+}
+
+// NewBuyLimitStatus computes the status of item's buy limit at now from the
+// user's buys of it. Buys outside the window and trades of other items or
+// sides are ignored.
+func NewBuyLimitStatus(item ItemPrice, trades []Trade, now time.Time) BuyLimitStatus {
+	status := BuyLimitStatus{ItemID: item.ItemID, Name: item.Name, Limit: item.BuyLimit}
+
+	since := now.Add(-BuyLimitWindow)
+	var oldest, newest time.Time
+	for _, trade := range trades {
+		if trade.ItemID != item.ItemID || trade.Side != TradeBuy || !trade.TradedAt.After(since) {
+			continue
+		}
+		status.Bought += trade.Quantity
+		if oldest.IsZero() || trade.TradedAt.Before(oldest) {
+			oldest = trade.TradedAt
+		}
+		if trade.TradedAt.After(newest) {
+			newest = trade.TradedAt
+		}
+	}
+
+	if status.Bought > 0 {
+		resetsAt, fullResetAt := oldest.Add(BuyLimitWindow), newest.Add(BuyLimitWindow)
+		status.ResetsAt, status.FullResetAt = &resetsAt, &fullResetAt
+	}
+	if status.Limit > 0 {
+		remaining := max(status.Limit-status.Bought, 0)
+		status.Remaining = &remaining
+		status.Capped = remaining == 0
+	}
+	return status
+}
+
+// BuyLimitStatuses computes the status of every item bought within the
+// window at now, soonest reset first. items holds the bought items' prices;
+// items missing from it have an unknown limit.
+func BuyLimitStatuses(trades []Trade, items map[int]ItemPrice, now time.Time) []BuyLimitStatus {
+	since := now.Add(-BuyLimitWindow)
+	byItem := make(map[int][]Trade)
+	for _, trade := range trades {
+		if trade.Side == TradeBuy && trade.TradedAt.After(since) {
+			byItem[trade.ItemID] = append(byItem[trade.ItemID], trade)
+		}
+	}
+
+	statuses := make([]BuyLimitStatus, 0, len(byItem))
+	for id, bought := range byItem {
+		item, ok := items[id]
+		if !ok {
+			item = ItemPrice{ItemID: id}
+		}
+		statuses = append(statuses, NewBuyLimitStatus(item, bought, now))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if !a.ResetsAt.Equal(*b.ResetsAt) {
+			return a.ResetsAt.Before(*b.ResetsAt)
+		}
+		return a.ItemID < b.ItemID
+	})
+	return statuses
+}
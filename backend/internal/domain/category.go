@@ -0,0 +1,329 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Category groups items traded for similar reasons
+type Category string
+
+const (
+	CategoryWeapons          Category = "weapons"
+	CategoryArmour           Category = "armour"
+	CategoryRunes            Category = "runes"
+	CategoryHerbs            Category = "herbs"
+	CategoryPotions          Category = "potions"
+	CategoryRaidsUniques     Category = "raids_uniques"
+	CategorySkillingSupplies Category = "skilling_supplies"
+	// CategoryOther holds the items no mapping entry or heuristic places
+	CategoryOther Category = "other"
+)
+
+// Categories lists every category in display order
+var Categories = []Category{
+	CategoryWeapons, CategoryArmour, CategoryRunes, CategoryHerbs, CategoryPotions,
+	CategoryRaidsUniques, CategorySkillingSupplies, CategoryOther,
+}
+
+// Valid reports whether c is one of Categories
+func (c Category) Valid() bool {
+	for _, category := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// CatalogEntry places an item by ID or, when ItemID is 0, by name. An empty
+// Category only adds Tags, leaving the category to the heuristics.
+type CatalogEntry struct {
+	ItemID   int
+	Name     string // Matched ignoring case
+	Category Category
+	Tags     []string
+}
+
+// Catalog assigns categories and tags to items: entries of the maintained
+// mapping first, then heuristics on the item name. A nil Catalog uses the
+// heuristics alone.
+type Catalog struct {
+	byID   map[int]CatalogEntry
+	byName map[string]CatalogEntry
+}
+
+// NewCatalog indexes entries; later entries replace earlier ones for the same item
+func NewCatalog(entries []CatalogEntry) *Catalog {
+	c := &Catalog{
+		byID:   make(map[int]CatalogEntry),
+		byName: make(map[string]CatalogEntry),
+	}
+	for _, entry := range entries {
+		if entry.ItemID > 0 {
+			c.byID[entry.ItemID] = entry
+		} else if entry.Name != "" {
+			c.byName[normalizeName(entry.Name)] = entry
+		}
+	}
+	return c
+}
+
+// Categorize returns the category and sorted tags of an item. An entry
+// matching the item's ID takes precedence over one matching its name.
+func (c *Catalog) Categorize(itemID int, name string) (Category, []string) {
+	category, tags := categorizeByName(name)
+	if c == nil {
+		return category, tags
+	}
+
+	entry, ok := c.byID[itemID]
+	if !ok {
+		entry, ok = c.byName[normalizeName(name)]
+	}
+	if !ok {
+		return category, tags
+	}
+	if entry.Category != "" {
+		// The heuristic tags describe the heuristic category, so they go with it
+		category, tags = entry.Category, nil
+	}
+	return category, mergeTags(tags, entry.Tags)
+}
+
+// Apply sets the item's category and tags
+func (c *Catalog) Apply(item *ItemPrice) {
+	item.Category, item.Tags = c.Categorize(item.ItemID, item.Name)
+}
+
+// HasTag reports whether the item carries tag, ignoring case
+func (i ItemPrice) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name heuristics. Names are matched lowercased, mostly on their head: the
+// last word before any " of " and trailing parentheses, so "Dragon
+// dagger(p++)" is a dagger and "Staff of the dead" a staff.
+var (
+	doseSuffix  = regexp.MustCompile(`\(\d\)$`)
+	parentheses = regexp.MustCompile(`\s*\([^)]*\)`)
+
+	herbNames = map[string]bool{
+		"guam leaf": true, "marrentill": true, "tarromin": true, "harralander": true,
+		"ranarr weed": true, "toadflax": true, "irit leaf": true, "avantoe": true,
+		"kwuarm": true, "huasca": true, "snapdragon": true, "cadantine": true,
+		"lantadyme": true, "dwarf weed": true, "torstol": true,
+	}
+	// combinationRunes are the first words of runes of two elements
+	combinationRunes = map[string]bool{
+		"mist": true, "dust": true, "mud": true, "smoke": true, "steam": true, "lava": true,
+	}
+	jewelleryHeads = map[string]bool{
+		"amulet": true, "necklace": true, "ring": true, "bracelet": true,
+	}
+	// ammoHeads are ranged weapons thrown or fired by the stack
+	ammoHeads = map[string]bool{
+		"arrow": true, "arrows": true, "bolt": true, "bolts": true, "dart": true,
+		"javelin": true, "knife": true, "thrownaxe": true, "chinchompa": true,
+	}
+	// weaponHeads maps weapon heads to their combat style
+	weaponHeads = map[string]string{
+		"scimitar": "melee", "sword": "melee", "longsword": "melee", "dagger": "melee",
+		"whip": "melee", "mace": "melee", "battleaxe": "melee", "greataxe": "melee",
+		"warhammer": "melee", "hammer": "melee", "halberd": "melee", "spear": "melee",
+		"hasta": "melee", "maul": "melee", "claws": "melee", "godsword": "melee",
+		"scythe": "melee", "rapier": "melee", "bludgeon": "melee", "tentacle": "melee",
+		"blade": "melee", "flail": "melee", "katana": "melee", "fang": "melee",
+		"bulwark": "melee", "sickle": "melee", "club": "melee",
+		"bow": "ranged", "shortbow": "ranged", "longbow": "ranged", "crossbow": "ranged",
+		"blowpipe": "ranged", "ballista": "ranged",
+		"staff": "magic", "battlestaff": "magic", "wand": "magic", "trident": "magic",
+		"sceptre": "magic",
+	}
+	// armourHeads maps armour heads to their combat style; "" fits any style
+	armourHeads = map[string]string{
+		"helm": "melee", "helmet": "melee", "platebody": "melee", "platelegs": "melee",
+		"plateskirt": "melee", "chainbody": "melee", "chestplate": "melee",
+		"chainskirt": "melee", "tassets": "melee", "kiteshield": "melee",
+		"defender": "melee", "faceguard": "melee", "chestguard": "melee",
+		"legguards": "melee",
+		"chaps":     "ranged", "coif": "ranged", "vambraces": "ranged",
+		"leathertop": "ranged", "leatherskirt": "ranged",
+		"robe": "magic", "robetop": "magic", "robeskirt": "magic", "hat": "magic",
+		"hood":   "magic",
+		"shield": "", "boots": "", "gloves": "", "body": "", "legs": "", "top": "",
+		"skirt": "", "bottom": "", "bottoms": "", "cape": "", "cloak": "", "mask": "",
+		"gauntlets": "", "greaves": "", "bracers": "",
+	}
+	// toolHeads are skilling tools
+	toolHeads = map[string]bool{
+		"axe": true, "pickaxe": true, "harpoon": true,
+	}
+	// supplyHeads maps skilling supplies to the skill using them
+	supplyHeads = map[string]string{
+		"logs": "woodcutting", "ore": "mining", "coal": "mining", "bar": "smithing",
+		"essence": "runecraft", "bones": "prayer", "ashes": "prayer",
+		"hide": "crafting", "leather": "crafting", "flax": "crafting",
+		"bowstring": "fletching", "feather": "fletching", "feathers": "fletching",
+		"arrowtips": "fletching", "tips": "fletching", "shaft": "fletching", "shafts": "fletching",
+		"plank": "construction", "seaweed": "crafting", "sand": "crafting", "clay": "crafting",
+		"compost": "farming", "sapling": "farming",
+	}
+)
+
+// categorizeByName guesses an item's category and tags from its name
+func categorizeByName(name string) (Category, []string) {
+	lower := normalizeName(name)
+	if lower == "" {
+		return CategoryOther, nil
+	}
+	words := strings.Fields(parentheses.ReplaceAllString(lower, ""))
+	if len(words) == 0 {
+		return CategoryOther, nil
+	}
+	first := words[0]
+	head := headWord(words)
+
+	switch {
+	case strings.HasSuffix(lower, "(unf)"):
+		return CategorySkillingSupplies, []string{"herblore"}
+	case jewelleryHeads[head]:
+		// Charged jewellery such as "Amulet of glory(4)" looks like a potion
+		return CategoryOther, []string{"jewellery"}
+	case doseSuffix.MatchString(lower):
+		return CategoryPotions, nil
+	case first == "grimy":
+		return CategoryHerbs, []string{"grimy"}
+	case herbNames[strings.Join(words, " ")]:
+		return CategoryHerbs, []string{"clean"}
+	case head == "seed" || head == "seeds":
+		return CategorySkillingSupplies, []string{"farming"}
+	case head == "rune" && len(words) > 1:
+		if combinationRunes[first] {
+			return CategoryRunes, []string{"combination"}
+		}
+		return CategoryRunes, nil
+	case ammoHeads[head]:
+		return CategoryWeapons, []string{"ammo", "ranged"}
+	case toolHeads[head]:
+		return CategorySkillingSupplies, []string{"tools"}
+	case first == "uncut":
+		return CategorySkillingSupplies, []string{"crafting"}
+	case first == "raw":
+		return CategorySkillingSupplies, []string{"cooking"}
+	}
+
+	if style, ok := weaponHeads[head]; ok {
+		return CategoryWeapons, []string{style}
+	}
+	if style, ok := armourHeads[head]; ok {
+		if style == "" && strings.Contains(lower, "d'hide") {
+			style = "ranged"
+		}
+		if style == "" {
+			return CategoryArmour, nil
+		}
+		return CategoryArmour, []string{style}
+	}
+	if skill, ok := supplyHeads[head]; ok {
+		return CategorySkillingSupplies, []string{skill}
+	}
+	return CategoryOther, nil
+}
+
+// headWord is the last word before any "of", so the noun of "Scythe of
+// vitur" is "scythe"
+func headWord(words []string) string {
+	for i, word := range words {
+		if word == "of" && i > 0 {
+			return words[i-1]
+		}
+	}
+	return words[len(words)-1]
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// mergeTags returns the sorted union of a and b, lowercased
+func mergeTags(a, b []string) []string {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, tag := range append(append([]string{}, a...), b...) {
+		tag = normalizeName(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// CategoryStats aggregates the items of a category
+type CategoryStats struct {
+	Category    Category `json:"category"`
+	Items       int      `json:"items"`
+	TotalVolume int      `json:"total_volume"`
+	// MedianMargin and MedianProfit are taken over the items with both a
+	// buy and a sell price; Priced counts them
+	Priced       int     `json:"priced"`
+	MedianMargin float64 `json:"median_margin"` // Percentage before tax
+	MedianProfit int     `json:"median_profit"` // Per item after GE tax
+}
+
+// SummarizeCategories aggregates items per category, in the order of
+// Categories. Categories without items are included with zero figures.
+func SummarizeCategories(items []ItemPrice) []CategoryStats {
+	type totals struct {
+		items, volume int
+		margins       []float64
+		profits       []int
+	}
+	byCategory := make(map[Category]*totals, len(Categories))
+	for _, category := range Categories {
+		byCategory[category] = &totals{}
+	}
+
+	for _, item := range items {
+		t, ok := byCategory[item.Category]
+		if !ok {
+			t = byCategory[CategoryOther]
+		}
+		t.items++
+		t.volume += item.Volume
+		if item.Low > 0 && item.High > 0 {
+			t.margins = append(t.margins, CalculateMargin(item.Low, item.High))
+			t.profits = append(t.profits, CalculateExpectedProfit(item.Low, item.High))
+		}
+	}
+
+	stats := make([]CategoryStats, 0, len(Categories))
+	for _, category := range Categories {
+		t := byCategory[category]
+		s := CategoryStats{Category: category, Items: t.items, TotalVolume: t.volume, Priced: len(t.margins)}
+		if n := len(t.margins); n > 0 {
+			sort.Float64s(t.margins)
+			sort.Ints(t.profits)
+			if n%2 == 1 {
+				s.MedianMargin, s.MedianProfit = t.margins[n/2], t.profits[n/2]
+			} else {
+				s.MedianMargin = (t.margins[n/2-1] + t.margins[n/2]) / 2
+				s.MedianProfit = (t.profits[n/2-1] + t.profits[n/2]) / 2
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
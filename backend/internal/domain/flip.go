@@ -0,0 +1,78 @@
+package domain
+
+// Flip represents a flipping opportunity: buy at the low price and sell at the high price
+type Flip struct {
+	ItemID    int       `json:"item_id"`
+	Name      string    `json:"name"`
+	BuyPrice  int       `json:"buy_price"`  // Instant-sell price we can buy at
+	SellPrice int       `json:"sell_price"` // Instant-buy price we can sell at
+	Margin    float64   `json:"margin"`     // Margin percentage before tax
+	Profit    int       `json:"profit"`     // Profit per item after GE tax
+	Volume    int       `json:"volume"`
+	Trend     TrendType `json:"trend"`
+	BuyLimit  int       `json:"buy_limit"` // 0 when unknown
+	Category  Category  `json:"category"`
+	// Anomalies lists the item's anomaly flags; flagged prices may be manipulated
+	Anomalies []AnomalyType `json:"anomalies,omitempty"`
+	// LimitRemaining and Capped are only set for a user's flips:
+	// the quantity they can still buy and whether that is none
+	LimitRemaining *int `json:"limit_remaining,omitempty"`
+	Capped         bool `json:"capped,omitempty"`
+}
+
+// FlipSort defines how flips are ranked
+type FlipSort string
+
+const (
+	FlipSortProfit FlipSort = "profit"
+	FlipSortMargin FlipSort = "margin"
+	FlipSortVolume FlipSort = "volume"
+)
+
+// FlipFilter represents the criteria used to select flips
+type FlipFilter struct {
+	MinMargin float64 // Minimum margin percentage
+	MinVolume int
+	SortBy    FlipSort
+	Limit     int
+	// ExcludeAnomalies drops items carrying anomaly flags
+	ExcludeAnomalies bool
+	// Category and Tag, when set, keep only the items of that category and
+	// carrying that tag
+	Category Category
+	Tag      string
+	// BuyLimits holds a user's buy limit status of the items they bought
+	// recently; when set, items they are capped on rank last
+	BuyLimits map[int]BuyLimitStatus
+}
+
+// ApplyBuyLimit sets the quantity of the flip's item the user can still buy.
+// Items the user did not buy recently have their whole limit left.
+func (f *Flip) ApplyBuyLimit(status BuyLimitStatus, bought bool) {
+	if !bought {
+		if f.BuyLimit > 0 {
+			remaining := f.BuyLimit
+			f.LimitRemaining = &remaining
+		}
+		return
+	}
+	f.LimitRemaining = status.Remaining
+	f.Capped = status.Capped
+}
+
+// NewFlip computes the flip figures of an item
+func NewFlip(item ItemPrice) Flip {
+	return Flip{
+		ItemID:    item.ItemID,
+		Name:      item.Name,
+		BuyPrice:  item.Low,
+		SellPrice: item.High,
+		Margin:    CalculateMargin(item.Low, item.High),
+		Profit:    CalculateExpectedProfit(item.Low, item.High),
+		Volume:    item.Volume,
+		Trend:     item.Trend,
+		BuyLimit:  item.BuyLimit,
+		Category:  item.Category,
+		Anomalies: item.AnomalyTypes(),
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// HealthStatus represents the health state of a component or of the service
+type HealthStatus string
+
+const (
+	HealthOK       HealthStatus = "ok"
+	HealthDegraded HealthStatus = "degraded"
+	HealthDown     HealthStatus = "down"
+)
+
+// ComponentHealth represents the health of a single dependency
+type ComponentHealth struct {
+	Status    HealthStatus `json:"status"`
+	Message   string       `json:"message,omitempty"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+// HealthReport aggregates the health of all checked components
+type HealthReport struct {
+	Status     HealthStatus               `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// UpdateStatus describes the outcome of the most recent price updates
+type UpdateStatus struct {
+	LastSuccessAt time.Time // Zero if no update has succeeded yet
+	LastErrorAt   time.Time // Zero if no update has failed yet
+	LastError     string
+	LastItemCount int
+}
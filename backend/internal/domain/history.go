@@ -1,6 +1,13 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidImport wraps the errors of an import file that cannot be read as a
+// whole, as opposed to single rejected rows
+var ErrInvalidImport = errors.New("invalid import file")
 
 // PriceHistory represents a historical price point for an item
 type PriceHistory struct {
@@ -10,6 +17,37 @@ type PriceHistory struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// HistoryImportRecord is a price observation read from an import file.
+// Err is set when the row could not be parsed.
+type HistoryImportRecord struct {
+	Line  int
+	Entry PriceHistory
+	Err   error
+}
+
+// HistoryRecordSource yields import records one at a time.
+// Next returns io.EOF after the last record.
+type HistoryRecordSource interface {
+	Next() (HistoryImportRecord, error)
+}
+
+// ImportRejection describes a row rejected during an import
+type ImportRejection struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportSummary reports the outcome of a price history import
+type ImportSummary struct {
+	DryRun     bool              `json:"dry_run"`
+	Rows       int               `json:"rows"`
+	Accepted   int               `json:"accepted"`
+	Duplicates int               `json:"duplicates"` // Repeated (item_id, timestamp) within the file
+	Rejected   int               `json:"rejected"`
+	Inserted   int               `json:"inserted"`   // Accepted rows not already stored; zero on dry runs
+	Rejections []ImportRejection `json:"rejections"` // Capped; Rejected holds the full count
+}
+
 // PriceHistoryEntry represents a single entry in the price history
 // Used for API responses with formatted date
 type PriceHistoryEntry struct {
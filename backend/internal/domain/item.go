@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // ItemPrice represents the price information for an OSRS item
 type ItemPrice struct {
@@ -13,7 +16,53 @@ type ItemPrice struct {
 	Avg24h    int       `json:"avg_24h"`
 	Avg7d     int       `json:"avg_7d"`
 	Trend     TrendType `json:"trend"`
+	BuyLimit  int       `json:"buy_limit"` // GE buy limit per 4 hours; 0 when unknown
 	UpdatedAt time.Time `json:"updated_at"`
+	// Anomalies flags suspicious movements, such as pumps, detected by the
+	// recent updates; see DetectAnomalies
+	Anomalies    []Anomaly    `json:"anomalies,omitempty"`
+	AnomalyStats AnomalyStats `json:"-"`
+	// Category and Tags are assigned by the Catalog on every update
+	Category Category `json:"category"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// MatchesQuery reports whether the item name contains query, ignoring case.
+// An empty query matches every item, like the /items search.
+func (i ItemPrice) MatchesQuery(query string) bool {
+	if query == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(i.Name), strings.ToLower(query))
+}
+
+// ItemFilter narrows an item search; zero fields match every item
+type ItemFilter struct {
+	Query    string // Substring of the name, ignoring case
+	Category Category
+	Tag      string // Matched ignoring case
+}
+
+// IsZero reports whether the filter matches every item
+func (f ItemFilter) IsZero() bool {
+	return f == ItemFilter{}
+}
+
+// Matches reports whether the item passes every field of the filter
+func (f ItemFilter) Matches(item ItemPrice) bool {
+	if f.Category != "" && item.Category != f.Category {
+		return false
+	}
+	if f.Tag != "" && !item.HasTag(f.Tag) {
+		return false
+	}
+	return item.MatchesQuery(f.Query)
+}
+
+// BatchItemResult represents the lookup result of a single ID in a batch request
+type BatchItemResult struct {
+	Found bool       `json:"found"`
+	Item  *ItemPrice `json:"item,omitempty"`
 }
 
 // TrendType represents the price trend direction
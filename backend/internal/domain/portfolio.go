@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// PortfolioHistoryTolerance is how much older than the requested time a
+// history entry may be and still stand for the price at that time
+const PortfolioHistoryTolerance = 24 * time.Hour
+
+// currencyValues prices items that are money rather than GE tradeables
+var currencyValues = map[int]int{
+	995:   1,    // Coins
+	13204: 1000, // Platinum token
+}
+
+// Holding is a quantity of an item in a bank or inventory snapshot
+type Holding struct {
+	ItemID   int `json:"item_id"`
+	Quantity int `json:"qty"`
+}
+
+// MergeHoldings sums the quantities of repeated items, keeping the order of
+// first occurrence, and drops empty holdings
+func MergeHoldings(holdings []Holding) []Holding {
+	index := make(map[int]int, len(holdings))
+	merged := make([]Holding, 0, len(holdings))
+	for _, h := range holdings {
+		if h.Quantity <= 0 {
+			continue
+		}
+		if i, ok := index[h.ItemID]; ok {
+			merged[i].Quantity += h.Quantity
+			continue
+		}
+		index[h.ItemID] = len(merged)
+		merged = append(merged, h)
+	}
+	return merged
+}
+
+// PortfolioValue is a value at the instant-buy, instant-sell and mid prices
+type PortfolioValue struct {
+	High int `json:"high"`
+	Low  int `json:"low"`
+	Mid  int `json:"mid"`
+}
+
+// PortfolioChange compares the current mid value with the value at a past
+// time. Only items with a price at that time are compared.
+type PortfolioChange struct {
+	Previous      int     `json:"previous"` // Value of the compared items at the past time
+	Change        int     `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	ItemsCompared int     `json:"items_compared"`
+
+	pricedItems int // Compared items other than currencies
+}
+
+// PortfolioItem is one holding valued at current prices
+type PortfolioItem struct {
+	ItemID   int    `json:"item_id"`
+	Name     string `json:"name,omitempty"`
+	Quantity int    `json:"qty"`
+	Found    bool   `json:"found"` // False when the item has no price; its value is 0
+	// Unit prices
+	High int `json:"high"`
+	Low  int `json:"low"`
+	Mid  int `json:"mid"`
+	PortfolioValue
+	Share     float64 `json:"share"`      // Percentage of the portfolio's mid value
+	Change24h *int    `json:"change_24h"` // Change in mid value; nil without a past price
+	Change7d  *int    `json:"change_7d"`
+}
+
+// Portfolio is the valuation of a snapshot
+type Portfolio struct {
+	Value     PortfolioValue   `json:"value"`
+	Change24h *PortfolioChange `json:"change_24h"` // nil when no item has a price from 24 hours ago
+	Change7d  *PortfolioChange `json:"change_7d"`
+	Items     []PortfolioItem  `json:"items"` // Highest mid value first
+}
+
+// BuildPortfolio values holdings at the current prices in items and
+// compares them with the past prices from 24 hours and 7 days ago. Coins
+// and platinum tokens count at face value.
+func BuildPortfolio(holdings []Holding, items map[int]ItemPrice, prices24h, prices7d map[int]PriceHistory) Portfolio {
+	portfolio := Portfolio{Items: make([]PortfolioItem, 0, len(holdings))}
+	var change24h, change7d PortfolioChange
+
+	for _, h := range holdings {
+		pi := PortfolioItem{ItemID: h.ItemID, Quantity: h.Quantity}
+		if value, ok := currencyValues[h.ItemID]; ok {
+			pi.Found, pi.High, pi.Low, pi.Mid = true, value, value, value
+			if item, ok := items[h.ItemID]; ok {
+				pi.Name = item.Name
+			}
+		} else if item, ok := items[h.ItemID]; ok && item.High > 0 && item.Low > 0 {
+			pi.Found, pi.Name, pi.High, pi.Low = true, item.Name, item.High, item.Low
+			pi.Mid = (item.High + item.Low) / 2
+		} else if ok {
+			pi.Name = item.Name
+		}
+		pi.PortfolioValue = PortfolioValue{High: pi.High * h.Quantity, Low: pi.Low * h.Quantity, Mid: pi.Mid * h.Quantity}
+
+		if pi.Found {
+			pi.Change24h = change24h.add(pi, prices24h)
+			pi.Change7d = change7d.add(pi, prices7d)
+		}
+
+		portfolio.Value.High += pi.PortfolioValue.High
+		portfolio.Value.Low += pi.PortfolioValue.Low
+		portfolio.Value.Mid += pi.PortfolioValue.Mid
+		portfolio.Items = append(portfolio.Items, pi)
+	}
+
+	for i := range portfolio.Items {
+		if portfolio.Value.Mid > 0 {
+			portfolio.Items[i].Share = float64(portfolio.Items[i].PortfolioValue.Mid) / float64(portfolio.Value.Mid) * 100
+		}
+	}
+	sort.SliceStable(portfolio.Items, func(i, j int) bool {
+		return portfolio.Items[i].PortfolioValue.Mid > portfolio.Items[j].PortfolioValue.Mid
+	})
+
+	portfolio.Change24h = change24h.result()
+	portfolio.Change7d = change7d.result()
+	return portfolio
+}
+
+// add compares the item's mid value with its value at the past prices and
+// returns the item's change, or nil without a past price. Currencies never change.
+func (c *PortfolioChange) add(pi PortfolioItem, past map[int]PriceHistory) *int {
+	previous := pi.Mid
+	if _, currency := currencyValues[pi.ItemID]; !currency {
+		entry, ok := past[pi.ItemID]
+		if !ok {
+			return nil
+		}
+		previous = entry.Price
+		c.pricedItems++
+	}
+	previousValue := previous * pi.Quantity
+	change := pi.PortfolioValue.Mid - previousValue
+
+	c.Previous += previousValue
+	c.Change += change
+	c.ItemsCompared++
+	return &change
+}
+
+// result finishes the change, returning nil when no item besides
+// currencies had a past price
+func (c *PortfolioChange) result() *PortfolioChange {
+	if c.pricedItems == 0 {
+		return nil
+	}
+	if c.Previous > 0 {
+		c.ChangePercent = float64(c.Change) / float64(c.Previous) * 100
+	}
+	return c
+}
@@ -16,17 +16,51 @@ type PriceSnapshot struct {
 // PriceProvider defines the interface for fetching prices from external sources
 type PriceProvider interface {
 	FetchLatestPrices(ctx context.Context) (map[int]PriceSnapshot, error)
-	FetchItemNames(ctx context.Context) (map[int]string, error)
+	FetchItemMapping(ctx context.Context) (map[int]ItemMapping, error)
+}
+
+// ItemMapping is the static data of an item from the provider's mapping
+type ItemMapping struct {
+	Name     string
+	BuyLimit int // Items that can be bought every 4 hours; 0 when unknown
+}
+
+// HistoryProvider defines the interface for fetching past prices from external sources.
+// timestep is the provider's sampling interval (e.g. "5m", "1h", "6h", "24h").
+type HistoryProvider interface {
+	FetchPriceHistory(ctx context.Context, itemID int, timestep string) ([]PriceHistory, error)
+}
+
+// DataVersion identifies the state of the stored price data.
+// Version increases on every write; UpdatedAt is the newest item update time
+// and ModifiedAt the time of the latest write, price history included.
+type DataVersion struct {
+	Version    int64
+	UpdatedAt  time.Time
+	ModifiedAt time.Time
 }
 
 // ItemRepository defines the interface for item data operations
 type ItemRepository interface {
 	SavePrices(ctx context.Context, prices []ItemPrice) error
 	GetItemByID(ctx context.Context, id int) (*ItemPrice, error)
+	// GetItemsByIDs returns the items found among ids, keyed by ID; missing IDs are omitted
+	GetItemsByIDs(ctx context.Context, ids []int) (map[int]ItemPrice, error)
 	SearchItems(ctx context.Context, query string) ([]ItemPrice, error)
 	GetAllItems(ctx context.Context) ([]ItemPrice, error)
-	SearchItemsPaginated(ctx context.Context, query string, params PaginationParams) (PaginatedResult[ItemPrice], error)
+	// SearchItemsPaginated returns the items matching filter, ordered by ID
+	SearchItemsPaginated(ctx context.Context, filter ItemFilter, params PaginationParams) (PaginatedResult[ItemPrice], error)
 	GetAllItemsPaginated(ctx context.Context, params PaginationParams) (PaginatedResult[ItemPrice], error)
 	SavePriceHistory(ctx context.Context, itemID int, price int, date time.Time) error
 	GetPriceHistory(ctx context.Context, itemID int, days int) ([]PriceHistory, error)
+	// GetHistoricalPrices returns, for each of ids, its latest history entry
+	// dated within [from, to]; items without one are omitted
+	GetHistoricalPrices(ctx context.Context, ids []int, from, to time.Time) (map[int]PriceHistory, error)
+	// SavePriceHistoryBatch stores entries, skipping any (item_id, date) already stored,
+	// and returns how many were inserted
+	SavePriceHistoryBatch(ctx context.Context, entries []PriceHistory) (int, error)
+	GetDataVersion(ctx context.Context) (DataVersion, error)
+	// ForEachItem calls fn for every item matching filter ordered by ID,
+	// stopping at the first error
+	ForEachItem(ctx context.Context, filter ItemFilter, fn func(item ItemPrice) error) error
 }
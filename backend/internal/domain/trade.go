@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrTradeNotFound is returned when the user has no trade with an ID
+var ErrTradeNotFound = errors.New("trade not found")
+
+// TradeSide tells whether a trade bought or sold items
+type TradeSide string
+
+const (
+	TradeBuy  TradeSide = "buy"
+	TradeSell TradeSide = "sell"
+)
+
+// Trade is one Grand Exchange offer filled by the user
+type Trade struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	ItemID    int       `json:"item_id"`
+	Side      TradeSide `json:"side"`
+	Quantity  int       `json:"quantity"`
+	Price     int       `json:"price"` // Price per item
+	TradedAt  time.Time `json:"traded_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TradeFilter selects trades; zero fields do not filter
+type TradeFilter struct {
+	ItemID int
+	From   time.Time // Inclusive
+	To     time.Time // Exclusive
+}
+
+// Matches reports whether the trade passes the filter
+func (f TradeFilter) Matches(t Trade) bool {
+	if f.ItemID != 0 && t.ItemID != f.ItemID {
+		return false
+	}
+	if !f.From.IsZero() && t.TradedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !t.TradedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// CompletedFlip is a quantity bought and later sold, matched FIFO
+type CompletedFlip struct {
+	ItemID    int       `json:"item_id"`
+	Quantity  int       `json:"quantity"`
+	BuyPrice  int       `json:"buy_price"`
+	SellPrice int       `json:"sell_price"`
+	BoughtAt  time.Time `json:"bought_at"`
+	SoldAt    time.Time `json:"sold_at"`
+	Cost      int       `json:"cost"`    // BuyPrice * Quantity
+	Revenue   int       `json:"revenue"` // SellPrice * Quantity, before tax
+	Tax       int       `json:"tax"`     // GE tax on the sale
+	Profit    int       `json:"profit"`  // Revenue - Tax - Cost
+}
+
+// OpenLot is a quantity bought and not sold yet
+type OpenLot struct {
+	ItemID   int       `json:"item_id"`
+	Quantity int       `json:"quantity"`
+	Price    int       `json:"price"`
+	BoughtAt time.Time `json:"bought_at"`
+}
+
+// TradeMatch is the result of matching trades FIFO
+type TradeMatch struct {
+	Flips []CompletedFlip
+	Open  []OpenLot
+	// UnmatchedSold counts, per item, quantities sold without an earlier
+	// logged buy; they are left out of profit figures
+	UnmatchedSold map[int]int
+}
+
+// MatchTrades matches each sale with the oldest unsold buys of the same item
+// (first in, first out). A sale spanning several buys yields one flip per
+// buy. Trades are processed in time order; at equal times buys come first.
+func MatchTrades(trades []Trade) TradeMatch {
+	ordered := make([]Trade, len(trades))
+	copy(ordered, trades)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if !a.TradedAt.Equal(b.TradedAt) {
+			return a.TradedAt.Before(b.TradedAt)
+		}
+		if a.Side != b.Side {
+			return a.Side == TradeBuy
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	match := TradeMatch{UnmatchedSold: make(map[int]int)}
+	lots := make(map[int][]OpenLot) // item -> unsold buys, oldest first
+	for _, trade := range ordered {
+		if trade.Side == TradeBuy {
+			lots[trade.ItemID] = append(lots[trade.ItemID], OpenLot{
+				ItemID:   trade.ItemID,
+				Quantity: trade.Quantity,
+				Price:    trade.Price,
+				BoughtAt: trade.TradedAt,
+			})
+			continue
+		}
+
+		remaining := trade.Quantity
+		queue := lots[trade.ItemID]
+		for remaining > 0 && len(queue) > 0 {
+			lot := &queue[0]
+			quantity := min(remaining, lot.Quantity)
+			match.Flips = append(match.Flips, newCompletedFlip(*lot, trade, quantity))
+
+			remaining -= quantity
+			lot.Quantity -= quantity
+			if lot.Quantity == 0 {
+				queue = queue[1:]
+			}
+		}
+		lots[trade.ItemID] = queue
+		if remaining > 0 {
+			match.UnmatchedSold[trade.ItemID] += remaining
+		}
+	}
+
+	for _, queue := range lots {
+		match.Open = append(match.Open, queue...)
+	}
+	sort.Slice(match.Open, func(i, j int) bool {
+		if match.Open[i].ItemID != match.Open[j].ItemID {
+			return match.Open[i].ItemID < match.Open[j].ItemID
+		}
+		return match.Open[i].BoughtAt.Before(match.Open[j].BoughtAt)
+	})
+	return match
+}
+
+// newCompletedFlip prices quantity items of lot sold by sale
+func newCompletedFlip(lot OpenLot, sale Trade, quantity int) CompletedFlip {
+	cost := lot.Price * quantity
+	revenue := sale.Price * quantity
+	tax := CalculateGETax(sale.Price) * quantity
+	return CompletedFlip{
+		ItemID:    lot.ItemID,
+		Quantity:  quantity,
+		BuyPrice:  lot.Price,
+		SellPrice: sale.Price,
+		BoughtAt:  lot.BoughtAt,
+		SoldAt:    sale.TradedAt,
+		Cost:      cost,
+		Revenue:   revenue,
+		Tax:       tax,
+		Profit:    revenue - tax - cost,
+	}
+}
+
+// TradeRepository defines the interface for trade journal storage. Every
+// method is scoped to a user.
+type TradeRepository interface {
+	CreateTrades(ctx context.Context, trades []Trade) error
+	// ListTrades returns the user's trades matching filter, oldest first
+	ListTrades(ctx context.Context, userID string, filter TradeFilter) ([]Trade, error)
+	DeleteTrade(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ReportPeriod is the length of the periods a trade report is grouped by
+type ReportPeriod string
+
+const (
+	ReportDaily   ReportPeriod = "day"
+	ReportWeekly  ReportPeriod = "week"
+	ReportMonthly ReportPeriod = "month"
+)
+
+// Start returns the start of the UTC period containing t. Weeks start on Monday.
+func (p ReportPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case ReportWeekly:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case ReportMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// RealizedPnL summarizes completed flips
+type RealizedPnL struct {
+	Flips          int     `json:"flips"`
+	Quantity       int     `json:"quantity"`
+	Cost           int     `json:"cost"`
+	Revenue        int     `json:"revenue"`
+	Tax            int     `json:"tax"`
+	RealizedProfit int     `json:"realized_profit"`
+	ROI            float64 `json:"roi"`         // Realized profit as a percentage of cost
+	HoursHeld      float64 `json:"hours_held"`  // Time with at least one flip in progress
+	GPPerHour      float64 `json:"gp_per_hour"` // Realized profit per hour held
+}
+
+// OpenPosition values the items bought and not sold yet
+type OpenPosition struct {
+	OpenQuantity     int `json:"open_quantity"`
+	OpenCost         int `json:"open_cost"`
+	MarketValue      int `json:"market_value"`      // Proceeds of selling at the mark price, after tax
+	UnrealizedProfit int `json:"unrealized_profit"` // MarketValue - OpenCost of priced items
+}
+
+// ItemPnL is the profit and loss of one item
+type ItemPnL struct {
+	ItemID int    `json:"item_id"`
+	Name   string `json:"name,omitempty"`
+	RealizedPnL
+	OpenPosition
+	// MarkPrice is the current instant-buy price open items are valued at;
+	// 0 when the item has no price, leaving its unrealized profit at 0
+	MarkPrice     int `json:"mark_price"`
+	UnmatchedSold int `json:"unmatched_sold"` // Sold without a logged buy; not in profit figures
+}
+
+// PeriodPnL is the realized profit and loss of flips completed in one period
+type PeriodPnL struct {
+	Start time.Time `json:"start"`
+	RealizedPnL
+}
+
+// TradeReport is the profit and loss of a user's trades. Realized figures
+// cover flips sold within [From, To); positions still open at To, or now
+// without To, are valued at current prices.
+type TradeReport struct {
+	From   *time.Time   `json:"from,omitempty"`
+	To     *time.Time   `json:"to,omitempty"`
+	Period ReportPeriod `json:"period"`
+	Total  struct {
+		RealizedPnL
+		OpenPosition
+	} `json:"total"`
+	Items   []ItemPnL   `json:"items"`   // Highest realized profit first
+	Periods []PeriodPnL `json:"periods"` // Oldest first
+}
+
+// BuildTradeReport matches trades FIFO and aggregates the result per item
+// and per period. Only flips sold within filter's time range count as
+// realized; prices holds the current price of items with open lots.
+func BuildTradeReport(trades []Trade, filter TradeFilter, period ReportPeriod, prices map[int]ItemPrice) TradeReport {
+	match := MatchTrades(trades)
+
+	report := TradeReport{Period: period, Items: []ItemPnL{}, Periods: []PeriodPnL{}}
+	if !filter.From.IsZero() {
+		report.From = &filter.From
+	}
+	if !filter.To.IsZero() {
+		report.To = &filter.To
+	}
+
+	var realized []CompletedFlip
+	byItem := make(map[int][]CompletedFlip)
+	byPeriod := make(map[time.Time][]CompletedFlip)
+	for _, flip := range match.Flips {
+		if !filter.Matches(Trade{ItemID: flip.ItemID, TradedAt: flip.SoldAt}) {
+			continue
+		}
+		realized = append(realized, flip)
+		byItem[flip.ItemID] = append(byItem[flip.ItemID], flip)
+		start := period.Start(flip.SoldAt)
+		byPeriod[start] = append(byPeriod[start], flip)
+	}
+
+	open := make(map[int][]OpenLot)
+	for _, lot := range match.Open {
+		if filter.ItemID == 0 || lot.ItemID == filter.ItemID {
+			open[lot.ItemID] = append(open[lot.ItemID], lot)
+		}
+	}
+
+	items := make(map[int]*ItemPnL)
+	item := func(id int) *ItemPnL {
+		if items[id] == nil {
+			items[id] = &ItemPnL{ItemID: id, Name: prices[id].Name}
+		}
+		return items[id]
+	}
+	for id, flips := range byItem {
+		item(id).RealizedPnL = summarizeFlips(flips)
+	}
+	for id, lots := range open {
+		pnl := item(id)
+		pnl.MarkPrice = prices[id].High
+		pnl.OpenPosition = valueLots(lots, pnl.MarkPrice)
+	}
+	for id, quantity := range match.UnmatchedSold {
+		if filter.ItemID == 0 || id == filter.ItemID {
+			item(id).UnmatchedSold = quantity
+		}
+	}
+
+	report.Total.RealizedPnL = summarizeFlips(realized)
+	for _, pnl := range items {
+		report.Total.OpenQuantity += pnl.OpenQuantity
+		report.Total.OpenCost += pnl.OpenCost
+		report.Total.MarketValue += pnl.MarketValue
+		report.Total.UnrealizedProfit += pnl.UnrealizedProfit
+		report.Items = append(report.Items, *pnl)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		if report.Items[i].RealizedProfit != report.Items[j].RealizedProfit {
+			return report.Items[i].RealizedProfit > report.Items[j].RealizedProfit
+		}
+		return report.Items[i].ItemID < report.Items[j].ItemID
+	})
+
+	for start, flips := range byPeriod {
+		report.Periods = append(report.Periods, PeriodPnL{Start: start, RealizedPnL: summarizeFlips(flips)})
+	}
+	sort.Slice(report.Periods, func(i, j int) bool {
+		return report.Periods[i].Start.Before(report.Periods[j].Start)
+	})
+	return report
+}
+
+// summarizeFlips totals flips. Hours held count the time covered by at
+// least one flip, so overlapping flips are not counted twice.
+func summarizeFlips(flips []CompletedFlip) RealizedPnL {
+	var pnl RealizedPnL
+	for _, flip := range flips {
+		pnl.Flips++
+		pnl.Quantity += flip.Quantity
+		pnl.Cost += flip.Cost
+		pnl.Revenue += flip.Revenue
+		pnl.Tax += flip.Tax
+		pnl.RealizedProfit += flip.Profit
+	}
+	if pnl.Cost > 0 {
+		pnl.ROI = float64(pnl.RealizedProfit) / float64(pnl.Cost) * 100
+	}
+	pnl.HoursHeld = heldDuration(flips).Hours()
+	if pnl.HoursHeld > 0 {
+		pnl.GPPerHour = float64(pnl.RealizedProfit) / pnl.HoursHeld
+	}
+	return pnl
+}
+
+// heldDuration returns the length of the union of the flips' holding intervals
+func heldDuration(flips []CompletedFlip) time.Duration {
+	intervals := make([]CompletedFlip, len(flips))
+	copy(intervals, flips)
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].BoughtAt.Before(intervals[j].BoughtAt)
+	})
+
+	var total time.Duration
+	var start, end time.Time
+	for i, flip := range intervals {
+		if i > 0 && !flip.BoughtAt.After(end) {
+			if flip.SoldAt.After(end) {
+				end = flip.SoldAt
+			}
+			continue
+		}
+		total += end.Sub(start)
+		start, end = flip.BoughtAt, flip.SoldAt
+	}
+	return total + end.Sub(start)
+}
+
+// valueLots values open lots at markPrice, after the tax of selling them.
+// Without a mark price the lots are valued at cost.
+func valueLots(lots []OpenLot, markPrice int) OpenPosition {
+	var position OpenPosition
+	for _, lot := range lots {
+		position.OpenQuantity += lot.Quantity
+		position.OpenCost += lot.Price * lot.Quantity
+	}
+	if markPrice <= 0 {
+		position.MarketValue = position.OpenCost
+		return position
+	}
+	position.MarketValue = (markPrice - CalculateGETax(markPrice)) * position.OpenQuantity
+	position.UnrealizedProfit = position.MarketValue - position.OpenCost
+	return position
+}
@@ -0,0 +1,279 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// tradeBase is a Monday, so weekly periods start on it
+var tradeBase = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func tradeAt(hours int) time.Time {
+	return tradeBase.Add(time.Duration(hours) * time.Hour)
+}
+
+func buyTrade(itemID, quantity, price, hours int) Trade {
+	return Trade{ItemID: itemID, Side: TradeBuy, Quantity: quantity, Price: price, TradedAt: tradeAt(hours)}
+}
+
+func sellTrade(itemID, quantity, price, hours int) Trade {
+	return Trade{ItemID: itemID, Side: TradeSell, Quantity: quantity, Price: price, TradedAt: tradeAt(hours)}
+}
+
+func TestMatchTrades(t *testing.T) {
+	tests := []struct {
+		name      string
+		trades    []Trade
+		flips     []CompletedFlip
+		open      []OpenLot
+		unmatched map[int]int
+	}{
+		{
+			name:   "whole lot",
+			trades: []Trade{buyTrade(1, 10, 1000, 0), sellTrade(1, 10, 1200, 2)},
+			flips: []CompletedFlip{
+				{ItemID: 1, Quantity: 10, BuyPrice: 1000, SellPrice: 1200, BoughtAt: tradeAt(0), SoldAt: tradeAt(2),
+					Cost: 10000, Revenue: 12000, Tax: 120, Profit: 1880},
+			},
+			unmatched: map[int]int{},
+		},
+		{
+			name:   "sale split across lots",
+			trades: []Trade{buyTrade(1, 5, 1000, 0), buyTrade(1, 5, 1100, 1), sellTrade(1, 7, 1200, 2)},
+			flips: []CompletedFlip{
+				{ItemID: 1, Quantity: 5, BuyPrice: 1000, SellPrice: 1200, BoughtAt: tradeAt(0), SoldAt: tradeAt(2),
+					Cost: 5000, Revenue: 6000, Tax: 60, Profit: 940},
+				{ItemID: 1, Quantity: 2, BuyPrice: 1100, SellPrice: 1200, BoughtAt: tradeAt(1), SoldAt: tradeAt(2),
+					Cost: 2200, Revenue: 2400, Tax: 24, Profit: 176},
+			},
+			open:      []OpenLot{{ItemID: 1, Quantity: 3, Price: 1100, BoughtAt: tradeAt(1)}},
+			unmatched: map[int]int{},
+		},
+		{
+			name:   "lot split across sales",
+			trades: []Trade{buyTrade(1, 10, 1000, 0), sellTrade(1, 4, 1200, 1), sellTrade(1, 6, 900, 2)},
+			flips: []CompletedFlip{
+				{ItemID: 1, Quantity: 4, BuyPrice: 1000, SellPrice: 1200, BoughtAt: tradeAt(0), SoldAt: tradeAt(1),
+					Cost: 4000, Revenue: 4800, Tax: 48, Profit: 752},
+				{ItemID: 1, Quantity: 6, BuyPrice: 1000, SellPrice: 900, BoughtAt: tradeAt(0), SoldAt: tradeAt(2),
+					Cost: 6000, Revenue: 5400, Tax: 54, Profit: -654},
+			},
+			unmatched: map[int]int{},
+		},
+		{
+			name:      "sale without a buy",
+			trades:    []Trade{sellTrade(1, 4, 1200, 0)},
+			unmatched: map[int]int{1: 4},
+		},
+		{
+			name:      "sale before the buy",
+			trades:    []Trade{buyTrade(1, 3, 1000, 2), sellTrade(1, 3, 1200, 1)},
+			open:      []OpenLot{{ItemID: 1, Quantity: 3, Price: 1000, BoughtAt: tradeAt(2)}},
+			unmatched: map[int]int{1: 3},
+		},
+		{
+			name:   "sale larger than the lots",
+			trades: []Trade{buyTrade(1, 3, 1000, 0), sellTrade(1, 5, 1200, 1)},
+			flips: []CompletedFlip{
+				{ItemID: 1, Quantity: 3, BuyPrice: 1000, SellPrice: 1200, BoughtAt: tradeAt(0), SoldAt: tradeAt(1),
+					Cost: 3000, Revenue: 3600, Tax: 36, Profit: 564},
+			},
+			unmatched: map[int]int{1: 2},
+		},
+		{
+			name:   "buy first at equal times",
+			trades: []Trade{sellTrade(1, 2, 1200, 0), buyTrade(1, 2, 1000, 0)},
+			flips: []CompletedFlip{
+				{ItemID: 1, Quantity: 2, BuyPrice: 1000, SellPrice: 1200, BoughtAt: tradeAt(0), SoldAt: tradeAt(0),
+					Cost: 2000, Revenue: 2400, Tax: 24, Profit: 376},
+			},
+			unmatched: map[int]int{},
+		},
+		{
+			name:   "items matched separately",
+			trades: []Trade{buyTrade(1, 2, 1000, 0), buyTrade(2, 2, 50, 1), sellTrade(2, 2, 60, 2)},
+			flips: []CompletedFlip{
+				{ItemID: 2, Quantity: 2, BuyPrice: 50, SellPrice: 60, BoughtAt: tradeAt(1), SoldAt: tradeAt(2),
+					Cost: 100, Revenue: 120, Tax: 0, Profit: 20},
+			},
+			open:      []OpenLot{{ItemID: 1, Quantity: 2, Price: 1000, BoughtAt: tradeAt(0)}},
+			unmatched: map[int]int{},
+		},
+		{
+			name:   "tax rounds down per item",
+			trades: []Trade{buyTrade(1, 10, 150, 0), sellTrade(1, 10, 199, 1)},
+			flips: []CompletedFlip{
+				{ItemID: 1, Quantity: 10, BuyPrice: 150, SellPrice: 199, BoughtAt: tradeAt(0), SoldAt: tradeAt(1),
+					Cost: 1500, Revenue: 1990, Tax: 10, Profit: 480},
+			},
+			unmatched: map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := MatchTrades(tt.trades)
+			if !reflect.DeepEqual(match.Flips, tt.flips) {
+				t.Errorf("flips = %+v, want %+v", match.Flips, tt.flips)
+			}
+			if !reflect.DeepEqual(match.Open, tt.open) {
+				t.Errorf("open = %+v, want %+v", match.Open, tt.open)
+			}
+			if !reflect.DeepEqual(match.UnmatchedSold, tt.unmatched) {
+				t.Errorf("unmatched sold = %v, want %v", match.UnmatchedSold, tt.unmatched)
+			}
+		})
+	}
+}
+
+func TestMatchTradesDoesNotReorderInput(t *testing.T) {
+	trades := []Trade{sellTrade(1, 1, 1200, 1), buyTrade(1, 1, 1000, 0)}
+	MatchTrades(trades)
+	if trades[0].Side != TradeSell {
+		t.Error("MatchTrades reordered its input")
+	}
+}
+
+func TestBuildTradeReport(t *testing.T) {
+	day := 24
+	trades := []Trade{
+		// Item 1: two flips on Monday, overlapping in time, and one the next week
+		buyTrade(1, 10, 1000, 0), sellTrade(1, 10, 1200, 2),
+		buyTrade(1, 5, 1000, 1), sellTrade(1, 5, 1100, 3),
+		buyTrade(1, 4, 2000, 8*day), sellTrade(1, 4, 1900, 8*day+4),
+		// Item 2: one flip on Tuesday and 3 items still held
+		buyTrade(2, 5, 100, day), sellTrade(2, 2, 150, day+1),
+		// Item 3: sold without a buy
+		sellTrade(3, 7, 500, day),
+	}
+	prices := map[int]ItemPrice{
+		1: {ItemID: 1, Name: "Whip", High: 1300},
+		2: {ItemID: 2, Name: "Rune", High: 200},
+	}
+
+	tests := []struct {
+		name    string
+		filter  TradeFilter
+		period  ReportPeriod
+		total   RealizedPnL
+		items   []ItemPnL
+		periods []PeriodPnL
+	}{
+		{
+			name:   "daily",
+			period: ReportDaily,
+			total: RealizedPnL{Flips: 4, Quantity: 21, Cost: 23200, Revenue: 25400, Tax: 253, RealizedProfit: 1947,
+				HoursHeld: 8, GPPerHour: 1947.0 / 8},
+			items: []ItemPnL{
+				{ItemID: 1, Name: "Whip",
+					RealizedPnL: RealizedPnL{Flips: 3, Quantity: 19, Cost: 23000, Revenue: 25100, Tax: 251,
+						RealizedProfit: 1849, HoursHeld: 7, GPPerHour: 1849.0 / 7}},
+				{ItemID: 2, Name: "Rune",
+					RealizedPnL:  RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2, RealizedProfit: 98, HoursHeld: 1, GPPerHour: 98},
+					OpenPosition: OpenPosition{OpenQuantity: 3, OpenCost: 300, MarketValue: 594, UnrealizedProfit: 294},
+					MarkPrice:    200},
+				{ItemID: 3, UnmatchedSold: 7},
+			},
+			periods: []PeriodPnL{
+				{Start: tradeAt(0), RealizedPnL: RealizedPnL{Flips: 2, Quantity: 15, Cost: 15000, Revenue: 17500, Tax: 175,
+					RealizedProfit: 2325, HoursHeld: 3, GPPerHour: 775}},
+				{Start: tradeAt(day), RealizedPnL: RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2,
+					RealizedProfit: 98, HoursHeld: 1, GPPerHour: 98}},
+				{Start: tradeAt(8 * day), RealizedPnL: RealizedPnL{Flips: 1, Quantity: 4, Cost: 8000, Revenue: 7600, Tax: 76,
+					RealizedProfit: -476, HoursHeld: 4, GPPerHour: -119}},
+			},
+		},
+		{
+			name:   "weekly within a range",
+			filter: TradeFilter{From: tradeAt(day), To: tradeAt(14 * day)},
+			period: ReportWeekly,
+			total: RealizedPnL{Flips: 2, Quantity: 6, Cost: 8200, Revenue: 7900, Tax: 78, RealizedProfit: -378,
+				HoursHeld: 5, GPPerHour: -378.0 / 5},
+			items: []ItemPnL{
+				{ItemID: 2, Name: "Rune",
+					RealizedPnL:  RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2, RealizedProfit: 98, HoursHeld: 1, GPPerHour: 98},
+					OpenPosition: OpenPosition{OpenQuantity: 3, OpenCost: 300, MarketValue: 594, UnrealizedProfit: 294},
+					MarkPrice:    200},
+				{ItemID: 3, UnmatchedSold: 7},
+				{ItemID: 1, Name: "Whip",
+					RealizedPnL: RealizedPnL{Flips: 1, Quantity: 4, Cost: 8000, Revenue: 7600, Tax: 76,
+						RealizedProfit: -476, HoursHeld: 4, GPPerHour: -119}},
+			},
+			periods: []PeriodPnL{
+				{Start: tradeAt(0), RealizedPnL: RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2,
+					RealizedProfit: 98, HoursHeld: 1, GPPerHour: 98}},
+				{Start: tradeAt(7 * day), RealizedPnL: RealizedPnL{Flips: 1, Quantity: 4, Cost: 8000, Revenue: 7600, Tax: 76,
+					RealizedProfit: -476, HoursHeld: 4, GPPerHour: -119}},
+			},
+		},
+		{
+			name:   "monthly for one item",
+			filter: TradeFilter{ItemID: 2},
+			period: ReportMonthly,
+			total: RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2, RealizedProfit: 98,
+				HoursHeld: 1, GPPerHour: 98},
+			items: []ItemPnL{
+				{ItemID: 2, Name: "Rune",
+					RealizedPnL:  RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2, RealizedProfit: 98, HoursHeld: 1, GPPerHour: 98},
+					OpenPosition: OpenPosition{OpenQuantity: 3, OpenCost: 300, MarketValue: 594, UnrealizedProfit: 294},
+					MarkPrice:    200},
+			},
+			periods: []PeriodPnL{
+				{Start: tradeAt(0), RealizedPnL: RealizedPnL{Flips: 1, Quantity: 2, Cost: 200, Revenue: 300, Tax: 2,
+					RealizedProfit: 98, HoursHeld: 1, GPPerHour: 98}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildTradeReport(trades, tt.filter, tt.period, prices)
+
+			for i := range tt.items {
+				tt.items[i].RealizedPnL.ROI = roi(tt.items[i].RealizedPnL)
+			}
+			for i := range tt.periods {
+				tt.periods[i].RealizedPnL.ROI = roi(tt.periods[i].RealizedPnL)
+			}
+			tt.total.ROI = roi(tt.total)
+
+			if !reflect.DeepEqual(report.Total.RealizedPnL, tt.total) {
+				t.Errorf("total = %+v, want %+v", report.Total.RealizedPnL, tt.total)
+			}
+			if !reflect.DeepEqual(report.Items, tt.items) {
+				t.Errorf("items = %+v, want %+v", report.Items, tt.items)
+			}
+			if !reflect.DeepEqual(report.Periods, tt.periods) {
+				t.Errorf("periods = %+v, want %+v", report.Periods, tt.periods)
+			}
+
+			var open OpenPosition
+			for _, item := range tt.items {
+				open.OpenQuantity += item.OpenQuantity
+				open.OpenCost += item.OpenCost
+				open.MarketValue += item.MarketValue
+				open.UnrealizedProfit += item.UnrealizedProfit
+			}
+			if report.Total.OpenPosition != open {
+				t.Errorf("total open position = %+v, want %+v", report.Total.OpenPosition, open)
+			}
+		})
+	}
+}
+
+func TestBuildTradeReportValuesUnpricedLotsAtCost(t *testing.T) {
+	report := BuildTradeReport([]Trade{buyTrade(9, 4, 250, 0)}, TradeFilter{}, ReportDaily, nil)
+
+	want := OpenPosition{OpenQuantity: 4, OpenCost: 1000, MarketValue: 1000}
+	if len(report.Items) != 1 || report.Items[0].OpenPosition != want || report.Items[0].MarkPrice != 0 {
+		t.Errorf("items = %+v, want one item with %+v", report.Items, want)
+	}
+}
+
+func roi(pnl RealizedPnL) float64 {
+	if pnl.Cost == 0 {
+		return 0
+	}
+	return float64(pnl.RealizedProfit) / float64(pnl.Cost) * 100
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUserNotFound is returned when no user matches an ID or email
+	ErrUserNotFound = errors.New("user not found")
+	// ErrEmailTaken is returned when registering an email that already has an account
+	ErrEmailTaken = errors.New("email already registered")
+	// ErrInvalidCredentials is returned for a wrong email or password, without telling which
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidToken is returned for malformed, expired or revoked tokens
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// User is an account that owns watchlists, trades and portfolios
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NormalizeEmail lowercases and trims an email so each address has one account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// RefreshToken is a long-lived session credential exchanged for new access
+// tokens. Only the SHA-256 hash of the token is stored. Each refresh replaces
+// the token; ReplacedBy links it to its successor so reuse can be detected.
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	Hash       string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy string
+}
+
+// HashRefreshToken returns the hex SHA-256 of a refresh token, which is
+// random enough that a fast hash allows lookups without weakening it
+func HashRefreshToken(token string) string {
+	return sha256Hex(token)
+}
+
+// Usable reports whether the token can still be exchanged at now
+func (t RefreshToken) Usable(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// AccessClaims is what a verified access token says about its bearer
+type AccessClaims struct {
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// Session is the pair of tokens handed to a client after login or refresh
+type Session struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	TokenType        string    `json:"token_type"`
+}
+
+// PasswordHasher hashes passwords and checks them against stored hashes
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Compare returns ErrInvalidCredentials when password does not match hash
+	Compare(hash, password string) error
+}
+
+// AccessTokenIssuer signs and verifies short-lived access tokens
+type AccessTokenIssuer interface {
+	Issue(userID string, now time.Time) (token string, expiresAt time.Time, err error)
+	// Verify returns ErrInvalidToken for tokens that are malformed, forged or expired
+	Verify(token string) (AccessClaims, error)
+}
+
+// UserRepository defines the interface for user accounts and their refresh tokens
+type UserRepository interface {
+	// CreateUser stores a new user, returning ErrEmailTaken when the email is in use
+	CreateUser(ctx context.Context, user User) error
+	GetUserByID(ctx context.Context, id string) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+
+	SaveRefreshToken(ctx context.Context, token RefreshToken) error
+	// GetRefreshTokenByHash finds a token, revoked or not, returning ErrInvalidToken when none matches
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// RotateRefreshToken revokes old in favor of next, atomically: it fails
+	// with ErrInvalidToken when old was already revoked by a concurrent refresh
+	RotateRefreshToken(ctx context.Context, oldID string, next RefreshToken, at time.Time) error
+	RevokeRefreshToken(ctx context.Context, id string, at time.Time) error
+	// RevokeUserRefreshTokens ends every session of the user
+	RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrWatchlistNotFound is returned when the user has no watchlist with an ID
+	ErrWatchlistNotFound = errors.New("watchlist not found")
+	// ErrWatchlistItemNotFound is returned when removing an item that is not in the watchlist
+	ErrWatchlistItemNotFound = errors.New("item not in watchlist")
+	// ErrWatchlistFull is returned when adding items beyond MaxWatchlistItems
+	ErrWatchlistFull = errors.New("watchlist is full")
+	// ErrTooManyWatchlists is returned when creating more than MaxWatchlistsPerUser lists
+	ErrTooManyWatchlists = errors.New("too many watchlists")
+	// ErrInvalidWatchlistOrder is returned when a new order is not a permutation of the items
+	ErrInvalidWatchlistOrder = errors.New("order must list every item of the watchlist exactly once")
+)
+
+const (
+	// MaxWatchlistItems bounds the items of one watchlist
+	MaxWatchlistItems = 200
+	// MaxWatchlistsPerUser bounds the watchlists of one user
+	MaxWatchlistsPerUser = 50
+)
+
+// Watchlist is a named, ordered list of items a user tracks
+type Watchlist struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	Name      string    `json:"name"`
+	ItemIDs   []int     `json:"item_ids"` // In the user's order
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AddItems appends the items not yet in the list, keeping their order.
+// It fails with ErrWatchlistFull, leaving the list unchanged, when the
+// result would exceed MaxWatchlistItems.
+func (w *Watchlist) AddItems(itemIDs []int) error {
+	items := slices.Clone(w.ItemIDs)
+	for _, id := range itemIDs {
+		if !slices.Contains(items, id) {
+			items = append(items, id)
+		}
+	}
+	if len(items) > MaxWatchlistItems {
+		return ErrWatchlistFull
+	}
+	w.ItemIDs = items
+	return nil
+}
+
+// RemoveItem removes one item, failing with ErrWatchlistItemNotFound when absent
+func (w *Watchlist) RemoveItem(itemID int) error {
+	i := slices.Index(w.ItemIDs, itemID)
+	if i < 0 {
+		return ErrWatchlistItemNotFound
+	}
+	w.ItemIDs = slices.Delete(slices.Clone(w.ItemIDs), i, i+1)
+	return nil
+}
+
+// Reorder replaces the order of the items. itemIDs must hold exactly the
+// items already in the list, otherwise it fails with ErrInvalidWatchlistOrder.
+func (w *Watchlist) Reorder(itemIDs []int) error {
+	current := slices.Clone(w.ItemIDs)
+	next := slices.Clone(itemIDs)
+	slices.Sort(current)
+	slices.Sort(next)
+	if !slices.Equal(current, next) {
+		return ErrInvalidWatchlistOrder
+	}
+	w.ItemIDs = slices.Clone(itemIDs)
+	return nil
+}
+
+// WatchlistItem is the current market data of one watchlist member. Found
+// is false for items without prices, e.g. untradeable or unknown IDs.
+type WatchlistItem struct {
+	ItemID int        `json:"item_id"`
+	Found  bool       `json:"found"`
+	Item   *ItemPrice `json:"item,omitempty"`
+	Margin float64    `json:"margin"` // Margin percentage of buying low and selling high, before tax
+	Profit int        `json:"profit"` // Profit per item after GE tax
+}
+
+// NewWatchlistItem computes the margins of a watchlist member; item is nil when not found
+func NewWatchlistItem(itemID int, item *ItemPrice) WatchlistItem {
+	if item == nil {
+		return WatchlistItem{ItemID: itemID}
+	}
+	return WatchlistItem{
+		ItemID: itemID,
+		Found:  true,
+		Item:   item,
+		Margin: CalculateMargin(item.Low, item.High),
+		Profit: CalculateExpectedProfit(item.Low, item.High),
+	}
+}
+
+// WatchlistRepository defines the interface for watchlist storage. Every
+// method is scoped to a user: another user's watchlist is ErrWatchlistNotFound.
+type WatchlistRepository interface {
+	// CreateWatchlist stores a new watchlist, or returns ErrTooManyWatchlists
+	// when the user already has max lists; the check and the insert are atomic
+	CreateWatchlist(ctx context.Context, watchlist Watchlist, max int) error
+	GetWatchlist(ctx context.Context, userID, id string) (*Watchlist, error)
+	// ListWatchlists returns the user's watchlists, oldest first
+	ListWatchlists(ctx context.Context, userID string) ([]Watchlist, error)
+	RenameWatchlist(ctx context.Context, userID, id, name string, at time.Time) (*Watchlist, error)
+	DeleteWatchlist(ctx context.Context, userID, id string) error
+
+	// AddWatchlistItems, RemoveWatchlistItem and ReorderWatchlist apply the
+	// Watchlist method of the same name atomically and return the result
+	AddWatchlistItems(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*Watchlist, error)
+	RemoveWatchlistItem(ctx context.Context, userID, id string, itemID int, at time.Time) (*Watchlist, error)
+	ReorderWatchlist(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*Watchlist, error)
+}
@@ -0,0 +1,240 @@
+// Package forecast predicts short-term item prices from their stored history
+// with exponential smoothing: Holt-Winters with daily seasonality when the
+// history is dense enough, Holt's linear method otherwise.
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInsufficientHistory is returned when an item has too little history to forecast
+var ErrInsufficientHistory = errors.New("not enough price history to forecast")
+
+// Horizons are the forecasts made for every item
+var Horizons = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// Methods a forecast can be made with
+const (
+	MethodHoltWinters = "holt_winters"
+	MethodHolt        = "holt"
+)
+
+const (
+	// Confidence is the coverage of the prediction intervals
+	Confidence = 0.95
+	// zConfidence is the normal quantile of Confidence
+	zConfidence = 1.959964
+
+	// minPoints is the shortest resampled series forecast
+	minPoints = 5
+	// minSeasons is how many days of history daily seasonality needs
+	minSeasons = 3
+	// holdout is the share of the series the accuracy backtest forecasts
+	holdout = 0.3
+)
+
+// Prediction is the forecast price at one horizon with its interval
+type Prediction struct {
+	Horizon string    `json:"horizon"`
+	At      time.Time `json:"at"`
+	Price   int       `json:"price"`
+	Lower   int       `json:"lower"`
+	Upper   int       `json:"upper"`
+}
+
+// HorizonAccuracy measures the forecasts of one horizon in the backtest
+type HorizonAccuracy struct {
+	Horizon   string  `json:"horizon"`
+	Forecasts int     `json:"forecasts"`
+	MAE       float64 `json:"mae"`
+	MAPE      float64 `json:"mape"` // Percentage
+	RMSE      float64 `json:"rmse"`
+	// NaiveMAPE is the MAPE of assuming the price stays the same, the
+	// baseline the forecasts should beat
+	NaiveMAPE float64 `json:"naive_mape"`
+	Coverage  float64 `json:"coverage"` // Percentage of prices within the interval
+}
+
+// Accuracy is the outcome of the built-in backtest: the parameters are fit
+// on the older part of the history and forecasts are made from every point
+// of the rest, comparing them with the prices that followed
+type Accuracy struct {
+	TrainPoints int               `json:"train_points"`
+	TestPoints  int               `json:"test_points"`
+	Horizons    []HorizonAccuracy `json:"horizons"`
+}
+
+// Forecast holds the predictions of an item's price
+type Forecast struct {
+	Method       string       `json:"method"`
+	Step         string       `json:"step"`          // Spacing the history was resampled to
+	SeasonLength int          `json:"season_length"` // Steps per day; 0 without seasonality
+	Params       Params       `json:"params"`
+	Points       int          `json:"points"`
+	Origin       time.Time    `json:"origin"` // Time of the last observation
+	Confidence   float64      `json:"confidence"`
+	Predictions  []Prediction `json:"predictions"`
+	Accuracy     *Accuracy    `json:"accuracy"` // nil when the history is too short to backtest
+}
+
+// Run fits a model to points and forecasts each horizon after the last
+// point. It returns ErrInsufficientHistory when points are too few or too
+// close together.
+func Run(points []Point, horizons []time.Duration) (Forecast, error) {
+	s := resample(points)
+	if len(s.values) < minPoints {
+		return Forecast{}, ErrInsufficientHistory
+	}
+	m := seasonLength(s)
+	model, sigma2 := fit(s.values, m)
+	if model == nil {
+		return Forecast{}, ErrInsufficientHistory
+	}
+
+	f := Forecast{
+		Method:       MethodHolt,
+		Step:         formatDuration(s.step),
+		SeasonLength: m,
+		Params:       model.params,
+		Points:       len(s.values),
+		Origin:       s.end,
+		Confidence:   Confidence,
+		Predictions:  make([]Prediction, 0, len(horizons)),
+		Accuracy:     evaluate(s, m, horizons),
+	}
+	if m > 0 {
+		f.Method = MethodHoltWinters
+	}
+
+	last := s.values[len(s.values)-1]
+	for _, h := range horizons {
+		price, variance := project(model, sigma2, last, float64(h)/float64(s.step))
+		half := zConfidence * math.Sqrt(variance)
+		f.Predictions = append(f.Predictions, Prediction{
+			Horizon: formatDuration(h),
+			At:      s.end.Add(h),
+			Price:   max(int(math.Round(price)), 0),
+			Lower:   max(int(math.Round(price-half)), 0),
+			Upper:   max(int(math.Round(price+half)), 0),
+		})
+	}
+	return f, nil
+}
+
+// seasonLength is the number of steps in a day, or 0 when the series is
+// daily or shorter than minSeasons days
+func seasonLength(s series) int {
+	m := int(24 * time.Hour / s.step)
+	if m < 2 || len(s.values) < minSeasons*m {
+		return 0
+	}
+	return m
+}
+
+// project forecasts k steps after the last value consumed by s, which is
+// last, with the variance of the forecast. Fractions of a step are
+// interpolated, so horizons shorter than the step fall between last and
+// the next step's forecast.
+func project(s *smoother, sigma2, last, k float64) (float64, float64) {
+	at := func(h int) (float64, float64) {
+		if h == 0 {
+			return last, 0
+		}
+		return s.predict(h), s.variance(sigma2, h)
+	}
+	lo := int(math.Floor(k))
+	valueLo, varianceLo := at(lo)
+	valueHi, varianceHi := at(int(math.Ceil(k)))
+	frac := k - float64(lo)
+	return valueLo + frac*(valueHi-valueLo), varianceLo + frac*(varianceHi-varianceLo)
+}
+
+// evaluate backtests the model: it fits the parameters on all but the last
+// holdout share of the series, then forecasts every horizon from each later
+// point whose horizon is still within the series
+func evaluate(s series, m int, horizons []time.Duration) *Accuracy {
+	n := len(s.values)
+	train := n - int(float64(n)*holdout)
+	if train >= n {
+		return nil
+	}
+	model, sigma2 := fit(s.values[:train], m)
+	if model == nil {
+		return nil
+	}
+
+	type totals struct {
+		count, covered         int
+		abs, pct, sq, naivePct float64
+	}
+	sums := make([]totals, len(horizons))
+	for origin := train - 1; origin < n-1; origin++ {
+		if origin >= train {
+			model.update(s.values[origin])
+		}
+		last := s.values[origin]
+		for i, h := range horizons {
+			k := float64(h) / float64(s.step)
+			target := float64(origin) + k
+			if target > float64(n-1) {
+				continue
+			}
+			actual := interpolate(s.values, target)
+			predicted, variance := project(model, sigma2, last, k)
+			err := actual - predicted
+
+			t := &sums[i]
+			t.count++
+			t.abs += math.Abs(err)
+			t.sq += err * err
+			t.pct += math.Abs(err) / actual * 100
+			t.naivePct += math.Abs(actual-last) / actual * 100
+			if math.Abs(err) <= zConfidence*math.Sqrt(variance) {
+				t.covered++
+			}
+		}
+	}
+
+	accuracy := &Accuracy{TrainPoints: train, TestPoints: n - train, Horizons: []HorizonAccuracy{}}
+	for i, h := range horizons {
+		t := sums[i]
+		if t.count == 0 {
+			continue
+		}
+		count := float64(t.count)
+		accuracy.Horizons = append(accuracy.Horizons, HorizonAccuracy{
+			Horizon:   formatDuration(h),
+			Forecasts: t.count,
+			MAE:       t.abs / count,
+			MAPE:      t.pct / count,
+			RMSE:      math.Sqrt(t.sq / count),
+			NaiveMAPE: t.naivePct / count,
+			Coverage:  float64(t.covered) / count * 100,
+		})
+	}
+	if len(accuracy.Horizons) == 0 {
+		return nil
+	}
+	return accuracy
+}
+
+// interpolate reads values at a fractional index
+func interpolate(values []float64, index float64) float64 {
+	lo := int(math.Floor(index))
+	if lo >= len(values)-1 {
+		return values[len(values)-1]
+	}
+	frac := index - float64(lo)
+	return values[lo] + frac*(values[lo+1]-values[lo])
+}
+
+// formatDuration writes whole hours as "6h" rather than "6h0m0s"
+func formatDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return d.String()
+}
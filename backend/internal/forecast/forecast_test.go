@@ -0,0 +1,194 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var origin = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// seasonal is a price of 10000 with a daily cycle of ±amplitude and a trend
+// of slope gp per hour
+func seasonal(amplitude, slope float64) func(time.Time) float64 {
+	return func(t time.Time) float64 {
+		hours := t.Sub(origin).Hours()
+		return 10000 + slope*hours + amplitude*math.Sin(2*math.Pi*hours/24)
+	}
+}
+
+// sample observes price every step for days, with relative gaussian noise
+func sample(price func(time.Time) float64, step time.Duration, days int, noise float64, seed int64) []Point {
+	rng := rand.New(rand.NewSource(seed))
+	n := int(time.Duration(days) * 24 * time.Hour / step)
+	points := make([]Point, n)
+	for i := range points {
+		t := origin.Add(time.Duration(i) * step)
+		points[i] = Point{Time: t, Price: price(t) * (1 + noise*rng.NormFloat64())}
+	}
+	return points
+}
+
+func TestRunSeasonal(t *testing.T) {
+	tests := []struct {
+		name  string
+		price func(time.Time) float64
+		noise float64
+		// maxError is the largest error of each prediction, as a share of the
+		// true price; maxMAPE the largest backtest MAPE of each horizon
+		maxError float64
+		maxMAPE  float64
+	}{
+		{name: "flat cycle", price: seasonal(300, 0), noise: 0, maxError: 0.001, maxMAPE: 0.05},
+		{name: "rising cycle", price: seasonal(300, 2), noise: 0, maxError: 0.005, maxMAPE: 0.4},
+		{name: "noisy cycle", price: seasonal(300, 0), noise: 0.002, maxError: 0.005, maxMAPE: 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := sample(tt.price, time.Hour, 14, tt.noise, 49)
+			f, err := Run(points, Horizons)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Method != MethodHoltWinters || f.SeasonLength != 24 || f.Step != "1h" {
+				t.Fatalf("method %s, season %d, step %s; want holt_winters, 24, 1h", f.Method, f.SeasonLength, f.Step)
+			}
+			if !f.Origin.Equal(points[len(points)-1].Time) {
+				t.Errorf("origin = %v, want the last point's time", f.Origin)
+			}
+
+			for i, p := range f.Predictions {
+				want := tt.price(f.Origin.Add(Horizons[i]))
+				if !p.At.Equal(f.Origin.Add(Horizons[i])) {
+					t.Errorf("%s: at %v, want %v", p.Horizon, p.At, f.Origin.Add(Horizons[i]))
+				}
+				if relErr := math.Abs(float64(p.Price)-want) / want; relErr > tt.maxError {
+					t.Errorf("%s: predicted %d, true price %.0f (error %.2f%%)", p.Horizon, p.Price, want, relErr*100)
+				}
+				if p.Lower > p.Price || p.Upper < p.Price {
+					t.Errorf("%s: interval [%d, %d] does not contain %d", p.Horizon, p.Lower, p.Upper, p.Price)
+				}
+			}
+
+			if f.Accuracy == nil || len(f.Accuracy.Horizons) != len(Horizons) {
+				t.Fatalf("accuracy = %+v, want every horizon backtested", f.Accuracy)
+			}
+			for _, h := range f.Accuracy.Horizons {
+				if h.MAPE > tt.maxMAPE {
+					t.Errorf("%s: backtest MAPE %.3f%%, want at most %.3f%%", h.Horizon, h.MAPE, tt.maxMAPE)
+				}
+				// Over a day the cycle returns to the same price, so only
+				// shorter horizons are expected to beat the naive forecast
+				if h.Horizon != "24h" && h.MAPE >= h.NaiveMAPE {
+					t.Errorf("%s: backtest MAPE %.3f%% does not beat the naive %.3f%%", h.Horizon, h.MAPE, h.NaiveMAPE)
+				}
+			}
+		})
+	}
+}
+
+func TestRunIntervalCoverage(t *testing.T) {
+	points := sample(seasonal(300, 0), time.Hour, 14, 0.005, 50)
+	f, err := Run(points, Horizons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range f.Accuracy.Horizons {
+		// Nominally 95%; the backtest is short, so allow some slack
+		if h.Coverage < 85 {
+			t.Errorf("%s: interval coverage %.1f%%, want about %.0f%%", h.Horizon, h.Coverage, Confidence*100)
+		}
+	}
+}
+
+func TestRunTrendWithoutSeason(t *testing.T) {
+	// Daily points leave no room for a daily season
+	price := func(t time.Time) float64 { return 10000 + 50*t.Sub(origin).Hours()/24 }
+	points := sample(price, 24*time.Hour, 30, 0, 0)
+
+	f, err := Run(points, []time.Duration{24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Method != MethodHolt || f.SeasonLength != 0 || f.Params.Gamma != 0 {
+		t.Fatalf("method %s, season %d, gamma %v; want holt without a season", f.Method, f.SeasonLength, f.Params.Gamma)
+	}
+	want := price(f.Origin.Add(24 * time.Hour))
+	if got := float64(f.Predictions[0].Price); math.Abs(got-want) > 5 {
+		t.Errorf("predicted %v, want about %v", got, want)
+	}
+}
+
+func TestRunShortHorizonInterpolates(t *testing.T) {
+	// A 1h horizon on a 6h series falls between the last value and the next step
+	points := sample(seasonal(300, 0), 6*time.Hour, 14, 0, 0)
+	f, err := Run(points, []time.Duration{time.Hour, 6 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Step != "6h" || f.SeasonLength != 4 {
+		t.Fatalf("step %s, season %d; want 6h, 4", f.Step, f.SeasonLength)
+	}
+	last := points[len(points)-1].Price
+	hour, sixHours := float64(f.Predictions[0].Price), float64(f.Predictions[1].Price)
+	if want := last + (sixHours-last)/6; math.Abs(hour-want) > 1 {
+		t.Errorf("1h prediction %v, want %v, a sixth of the way to the 6h one", hour, want)
+	}
+}
+
+func TestRunResamplesIrregularPoints(t *testing.T) {
+	rng := rand.New(rand.NewSource(51))
+	price := seasonal(300, 0)
+	var points []Point
+	for at := origin; at.Before(origin.Add(10 * 24 * time.Hour)); at = at.Add(time.Duration(50+rng.Intn(20)) * time.Minute) {
+		points = append(points, Point{Time: at, Price: price(at)})
+	}
+	// Out of order and unpriced points
+	points[3], points[4] = points[4], points[3]
+	points = append(points, Point{Time: origin.Add(time.Hour), Price: 0})
+
+	f, err := Run(points, Horizons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Step != "1h" || f.Method != MethodHoltWinters {
+		t.Errorf("step %s, method %s; want 1h, holt_winters", f.Step, f.Method)
+	}
+	for i, p := range f.Predictions {
+		want := price(f.Origin.Add(Horizons[i]))
+		if math.Abs(float64(p.Price)-want)/want > 0.005 {
+			t.Errorf("%s: predicted %d, true price %.0f", p.Horizon, p.Price, want)
+		}
+	}
+}
+
+func TestRunInsufficientHistory(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+	}{
+		{name: "no points"},
+		{name: "too few points", points: sample(seasonal(300, 0), time.Hour, 1, 0, 0)[:4]},
+		{
+			name: "same time",
+			points: []Point{
+				{Time: origin, Price: 100}, {Time: origin, Price: 101}, {Time: origin, Price: 102},
+				{Time: origin, Price: 103}, {Time: origin, Price: 104}, {Time: origin, Price: 105},
+			},
+		},
+		{
+			name:   "unpriced",
+			points: []Point{{Time: origin}, {Time: origin.Add(time.Hour)}, {Time: origin.Add(2 * time.Hour)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Run(tt.points, Horizons); !errors.Is(err, ErrInsufficientHistory) {
+				t.Errorf("err = %v, want ErrInsufficientHistory", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package forecast
+
+import "math"
+
+// damping shrinks the trend at every step ahead, keeping long forecasts
+// from extrapolating a short-lived move
+const damping = 0.98
+
+// Grids searched when fitting the smoothing parameters
+var (
+	alphas = []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.7, 0.9}
+	betas  = []float64{0, 0.02, 0.05, 0.1, 0.2}
+	gammas = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// Params are the smoothing parameters of the level, trend and season
+type Params struct {
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+	Gamma float64 `json:"gamma"` // 0 without seasonality
+}
+
+// smoother is an additive Holt-Winters model with a damped trend; with a
+// season shorter than 2 it is Holt's linear method
+type smoother struct {
+	params Params
+	m      int // Season length in steps
+	level  float64
+	trend  float64
+	season []float64 // season[i] applies to the values whose index is i modulo m
+	t      int       // Values consumed
+}
+
+// newSmoother initializes a smoother from the first values: the first two
+// seasons, or the first two values without seasonality. It returns nil when
+// values is too short.
+func newSmoother(values []float64, m int, params Params) *smoother {
+	s := &smoother{params: params, m: m}
+	if m < 2 {
+		if len(values) < 2 {
+			return nil
+		}
+		s.level, s.trend, s.t = values[0], values[1]-values[0], 1
+		return s
+	}
+
+	if len(values) < 2*m {
+		return nil
+	}
+	first, second := mean(values[:m]), mean(values[m:2*m])
+	s.level, s.trend = first, (second-first)/float64(m)
+	s.season = make([]float64, m)
+	for i := range s.season {
+		s.season[i] = values[i] - first
+	}
+	s.t = m
+	return s
+}
+
+// predict forecasts the value h steps after the last one consumed
+func (s *smoother) predict(h int) float64 {
+	factor, trend := 1.0, 0.0
+	for i := 0; i < h; i++ {
+		factor *= damping
+		trend += factor
+	}
+	y := s.level + trend*s.trend
+	if s.m >= 2 {
+		y += s.season[(s.t+h-1)%s.m]
+	}
+	return y
+}
+
+// update consumes the next value and returns the one-step-ahead error
+func (s *smoother) update(y float64) float64 {
+	err := y - s.predict(1)
+	seasonal := 0.0
+	if s.m >= 2 {
+		seasonal = s.season[s.t%s.m]
+	}
+
+	level := s.params.Alpha*(y-seasonal) + (1-s.params.Alpha)*(s.level+damping*s.trend)
+	s.trend = s.params.Beta*(level-s.level) + (1-s.params.Beta)*damping*s.trend
+	s.level = level
+	if s.m >= 2 {
+		s.season[s.t%s.m] = s.params.Gamma*(y-level) + (1-s.params.Gamma)*seasonal
+	}
+	s.t++
+	return err
+}
+
+// variance is the variance of the forecast h steps ahead relative to the
+// one-step variance sigma2
+func (s *smoother) variance(sigma2 float64, h int) float64 {
+	sum := 1.0
+	for j := 1; j < h; j++ {
+		c := s.params.Alpha * (1 + float64(j)*s.params.Beta)
+		if s.m >= 2 && j%s.m == 0 {
+			c += s.params.Gamma
+		}
+		sum += c * c
+	}
+	return sigma2 * sum
+}
+
+// fit searches the parameter grids for the smoother with the lowest squared
+// one-step error over values, returning it with the mean squared error.
+// It returns a nil smoother when values is too short.
+func fit(values []float64, m int) (*smoother, float64) {
+	gammaGrid := gammas
+	if m < 2 {
+		gammaGrid = []float64{0}
+	}
+
+	var best Params
+	bestSSE, count := math.Inf(1), 0
+	for _, alpha := range alphas {
+		for _, beta := range betas {
+			for _, gamma := range gammaGrid {
+				params := Params{Alpha: alpha, Beta: beta, Gamma: gamma}
+				s := newSmoother(values, m, params)
+				if s == nil {
+					return nil, 0
+				}
+				sse, start := 0.0, s.t
+				for _, y := range values[start:] {
+					err := s.update(y)
+					sse += err * err
+				}
+				if sse < bestSSE {
+					best, bestSSE, count = params, sse, len(values)-start
+				}
+			}
+		}
+	}
+	if count == 0 {
+		return nil, 0
+	}
+
+	s := newSmoother(values, m, best)
+	for _, y := range values[s.t:] {
+		s.update(y)
+	}
+	return s, bestSSE / float64(count)
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
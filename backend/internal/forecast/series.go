@@ -0,0 +1,84 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Point is a price observation
+type Point struct {
+	Time  time.Time
+	Price float64
+}
+
+// steps are the sampling intervals a series is resampled to; each divides a
+// day, so every step of a resampled series falls at the same time of day
+// as the step one season earlier
+var steps = []time.Duration{
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 4 * time.Hour,
+	6 * time.Hour, 8 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// series is a regularly spaced price series ending at the last observation
+type series struct {
+	values []float64
+	step   time.Duration
+	end    time.Time // Time of the last value
+}
+
+// resample orders points and interpolates them onto a grid of the step
+// closest to their median spacing, counted back from the last point
+func resample(points []Point) series {
+	ordered := make([]Point, 0, len(points))
+	for _, p := range points {
+		if p.Price > 0 {
+			ordered = append(ordered, p)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Time.Before(ordered[j].Time) })
+	if len(ordered) < 2 {
+		return series{}
+	}
+
+	gaps := make([]float64, 0, len(ordered)-1)
+	for i := 1; i < len(ordered); i++ {
+		if gap := ordered[i].Time.Sub(ordered[i-1].Time); gap > 0 {
+			gaps = append(gaps, float64(gap))
+		}
+	}
+	if len(gaps) == 0 {
+		return series{}
+	}
+	sort.Float64s(gaps)
+	median := gaps[len(gaps)/2]
+
+	step := steps[0]
+	for _, candidate := range steps[1:] {
+		if math.Abs(math.Log(float64(candidate)/median)) < math.Abs(math.Log(float64(step)/median)) {
+			step = candidate
+		}
+	}
+
+	first, last := ordered[0].Time, ordered[len(ordered)-1].Time
+	n := int(last.Sub(first)/step) + 1
+	values := make([]float64, n)
+	j := 0
+	for k := range values {
+		t := last.Add(-time.Duration(n-1-k) * step)
+		for j < len(ordered)-2 && !ordered[j+1].Time.After(t) {
+			j++
+		}
+		a, b := ordered[j], ordered[j+1]
+		switch {
+		case !t.After(a.Time):
+			values[k] = a.Price
+		case !t.Before(b.Time):
+			values[k] = b.Price
+		default:
+			frac := float64(t.Sub(a.Time)) / float64(b.Time.Sub(a.Time))
+			values[k] = a.Price + frac*(b.Price-a.Price)
+		}
+	}
+	return series{values: values, step: step, end: last}
+}
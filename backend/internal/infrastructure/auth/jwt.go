@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// issuer identifies tokens signed by this service
+const issuer = "osrs-good-to-flip"
+
+// JWTIssuer implements AccessTokenIssuer with HS256-signed JWTs
+type JWTIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewJWTIssuer creates an issuer signing with secret; tokens expire after ttl
+func NewJWTIssuer(secret []byte, ttl time.Duration) *JWTIssuer {
+	return &JWTIssuer{secret: secret, ttl: ttl}
+}
+
+// Issue signs an access token for userID
+func (i *JWTIssuer) Issue(userID string, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(i.ttl)
+	claims := jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   userID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Verify checks the signature, issuer and expiry of token
+func (i *JWTIssuer) Verify(token string) (domain.AccessClaims, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims,
+		func(*jwt.Token) (interface{}, error) { return i.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" {
+		return domain.AccessClaims{}, errors.Join(domain.ErrInvalidToken, err)
+	}
+
+	return domain.AccessClaims{UserID: claims.Subject, ExpiresAt: claims.ExpiresAt.Time}, nil
+}
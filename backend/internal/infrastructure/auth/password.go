@@ -0,0 +1,41 @@
+// Package auth implements password hashing and access token signing for user accounts
+package auth
+
+import (
+	"errors"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher implements PasswordHasher with bcrypt
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a hasher with the given bcrypt cost; 0 uses bcrypt.DefaultCost
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash returns the bcrypt hash of password. bcrypt only reads the first 72
+// bytes, so longer passwords are rejected before reaching it.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Compare checks password against hash
+func (h *BcryptHasher) Compare(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return domain.ErrInvalidCredentials
+	}
+	return err
+}
@@ -1,28 +1,52 @@
 package cache
 
 import (
+	"container/list"
 	"sync"
 	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
 )
 
+// cleanupInterval is how often expired entries are purged
+const cleanupInterval = 1 * time.Minute
+
 // CacheEntry represents a cached value with expiration
-type CacheEntry struct {
-	Value     interface{}
+type CacheEntry[K comparable, V any] struct {
+	Key       K
+	Value     V
 	ExpiresAt time.Time
 }
 
-// MemoryCache is a thread-safe in-memory cache with TTL
-type MemoryCache struct {
-	mu    sync.RWMutex
-	items map[string]CacheEntry
-	ttl   time.Duration
+// MemoryCache is a thread-safe, size-bounded in-memory LRU cache with per-entry TTL.
+// When full, the least recently used entry is evicted.
+type MemoryCache[K comparable, V any] struct {
+	name       string
+	mu         sync.Mutex
+	items      map[K]*list.Element // values are *CacheEntry[K, V]
+	order      *list.List          // front = most recently used
+	maxEntries int
+	ttl        time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
-// NewMemoryCache creates a new in-memory cache with the specified TTL
-func NewMemoryCache(ttl time.Duration) *MemoryCache {
-	cache := &MemoryCache{
-		items: make(map[string]CacheEntry),
-		ttl:   ttl,
+// NewMemoryCache creates a new in-memory cache holding at most maxEntries
+// entries with the specified default TTL. name labels the cache metrics.
+// Call Stop to release the cleanup goroutine.
+func NewMemoryCache[K comparable, V any](name string, maxEntries int, ttl time.Duration) *MemoryCache[K, V] {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+
+	cache := &MemoryCache[K, V]{
+		name:       name,
+		items:      make(map[K]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		stop:       make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -31,64 +55,143 @@ func NewMemoryCache(ttl time.Duration) *MemoryCache {
 	return cache
 }
 
-// Get retrieves a value from the cache
-func (c *MemoryCache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// Get retrieves a value from the cache and marks it as recently used
+func (c *MemoryCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	entry, exists := c.items[key]
+	var zero V
+	elem, exists := c.items[key]
 	if !exists {
-		return nil, false
+		metrics.ObserveCacheLookup(c.name, false)
+		return zero, false
 	}
 
+	entry := elem.Value.(*CacheEntry[K, V])
+
 	// Check if expired
 	if time.Now().After(entry.ExpiresAt) {
-		return nil, false
+		c.removeElement(elem)
+		metrics.ObserveCacheLookup(c.name, false)
+		return zero, false
 	}
 
+	c.order.MoveToFront(elem)
+	metrics.ObserveCacheLookup(c.name, true)
 	return entry.Value, true
 }
 
-// Set stores a value in the cache with TTL
-func (c *MemoryCache) Set(key string, value interface{}) {
+// Set stores a value in the cache with the default TTL
+func (c *MemoryCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	ttl := c.ttl
+	c.mu.Unlock()
+
+	c.SetWithTTL(key, value, ttl)
+}
+
+// SetDefaultTTL changes the TTL of entries stored from now on with Set
+func (c *MemoryCache[K, V]) SetDefaultTTL(ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items[key] = CacheEntry{
+	c.ttl = ttl
+}
+
+// SetWithTTL stores a value in the cache with a specific TTL
+func (c *MemoryCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+
+	if elem, exists := c.items[key]; exists {
+		entry := elem.Value.(*CacheEntry[K, V])
+		entry.Value = value
+		entry.ExpiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&CacheEntry[K, V]{
+		Key:       key,
 		Value:     value,
-		ExpiresAt: time.Now().Add(c.ttl),
+		ExpiresAt: expiresAt,
+	})
+
+	// Evict least recently used entries beyond the size bound
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		metrics.IncCacheEviction(c.name)
 	}
 }
 
+// Len returns the number of entries in the cache, including expired ones not yet purged
+func (c *MemoryCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
 // Clear removes all entries from the cache
-func (c *MemoryCache) Clear() {
+func (c *MemoryCache[K, V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items = make(map[string]CacheEntry)
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
 }
 
 // Delete removes a specific key from the cache
-func (c *MemoryCache) Delete(key string) {
+func (c *MemoryCache[K, V]) Delete(key K) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.items, key)
+	if elem, exists := c.items[key]; exists {
+		c.removeElement(elem)
+	}
+}
+
+// Stop terminates the cleanup goroutine. It is safe to call more than once.
+func (c *MemoryCache[K, V]) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
 }
 
-// cleanup periodically removes expired entries
-func (c *MemoryCache) cleanup() {
-	ticker := time.NewTicker(1 * time.Minute)
+// removeElement unlinks an element; callers must hold c.mu
+func (c *MemoryCache[K, V]) removeElement(elem *list.Element) {
+	entry := c.order.Remove(elem).(*CacheEntry[K, V])
+	delete(c.items, entry.Key)
+}
+
+// cleanup periodically removes expired entries until Stop is called
+func (c *MemoryCache[K, V]) cleanup() {
+	ticker := time.NewTicker(cleanupInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.mu.Lock()
-		now := time.Now()
-		for key, entry := range c.items {
-			if now.After(entry.ExpiresAt) {
-				delete(c.items, key)
-			}
+	for {
+		select {
+		case <-ticker.C:
+			c.removeExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// removeExpired purges every expired entry
+func (c *MemoryCache[K, V]) removeExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if now.After(elem.Value.(*CacheEntry[K, V]).ExpiresAt) {
+			c.removeElement(elem)
 		}
-		c.mu.Unlock()
+		elem = prev
 	}
 }
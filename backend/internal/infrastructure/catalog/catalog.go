@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// mapping is the maintained category mapping built into the binary
+//
+//go:embed categories.yaml
+var mapping []byte
+
+// tagsOnly is the key of the entries that add tags without a category
+const tagsOnly = "tags"
+
+// entry is one item of the mapping file
+type entry struct {
+	ID   int      `yaml:"id"`
+	Name string   `yaml:"name"`
+	Tags []string `yaml:"tags"`
+}
+
+// Load returns the catalog of the built-in mapping file
+func Load() (*domain.Catalog, error) {
+	catalog, err := parse(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("built-in category mapping: %w", err)
+	}
+	return catalog, nil
+}
+
+// parse reads a mapping file: lists of entries keyed by category, plus a
+// "tags" list of entries that only add tags. Every item may be listed once.
+func parse(data []byte) (*domain.Catalog, error) {
+	var file map[string][]entry
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	var entries []domain.CatalogEntry
+	seen := make(map[string]string)
+	for key, list := range file {
+		category := domain.Category(key)
+		if key == tagsOnly {
+			category = ""
+		} else if !category.Valid() {
+			return nil, fmt.Errorf("unknown category %q", key)
+		}
+
+		for _, e := range list {
+			ref := strings.ToLower(strings.TrimSpace(e.Name))
+			if e.ID > 0 {
+				ref = fmt.Sprintf("#%d", e.ID)
+			}
+			if ref == "" {
+				return nil, fmt.Errorf("entry under %q has neither an id nor a name", key)
+			}
+			if previous, ok := seen[ref]; ok {
+				return nil, fmt.Errorf("item %q is listed under both %q and %q", ref, previous, key)
+			}
+			seen[ref] = key
+
+			entries = append(entries, domain.CatalogEntry{
+				ItemID:   e.ID,
+				Name:     e.Name,
+				Category: category,
+				Tags:     e.Tags,
+			})
+		}
+	}
+	return domain.NewCatalog(entries), nil
+}
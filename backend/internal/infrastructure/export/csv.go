@@ -0,0 +1,101 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// csvFlushEvery bounds how many rows are buffered before being written out
+const csvFlushEvery = 500
+
+var (
+	itemCSVHeader    = []string{"item_id", "name", "price", "high", "low", "volume", "avg_24h", "avg_7d", "trend", "updated_at"}
+	historyCSVHeader = []string{"item_id", "date", "price"}
+)
+
+// csvItemWriter writes items as CSV rows
+type csvItemWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVItemWriter(w io.Writer) (*csvItemWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(itemCSVHeader); err != nil {
+		return nil, err
+	}
+	return &csvItemWriter{w: cw}, nil
+}
+
+// WriteItem writes a single item row
+func (c *csvItemWriter) WriteItem(item domain.ItemPrice) error {
+	err := c.w.Write([]string{
+		strconv.Itoa(item.ItemID),
+		item.Name,
+		strconv.Itoa(item.Price),
+		strconv.Itoa(item.High),
+		strconv.Itoa(item.Low),
+		strconv.Itoa(item.Volume),
+		strconv.Itoa(item.Avg24h),
+		strconv.Itoa(item.Avg7d),
+		string(item.Trend),
+		item.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	return flushPeriodically(c.w, &c.rows)
+}
+
+// Close flushes buffered rows
+func (c *csvItemWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvHistoryWriter writes price history entries as CSV rows
+type csvHistoryWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVHistoryWriter(w io.Writer) (*csvHistoryWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(historyCSVHeader); err != nil {
+		return nil, err
+	}
+	return &csvHistoryWriter{w: cw}, nil
+}
+
+// WriteHistory writes a single history row
+func (c *csvHistoryWriter) WriteHistory(entry domain.PriceHistory) error {
+	err := c.w.Write([]string{
+		strconv.Itoa(entry.ItemID),
+		entry.Date.UTC().Format(time.RFC3339),
+		strconv.Itoa(entry.Price),
+	})
+	if err != nil {
+		return err
+	}
+	return flushPeriodically(c.w, &c.rows)
+}
+
+// Close flushes buffered rows
+func (c *csvHistoryWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// flushPeriodically flushes the CSV writer every csvFlushEvery rows
+func flushPeriodically(w *csv.Writer, rows *int) error {
+	*rows++
+	if *rows%csvFlushEvery != 0 {
+		return nil
+	}
+	w.Flush()
+	return w.Error()
+}
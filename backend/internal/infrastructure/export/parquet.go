@@ -0,0 +1,95 @@
+package export
+
+import (
+	"io"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRowGroupSize caps the rows buffered in memory before a row group is flushed
+const parquetRowGroupSize = 8 * 1024 * 1024 // 8MB
+
+// parquetItem is the Parquet schema for items
+type parquetItem struct {
+	ItemID    int32  `parquet:"name=item_id, type=INT32"`
+	Name      string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Price     int64  `parquet:"name=price, type=INT64"`
+	High      int64  `parquet:"name=high, type=INT64"`
+	Low       int64  `parquet:"name=low, type=INT64"`
+	Volume    int64  `parquet:"name=volume, type=INT64"`
+	Avg24h    int64  `parquet:"name=avg_24h, type=INT64"`
+	Avg7d     int64  `parquet:"name=avg_7d, type=INT64"`
+	Trend     string `parquet:"name=trend, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UpdatedAt int64  `parquet:"name=updated_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+// parquetHistory is the Parquet schema for price history entries
+type parquetHistory struct {
+	ItemID int32 `parquet:"name=item_id, type=INT32"`
+	Date   int64 `parquet:"name=date, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Price  int64 `parquet:"name=price, type=INT64"`
+}
+
+// parquetItemWriter writes items as Parquet rows
+type parquetItemWriter struct {
+	pw *writer.ParquetWriter
+}
+
+func newParquetItemWriter(w io.Writer) (*parquetItemWriter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetItem), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	return &parquetItemWriter{pw: pw}, nil
+}
+
+// WriteItem writes a single item row
+func (p *parquetItemWriter) WriteItem(item domain.ItemPrice) error {
+	return p.pw.Write(parquetItem{
+		ItemID:    int32(item.ItemID),
+		Name:      item.Name,
+		Price:     int64(item.Price),
+		High:      int64(item.High),
+		Low:       int64(item.Low),
+		Volume:    int64(item.Volume),
+		Avg24h:    int64(item.Avg24h),
+		Avg7d:     int64(item.Avg7d),
+		Trend:     string(item.Trend),
+		UpdatedAt: item.UpdatedAt.UnixMilli(),
+	})
+}
+
+// Close flushes the last row group and writes the file footer
+func (p *parquetItemWriter) Close() error {
+	return p.pw.WriteStop()
+}
+
+// parquetHistoryWriter writes price history entries as Parquet rows
+type parquetHistoryWriter struct {
+	pw *writer.ParquetWriter
+}
+
+func newParquetHistoryWriter(w io.Writer) (*parquetHistoryWriter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetHistory), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	return &parquetHistoryWriter{pw: pw}, nil
+}
+
+// WriteHistory writes a single history row
+func (p *parquetHistoryWriter) WriteHistory(entry domain.PriceHistory) error {
+	return p.pw.Write(parquetHistory{
+		ItemID: int32(entry.ItemID),
+		Date:   entry.Date.UnixMilli(),
+		Price:  int64(entry.Price),
+	})
+}
+
+// Close flushes the last row group and writes the file footer
+func (p *parquetHistoryWriter) Close() error {
+	return p.pw.WriteStop()
+}
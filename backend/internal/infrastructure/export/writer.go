@@ -0,0 +1,80 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// Format identifies an export file format
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat converts a format name into a Format, defaulting to CSV
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatParquet:
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("invalid export format %q (expected csv or parquet)", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// ItemWriter writes items one at a time to an export file
+type ItemWriter interface {
+	WriteItem(item domain.ItemPrice) error
+	// Close flushes buffered rows and writes any file footer; it does not close the underlying writer
+	Close() error
+}
+
+// HistoryWriter writes price history entries one at a time to an export file
+type HistoryWriter interface {
+	WriteHistory(entry domain.PriceHistory) error
+	// Close flushes buffered rows and writes any file footer; it does not close the underlying writer
+	Close() error
+}
+
+// NewItemWriter creates an ItemWriter for the given format
+func NewItemWriter(format Format, w io.Writer) (ItemWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVItemWriter(w)
+	case FormatParquet:
+		return newParquetItemWriter(w)
+	default:
+		return nil, fmt.Errorf("invalid export format %q", format)
+	}
+}
+
+// NewHistoryWriter creates a HistoryWriter for the given format
+func NewHistoryWriter(format Format, w io.Writer) (HistoryWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVHistoryWriter(w)
+	case FormatParquet:
+		return newParquetHistoryWriter(w)
+	default:
+		return nil, fmt.Errorf("invalid export format %q", format)
+	}
+}
@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// bankJSONItem is one item of a JSON bank snapshot; both our field names and
+// the id/quantity names of RuneLite's item containers are accepted
+type bankJSONItem struct {
+	ItemID   *int `json:"item_id"`
+	ID       *int `json:"id"`
+	Qty      *int `json:"qty"`
+	Quantity *int `json:"quantity"`
+}
+
+// ParseBankSnapshot reads a bank or inventory snapshot in one of these forms:
+//   - JSON: [{"item_id": 4151, "qty": 1}] or {"items": [...]}, also with
+//     RuneLite's "id" and "quantity" names
+//   - RuneLite Bank Memory export: tab-separated "Item id, Item name, Item
+//     quantity" lines, with or without the header line
+//   - "item_id,qty" or "item_id<TAB>qty" lines
+func ParseBankSnapshot(r io.Reader) ([]domain.Holding, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("snapshot is empty")
+	}
+	if data[0] == '[' || data[0] == '{' {
+		return parseBankJSON(data)
+	}
+	return parseBankText(string(data))
+}
+
+// parseBankJSON reads a JSON array of items or an object with an items array
+func parseBankJSON(data []byte) ([]domain.Holding, error) {
+	var items []bankJSONItem
+	if data[0] == '{' {
+		var wrapper struct {
+			Items []bankJSONItem `json:"items"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid JSON snapshot: %w", err)
+		}
+		items = wrapper.Items
+	} else if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid JSON snapshot: %w", err)
+	}
+
+	holdings := make([]domain.Holding, 0, len(items))
+	for i, item := range items {
+		id, qty := item.ItemID, item.Qty
+		if id == nil {
+			id = item.ID
+		}
+		if qty == nil {
+			qty = item.Quantity
+		}
+		if id == nil || qty == nil {
+			return nil, fmt.Errorf("item %d: item_id and qty are required", i)
+		}
+		holdings = append(holdings, domain.Holding{ItemID: *id, Quantity: *qty})
+	}
+	return holdings, nil
+}
+
+// parseBankText reads delimited lines whose first column is the item ID and
+// last column the quantity. A first line without a numeric ID is a header.
+func parseBankText(text string) ([]domain.Holding, error) {
+	var holdings []domain.Holding
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		sep := "\t"
+		if !strings.Contains(line, sep) {
+			sep = ","
+		}
+		fields := strings.Split(line, sep)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected item ID and quantity columns", i+1)
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			if i == 0 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("line %d: invalid item ID %q", i+1, fields[0])
+		}
+		qty, err := strconv.Atoi(strings.TrimSpace(fields[len(fields)-1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid quantity %q", i+1, fields[len(fields)-1])
+		}
+		holdings = append(holdings, domain.Holding{ItemID: id, Quantity: qty})
+	}
+	return holdings, nil
+}
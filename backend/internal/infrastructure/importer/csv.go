@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// csvSource reads price history rows from CSV with a header line.
+// Required columns: item_id, price and date (or timestamp). Extra columns are ignored,
+// so files produced by /export/history can be imported back.
+type csvSource struct {
+	r         *csv.Reader
+	itemIDCol int
+	priceCol  int
+	dateCol   int
+}
+
+func newCSVSource(r io.Reader) (*csvSource, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // Ragged rows are reported per row instead of aborting
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	src := &csvSource{r: cr, itemIDCol: -1, priceCol: -1, dateCol: -1}
+	if i, ok := columns["item_id"]; ok {
+		src.itemIDCol = i
+	}
+	if i, ok := columns["price"]; ok {
+		src.priceCol = i
+	}
+	if i, ok := columns["date"]; ok {
+		src.dateCol = i
+	} else if i, ok := columns["timestamp"]; ok {
+		src.dateCol = i
+	}
+
+	if src.itemIDCol < 0 || src.priceCol < 0 || src.dateCol < 0 {
+		return nil, fmt.Errorf("csv header must contain item_id, price and date (or timestamp) columns")
+	}
+
+	return src, nil
+}
+
+// Next returns the next row; parse problems are reported on the record, not as errors
+func (s *csvSource) Next() (domain.HistoryImportRecord, error) {
+	row, err := s.r.Read()
+	line, _ := s.r.FieldPos(0)
+
+	if errors.Is(err, io.EOF) {
+		return domain.HistoryImportRecord{}, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return domain.HistoryImportRecord{Line: parseErr.Line, Err: parseErr.Err}, nil
+		}
+		return domain.HistoryImportRecord{}, err
+	}
+
+	record := domain.HistoryImportRecord{Line: line}
+	for _, col := range []int{s.itemIDCol, s.priceCol, s.dateCol} {
+		if col >= len(row) {
+			record.Err = fmt.Errorf("expected at least %d columns, got %d", col+1, len(row))
+			return record, nil
+		}
+	}
+
+	if record.Entry.ItemID, record.Err = parseItemID(row[s.itemIDCol]); record.Err != nil {
+		return record, nil
+	}
+	if record.Entry.Price, record.Err = parsePrice(row[s.priceCol]); record.Err != nil {
+		return record, nil
+	}
+	record.Entry.Date, record.Err = parseTimestamp(row[s.dateCol])
+	return record, nil
+}
@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// jsonRow mirrors one price observation in a JSON dump.
+// Values are kept raw so strings and numbers are both accepted.
+type jsonRow struct {
+	ItemID    json.RawMessage `json:"item_id"`
+	Price     json.RawMessage `json:"price"`
+	Date      json.RawMessage `json:"date"`
+	Timestamp json.RawMessage `json:"timestamp"`
+}
+
+// jsonSource reads price history from a JSON array of objects or from
+// newline-delimited JSON objects. Line holds the 1-based record number.
+type jsonSource struct {
+	dec     *json.Decoder
+	inArray bool
+	index   int
+}
+
+func newJSONSource(r *bufio.Reader) (*jsonSource, error) {
+	src := &jsonSource{dec: json.NewDecoder(r)}
+
+	// Skip a UTF-8 byte order mark written by some spreadsheet tools
+	if bom, err := r.Peek(3); err == nil && string(bom) == "\ufeff" {
+		r.Discard(3)
+	}
+
+	// Peek the first significant byte to tell an array from NDJSON
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return src, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.ContainsRune(" \t\r\n", rune(b[0])) {
+			r.ReadByte()
+			continue
+		}
+		if b[0] == '[' {
+			if _, err := src.dec.Token(); err != nil {
+				return nil, err
+			}
+			src.inArray = true
+		}
+		return src, nil
+	}
+}
+
+// Next decodes the next object. Malformed JSON aborts the import because the
+// decoder cannot resynchronize; invalid field values are reported on the record.
+func (s *jsonSource) Next() (domain.HistoryImportRecord, error) {
+	if s.inArray && !s.dec.More() {
+		return domain.HistoryImportRecord{}, io.EOF
+	}
+
+	var row jsonRow
+	if err := s.dec.Decode(&row); err != nil {
+		if err == io.EOF {
+			return domain.HistoryImportRecord{}, io.EOF
+		}
+		return domain.HistoryImportRecord{}, fmt.Errorf("record %d: %w", s.index+1, err)
+	}
+	s.index++
+
+	record := domain.HistoryImportRecord{Line: s.index}
+
+	if record.Entry.ItemID, record.Err = parseItemID(rawString(row.ItemID)); record.Err != nil {
+		return record, nil
+	}
+	if record.Entry.Price, record.Err = parsePrice(rawString(row.Price)); record.Err != nil {
+		return record, nil
+	}
+
+	date := row.Date
+	if len(date) == 0 {
+		date = row.Timestamp
+	}
+	record.Entry.Date, record.Err = parseTimestamp(rawString(date))
+	return record, nil
+}
+
+// rawString returns a raw JSON value without the quotes of a string literal
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// Format identifies an import file format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat converts a format name into a Format
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON, "ndjson", "jsonl":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("invalid import format %q (expected csv or json)", name)
+	}
+}
+
+// NewHistorySource creates a record source reading price history in the given format
+func NewHistorySource(format Format, r io.Reader) (domain.HistoryRecordSource, error) {
+	br := bufio.NewReader(r)
+	switch format {
+	case FormatCSV:
+		return newCSVSource(br)
+	case FormatJSON:
+		return newJSONSource(br)
+	default:
+		return nil, fmt.Errorf("invalid import format %q", format)
+	}
+}
+
+// timestampLayouts are the textual date formats accepted in dumps
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTimestamp accepts RFC 3339 and common date layouts (UTC assumed when no
+// zone is given) as well as Unix timestamps in seconds or milliseconds
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("timestamp is required")
+	}
+
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Values this large cannot be seconds in any plausible range
+		if unix > 1e12 {
+			return time.UnixMilli(unix).UTC(), nil
+		}
+		return time.Unix(unix, 0).UTC(), nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// parsePrice accepts integer prices, tolerating a trailing ".0" from spreadsheets
+func parsePrice(value string) (int, error) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), ".0")
+	price, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q", value)
+	}
+	return price, nil
+}
+
+// parseItemID parses an item ID column
+func parseItemID(value string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid item_id %q", value)
+	}
+	return id, nil
+}
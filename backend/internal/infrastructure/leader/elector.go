@@ -0,0 +1,123 @@
+// Package leader decides which process runs the price updater when several
+// processes share a persistent repository.
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Elector runs work only while this process is the leader.
+// work must return once its context is cancelled.
+type Elector interface {
+	Run(ctx context.Context, work func(ctx context.Context))
+}
+
+// Single is an Elector for single-worker deployments: the process is always the leader
+type Single struct{}
+
+// Run calls work until ctx is cancelled
+func (Single) Run(ctx context.Context, work func(ctx context.Context)) {
+	metrics.SetLeader(true)
+	defer metrics.SetLeader(false)
+	work(ctx)
+}
+
+// PostgresElector elects a leader with a PostgreSQL session advisory lock.
+// The lock is released by the database if the holder's connection drops,
+// so a crashed leader is replaced within one retry interval.
+type PostgresElector struct {
+	pool  *pgxpool.Pool
+	key   int64
+	retry time.Duration
+}
+
+// NewPostgresElector creates an elector competing for the advisory lock key.
+// Followers try to take over, and the leader checks its connection, every retry.
+func NewPostgresElector(pool *pgxpool.Pool, key int64, retry time.Duration) *PostgresElector {
+	return &PostgresElector{pool: pool, key: key, retry: retry}
+}
+
+// Run competes for leadership until ctx is cancelled, calling work each time it is won
+func (e *PostgresElector) Run(ctx context.Context, work func(ctx context.Context)) {
+	ticker := time.NewTicker(e.retry)
+	defer ticker.Stop()
+
+	for {
+		if err := e.lead(ctx, work); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "leader election failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead takes the lock if it is free and runs work until ctx is cancelled
+// or the connection holding the lock is lost
+func (e *PostgresElector) lead(ctx context.Context, work func(ctx context.Context)) error {
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, e.key).Scan(&acquired); err != nil {
+		return err
+	}
+	if !acquired {
+		slog.DebugContext(ctx, "another process is the leader")
+		return nil
+	}
+
+	slog.InfoContext(ctx, "acquired leadership", "lock_key", e.key)
+	metrics.SetLeader(true)
+	defer metrics.SetLeader(false)
+
+	workCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		work(workCtx)
+	}()
+
+	ticker := time.NewTicker(e.retry)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			// Unlock with a fresh context since ctx is already cancelled
+			unlockCtx, unlockCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer unlockCancel()
+			if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, e.key); err != nil {
+				// Closing the connection releases the lock too
+				conn.Hijack().Close(unlockCtx)
+				return err
+			}
+			slog.InfoContext(unlockCtx, "released leadership", "lock_key", e.key)
+			return nil
+
+		case <-ticker.C:
+			if err := conn.Ping(ctx); err != nil && ctx.Err() == nil {
+				// The lock may already belong to someone else; stop working
+				// and drop the connection rather than return it to the pool
+				cancel()
+				<-done
+				conn.Hijack().Close(context.Background())
+				slog.WarnContext(ctx, "lost leadership", "error", err)
+				return err
+			}
+		}
+	}
+}
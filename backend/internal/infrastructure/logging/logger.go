@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ParseLevel converts a level name (debug, info, warn, error) into a slog.Level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q", name)
+	}
+}
+
+// NewLogger creates a JSON logger that writes to w at the given level.
+// Records logged with a request context carry the chi request ID and,
+// when a span is active, the trace and span IDs.
+func NewLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(&requestIDHandler{Handler: handler})
+}
+
+// requestIDHandler adds request and trace correlation attributes from the context to each record
+type requestIDHandler struct {
+	slog.Handler
+}
+
+// Handle adds the correlation IDs, when present, before delegating to the wrapped handler
+func (h *requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if reqID := middleware.GetReqID(ctx); reqID != "" {
+			record.AddAttrs(slog.String("request_id", reqID))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			record.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler that keeps adding request IDs
+func (h *requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &requestIDHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that keeps adding request IDs
+func (h *requestIDHandler) WithGroup(name string) slog.Handler {
+	return &requestIDHandler{Handler: h.Handler.WithGroup(name)}
+}
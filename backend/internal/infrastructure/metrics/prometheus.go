@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "osrs_flip"
+
+// Registry holds every collector exposed on /metrics.
+// A dedicated registry avoids leaking collectors registered by dependencies.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests processed, by route pattern, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by route pattern, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	rateLimitRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limiter.",
+	})
+
+	providerFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_fetch_duration_seconds",
+		Help:      "Latency of requests to the price provider, by endpoint.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"endpoint"})
+
+	providerFetchErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_fetch_errors_total",
+		Help:      "Failed requests to the price provider, by endpoint.",
+	}, []string{"endpoint"})
+
+	updateCycleDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "price_update_duration_seconds",
+		Help:      "Duration of a full price update cycle.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	updateCyclesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "price_updates_total",
+		Help:      "Price update cycles, by result (success or error).",
+	}, []string{"result"})
+
+	itemsTracked = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "items_updated",
+		Help:      "Number of items saved by the last successful price update.",
+	})
+
+	repositoryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_operation_duration_seconds",
+		Help:      "Latency of repository operations, by operation.",
+		Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5},
+	}, []string{"operation"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups, by cache name and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	cacheEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_evictions_total",
+		Help:      "Entries evicted because a cache reached its size bound, by cache name.",
+	}, []string{"cache"})
+
+	apiKeyRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_key_requests_total",
+		Help:      "Requests carrying an API key, by result (accepted, quota_exceeded or invalid).",
+	}, []string{"result"})
+
+	workerLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "worker_leader",
+		Help:      "1 while this process runs the price updater, 0 otherwise.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		rateLimitRejections,
+		providerFetchDuration,
+		providerFetchErrors,
+		updateCycleDuration,
+		updateCyclesTotal,
+		itemsTracked,
+		repositoryDuration,
+		cacheRequests,
+		cacheEvictions,
+		apiKeyRequests,
+		workerLeader,
+	)
+}
+
+// Handler returns the HTTP handler serving metrics in Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a served HTTP request
+func ObserveHTTPRequest(route, method, status string, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(route, method, status).Inc()
+	httpRequestDuration.WithLabelValues(route, method, status).Observe(duration.Seconds())
+}
+
+// IncRateLimitRejection records a request rejected by the rate limiter
+func IncRateLimitRejection() {
+	rateLimitRejections.Inc()
+}
+
+// IncAPIKeyRequest records a request authenticated with an API key
+func IncAPIKeyRequest(result string) {
+	apiKeyRequests.WithLabelValues(result).Inc()
+}
+
+// ObserveProviderFetch records a request to the price provider
+func ObserveProviderFetch(endpoint string, duration time.Duration, err error) {
+	providerFetchDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if err != nil {
+		providerFetchErrors.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// ObserveUpdateCycle records a price update cycle
+func ObserveUpdateCycle(duration time.Duration, err error) {
+	updateCycleDuration.Observe(duration.Seconds())
+	if err != nil {
+		updateCyclesTotal.WithLabelValues("error").Inc()
+		return
+	}
+	updateCyclesTotal.WithLabelValues("success").Inc()
+}
+
+// SetItemCount records the number of items saved by the last update
+func SetItemCount(count int) {
+	itemsTracked.Set(float64(count))
+}
+
+// SetLeader records whether this process currently runs the price updater
+func SetLeader(leader bool) {
+	if leader {
+		workerLeader.Set(1)
+		return
+	}
+	workerLeader.Set(0)
+}
+
+// ObserveRepositoryOperation records the latency of a repository call
+func ObserveRepositoryOperation(operation string, duration time.Duration) {
+	repositoryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveCacheLookup records a cache hit or miss
+func ObserveCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// IncCacheEviction records an entry evicted from a full cache
+func IncCacheEviction(cache string) {
+	cacheEvictions.WithLabelValues(cache).Inc()
+}
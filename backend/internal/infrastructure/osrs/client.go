@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
 	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // OsrsWikiClient implements PriceProvider for OSRS Wiki API.
@@ -27,7 +30,7 @@ type OsrsWikiClient struct {
 
 	// Cache for item names mapping (longer TTL since it changes rarely)
 	namesCacheMu sync.RWMutex
-	cachedNames  map[int]string
+	cachedNames  map[int]domain.ItemMapping
 	namesCachedAt time.Time
 	namesCacheTTL time.Duration
 }
@@ -43,60 +46,47 @@ type latestResponse struct {
 
 // mappingResponse mirrors the /mapping payload.
 type mappingResponse []struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Limit int    `json:"limit"` // Absent for items without a known buy limit
 }
 
-// NewOsrsWikiClient creates a new OSRS Wiki client with sane defaults.
-func NewOsrsWikiClient() *OsrsWikiClient {
-	timeout := 10 * time.Second
-	if v := os.Getenv("OSRS_WIKI_TIMEOUT_MS"); v != "" {
-		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
-			timeout = time.Duration(ms) * time.Millisecond
-		}
-	}
-
-	baseURL := os.Getenv("OSRS_WIKI_BASE_URL")
-	if baseURL == "" {
-		baseURL = "https://prices.runescape.wiki/api/v1/osrs"
-	}
-
-	userAgent := os.Getenv("OSRS_WIKI_USER_AGENT")
-	if userAgent == "" {
-		userAgent = "OSRS-GoodToFlip/1.0 (contact: your-email@example.com)"
-	}
-
-	cacheTTL := 60 * time.Second
-	if v := os.Getenv("OSRS_WIKI_CACHE_TTL_SEC"); v != "" {
-		if s, err := strconv.Atoi(v); err == nil && s > 0 {
-			cacheTTL = time.Duration(s) * time.Second
-		}
-	}
-
-	// Names cache TTL - longer since mapping changes rarely (default: 1 hour)
-	namesCacheTTL := 1 * time.Hour
-	if v := os.Getenv("OSRS_WIKI_NAMES_CACHE_TTL_SEC"); v != "" {
-		if s, err := strconv.Atoi(v); err == nil && s > 0 {
-			namesCacheTTL = time.Duration(s) * time.Second
-		}
-	}
+// timeseriesResponse mirrors the /timeseries payload.
+type timeseriesResponse struct {
+	Data []struct {
+		Timestamp    int64 `json:"timestamp"`
+		AvgHighPrice *int  `json:"avgHighPrice"`
+		AvgLowPrice  *int  `json:"avgLowPrice"`
+	} `json:"data"`
+}
 
+// NewOsrsWikiClient creates a new OSRS Wiki client from the validated configuration.
+func NewOsrsWikiClient(cfg config.OSRSWikiConfig) *OsrsWikiClient {
 	return &OsrsWikiClient{
-		httpClient:    &http.Client{Timeout: timeout},
-		baseURL:       baseURL,
-		userAgent:     userAgent,
-		cacheTTL:      cacheTTL,
-		namesCacheTTL: namesCacheTTL,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout.Std(),
+			// Creates client spans and injects W3C trace context headers
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		baseURL:       cfg.BaseURL,
+		userAgent:     cfg.UserAgent,
+		cacheTTL:      cfg.CacheTTL.Std(),
+		namesCacheTTL: cfg.NamesCacheTTL.Std(),
 	}
 }
 
 // FetchLatestPrices fetches latest high/low prices with a small TTL cache.
-func (c *OsrsWikiClient) FetchLatestPrices(ctx context.Context) (map[int]domain.PriceSnapshot, error) {
+func (c *OsrsWikiClient) FetchLatestPrices(ctx context.Context) (_ map[int]domain.PriceSnapshot, err error) {
 	// serve from cache when fresh
-	if data, ok := c.getCached(); ok {
+	data, ok := c.getCached()
+	metrics.ObserveCacheLookup("latest", ok)
+	if ok {
 		return data, nil
 	}
 
+	start := time.Now()
+	defer func() { metrics.ObserveProviderFetch("latest", time.Since(start), err) }()
+
 	url := fmt.Sprintf("%s/latest", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -111,6 +101,11 @@ func (c *OsrsWikiClient) FetchLatestPrices(ctx context.Context) (map[int]domain.
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		slog.WarnContext(ctx, "osrs wiki request failed",
+			"endpoint", "latest",
+			"status_code", resp.StatusCode,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
 		return nil, fmt.Errorf("osrs wiki: status %d", resp.StatusCode)
 	}
 
@@ -127,18 +122,29 @@ func (c *OsrsWikiClient) FetchLatestPrices(ctx context.Context) (map[int]domain.
 		}
 		result[id] = domain.PriceSnapshot{High: v.High, Low: v.Low}
 	}
+	slog.DebugContext(ctx, "osrs wiki request succeeded",
+		"endpoint", "latest",
+		"status_code", resp.StatusCode,
+		"item_count", len(result),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
 
 	c.setCache(result)
 	return result, nil
 }
 
-// FetchItemNames fetches item ID to name mapping from OSRS Wiki API
-func (c *OsrsWikiClient) FetchItemNames(ctx context.Context) (map[int]string, error) {
+// FetchItemMapping fetches the names and buy limits of every item from OSRS Wiki API
+func (c *OsrsWikiClient) FetchItemMapping(ctx context.Context) (_ map[int]domain.ItemMapping, err error) {
 	// Check cache first
-	if data, ok := c.getCachedNames(); ok {
+	data, ok := c.getCachedNames()
+	metrics.ObserveCacheLookup("names", ok)
+	if ok {
 		return data, nil
 	}
 
+	start := time.Now()
+	defer func() { metrics.ObserveProviderFetch("mapping", time.Since(start), err) }()
+
 	url := fmt.Sprintf("%s/mapping", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -153,6 +159,11 @@ func (c *OsrsWikiClient) FetchItemNames(ctx context.Context) (map[int]string, er
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		slog.WarnContext(ctx, "osrs wiki request failed",
+			"endpoint", "mapping",
+			"status_code", resp.StatusCode,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
 		return nil, fmt.Errorf("osrs wiki mapping: status %d", resp.StatusCode)
 	}
 
@@ -161,17 +172,84 @@ func (c *OsrsWikiClient) FetchItemNames(ctx context.Context) (map[int]string, er
 		return nil, err
 	}
 
-	result := make(map[int]string, len(payload))
+	result := make(map[int]domain.ItemMapping, len(payload))
 	for _, item := range payload {
-		result[item.ID] = item.Name
+		result[item.ID] = domain.ItemMapping{Name: item.Name, BuyLimit: item.Limit}
 	}
+	slog.DebugContext(ctx, "osrs wiki request succeeded",
+		"endpoint", "mapping",
+		"status_code", resp.StatusCode,
+		"item_count", len(result),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
 
 	// Cache the result
 	c.setCachedNames(result)
 	return result, nil
 }
 
-func (c *OsrsWikiClient) getCachedNames() (map[int]string, bool) {
+// FetchPriceHistory fetches up to 365 data points of an item from /timeseries.
+// Each point uses the average high price, falling back to the average low price.
+func (c *OsrsWikiClient) FetchPriceHistory(ctx context.Context, itemID int, timestep string) (_ []domain.PriceHistory, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveProviderFetch("timeseries", time.Since(start), err) }()
+
+	url := fmt.Sprintf("%s/timeseries?timestep=%s&id=%d", c.baseURL, timestep, itemID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.WarnContext(ctx, "osrs wiki request failed",
+			"endpoint", "timeseries",
+			"item_id", itemID,
+			"status_code", resp.StatusCode,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		return nil, fmt.Errorf("osrs wiki timeseries: status %d", resp.StatusCode)
+	}
+
+	var payload timeseriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.PriceHistory, 0, len(payload.Data))
+	for _, point := range payload.Data {
+		price := point.AvgHighPrice
+		if price == nil {
+			price = point.AvgLowPrice
+		}
+		// Skip intervals without trades
+		if price == nil {
+			continue
+		}
+		result = append(result, domain.PriceHistory{
+			ItemID: itemID,
+			Price:  *price,
+			Date:   time.Unix(point.Timestamp, 0).UTC(),
+		})
+	}
+	slog.DebugContext(ctx, "osrs wiki request succeeded",
+		"endpoint", "timeseries",
+		"item_id", itemID,
+		"status_code", resp.StatusCode,
+		"point_count", len(result),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	return result, nil
+}
+
+func (c *OsrsWikiClient) getCachedNames() (map[int]domain.ItemMapping, bool) {
 	c.namesCacheMu.RLock()
 	defer c.namesCacheMu.RUnlock()
 	if c.cachedNames == nil {
@@ -181,14 +259,14 @@ func (c *OsrsWikiClient) getCachedNames() (map[int]string, bool) {
 		return nil, false
 	}
 	// return a copy to avoid external mutation
-	out := make(map[int]string, len(c.cachedNames))
+	out := make(map[int]domain.ItemMapping, len(c.cachedNames))
 	for k, v := range c.cachedNames {
 		out[k] = v
 	}
 	return out, true
 }
 
-func (c *OsrsWikiClient) setCachedNames(data map[int]string) {
+func (c *OsrsWikiClient) setCachedNames(data map[int]domain.ItemMapping) {
 	c.namesCacheMu.Lock()
 	defer c.namesCacheMu.Unlock()
 	c.cachedNames = data
@@ -218,3 +296,25 @@ func (c *OsrsWikiClient) setCache(data map[int]domain.PriceSnapshot) {
 	c.cachedLatest = data
 	c.cachedAt = time.Now()
 }
+
+// SetCacheTTLs changes how long latest prices and item names are cached
+func (c *OsrsWikiClient) SetCacheTTLs(latest, names time.Duration) {
+	c.cacheMu.Lock()
+	c.cacheTTL = latest
+	c.cacheMu.Unlock()
+
+	c.namesCacheMu.Lock()
+	c.namesCacheTTL = names
+	c.namesCacheMu.Unlock()
+}
+
+// NamesCacheAge reports how old the item names cache is and its configured TTL.
+// ok is false when the names have never been fetched.
+func (c *OsrsWikiClient) NamesCacheAge() (age time.Duration, ttl time.Duration, ok bool) {
+	c.namesCacheMu.RLock()
+	defer c.namesCacheMu.RUnlock()
+	if c.cachedNames == nil {
+		return 0, c.namesCacheTTL, false
+	}
+	return time.Since(c.namesCachedAt), c.namesCacheTTL, true
+}
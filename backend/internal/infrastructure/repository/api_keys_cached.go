@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/cache"
+)
+
+// CachedAPIKeyRepository caches key lookups by hash, which happen on every
+// authenticated request. Unknown hashes are cached too, so invalid keys do
+// not reach the database. Updates and revocations made through this process
+// clear the cache; other replicas see them once their entries expire.
+type CachedAPIKeyRepository struct {
+	domain.APIKeyRepository
+	byHash *cache.MemoryCache[string, *domain.APIKey] // nil value: no such key
+}
+
+// NewCachedAPIKeyRepository wraps next with a lookup cache whose entries live for ttl.
+// Call Stop to release the cache cleanup goroutine.
+func NewCachedAPIKeyRepository(next domain.APIKeyRepository, ttl time.Duration) *CachedAPIKeyRepository {
+	return &CachedAPIKeyRepository{
+		APIKeyRepository: next,
+		byHash:           cache.NewMemoryCache[string, *domain.APIKey]("api_keys", 10000, ttl),
+	}
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of its secret, from the cache when possible
+func (r *CachedAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	if key, ok := r.byHash.Get(hash); ok {
+		if key == nil {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		keyCopy := *key
+		return &keyCopy, nil
+	}
+
+	key, err := r.APIKeyRepository.GetAPIKeyByHash(ctx, hash)
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		r.byHash.Set(hash, nil)
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+
+	keyCopy := *key
+	r.byHash.Set(hash, &keyCopy)
+	return key, nil
+}
+
+// CreateAPIKey stores a new key and forgets its hash if it was cached as unknown
+func (r *CachedAPIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey) error {
+	err := r.APIKeyRepository.CreateAPIKey(ctx, key)
+	r.byHash.Delete(key.Hash)
+	return err
+}
+
+// UpdateAPIKey changes the name and limits of a key
+func (r *CachedAPIKeyRepository) UpdateAPIKey(ctx context.Context, key domain.APIKey) error {
+	err := r.APIKeyRepository.UpdateAPIKey(ctx, key)
+	r.byHash.Clear()
+	return err
+}
+
+// RevokeAPIKey marks a key as revoked, effective immediately in this process
+func (r *CachedAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	err := r.APIKeyRepository.RevokeAPIKey(ctx, id, at)
+	r.byHash.Clear()
+	return err
+}
+
+// Stop releases the cache cleanup goroutine
+func (r *CachedAPIKeyRepository) Stop() {
+	r.byHash.Stop()
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// InMemoryAPIKeyRepository implements APIKeyRepository in memory.
+// Keys are lost on restart, so it only suits development and single instances.
+type InMemoryAPIKeyRepository struct {
+	mu     sync.Mutex
+	keys   map[string]*domain.APIKey
+	byHash map[string]string                            // hash -> id
+	usage  map[string]map[time.Time]*domain.APIKeyUsage // id -> day -> usage
+}
+
+// NewInMemoryAPIKeyRepository creates an empty in-memory API key repository
+func NewInMemoryAPIKeyRepository() *InMemoryAPIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		keys:   make(map[string]*domain.APIKey),
+		byHash: make(map[string]string),
+		usage:  make(map[string]map[time.Time]*domain.APIKeyUsage),
+	}
+}
+
+// CreateAPIKey stores a new key
+func (r *InMemoryAPIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return errors.New("api key id already exists")
+	}
+	if _, exists := r.byHash[key.Hash]; exists {
+		return errors.New("api key hash already exists")
+	}
+
+	r.keys[key.ID] = &key
+	r.byHash[key.Hash] = key.ID
+	return nil
+}
+
+// GetAPIKey retrieves a key by its ID
+func (r *InMemoryAPIKeyRepository) GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.getLocked(id)
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of its secret
+func (r *InMemoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, exists := r.byHash[hash]
+	if !exists {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return r.getLocked(id)
+}
+
+// getLocked returns a copy of the key; r.mu must be held
+func (r *InMemoryAPIKeyRepository) getLocked(id string) (*domain.APIKey, error) {
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	keyCopy := *key
+	return &keyCopy, nil
+}
+
+// ListAPIKeys returns every key, oldest first
+func (r *InMemoryAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+// UpdateAPIKey changes the name and limits of a key
+func (r *InMemoryAPIKeyRepository) UpdateAPIKey(ctx context.Context, key domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.keys[key.ID]
+	if !exists {
+		return domain.ErrAPIKeyNotFound
+	}
+	stored.Name = key.Name
+	stored.RateLimit = key.RateLimit
+	stored.DailyQuota = key.DailyQuota
+	return nil
+}
+
+// RevokeAPIKey marks a key as revoked; revoking twice keeps the first time
+func (r *InMemoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.keys[id]
+	if !exists {
+		return domain.ErrAPIKeyNotFound
+	}
+	if stored.RevokedAt == nil {
+		stored.RevokedAt = &at
+	}
+	return nil
+}
+
+// ConsumeAPIKeyQuota counts one request against the key's daily quota
+func (r *InMemoryAPIKeyRepository) ConsumeAPIKeyQuota(ctx context.Context, id string, at time.Time, quota int) (domain.QuotaResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return domain.QuotaResult{}, domain.ErrAPIKeyNotFound
+	}
+	key.LastUsedAt = &at
+
+	day := domain.UsageDay(at)
+	days, exists := r.usage[id]
+	if !exists {
+		days = make(map[time.Time]*domain.APIKeyUsage)
+		r.usage[id] = days
+	}
+	usage, exists := days[day]
+	if !exists {
+		usage = &domain.APIKeyUsage{Day: day}
+		days[day] = usage
+	}
+
+	if quota > 0 && usage.Requests >= int64(quota) {
+		usage.Rejected++
+		return domain.QuotaResult{Allowed: false, Used: usage.Requests}, nil
+	}
+	usage.Requests++
+	return domain.QuotaResult{Allowed: true, Used: usage.Requests}, nil
+}
+
+// GetAPIKeyUsage returns the daily counters of the last days, newest first
+func (r *InMemoryAPIKeyRepository) GetAPIKeyUsage(ctx context.Context, id string, days int) ([]domain.APIKeyUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[id]; !exists {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	cutoff := domain.UsageDay(time.Now()).AddDate(0, 0, -(days - 1))
+	usage := make([]domain.APIKeyUsage, 0)
+	for day, counters := range r.usage[id] {
+		if !day.Before(cutoff) {
+			usage = append(usage, *counters)
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Day.After(usage[j].Day)
+	})
+	return usage, nil
+}
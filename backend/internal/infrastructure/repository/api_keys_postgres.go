@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// apiKeyColumns is the column list scanned by scanAPIKey; the last use comes
+// from the usage counters instead of a write to api_keys on every request
+const apiKeyColumns = `k.id, k.name, k.prefix, k.key_hash, k.rate_limit, k.daily_quota, k.created_at, k.revoked_at,
+	(SELECT max(u.last_request_at) FROM api_key_usage u WHERE u.key_id = k.id)`
+
+// PostgresAPIKeyRepository implements APIKeyRepository on PostgreSQL, so
+// keys and quotas are shared by every API replica
+type PostgresAPIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAPIKeyRepository creates an API key repository on pool
+func NewPostgresAPIKeyRepository(pool *pgxpool.Pool) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{pool: pool}
+}
+
+// scanAPIKey scans a row selected with apiKeyColumns
+func scanAPIKey(row pgx.CollectableRow) (domain.APIKey, error) {
+	var key domain.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &key.RateLimit, &key.DailyQuota,
+		&key.CreatedAt, &key.RevokedAt, &key.LastUsedAt)
+	return key, err
+}
+
+// queryAPIKeys runs an API key query and collects the rows
+func (r *PostgresAPIKeyRepository) queryAPIKeys(ctx context.Context, sql string, args ...any) ([]domain.APIKey, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanAPIKey)
+}
+
+// CreateAPIKey stores a new key
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO api_keys (id, name, prefix, key_hash, rate_limit, daily_quota, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		key.ID, key.Name, key.Prefix, key.Hash, key.RateLimit, key.DailyQuota, key.CreatedAt)
+	return err
+}
+
+// GetAPIKey retrieves a key by its ID
+func (r *PostgresAPIKeyRepository) GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	keys, err := r.queryAPIKeys(ctx, `SELECT `+apiKeyColumns+` FROM api_keys k WHERE k.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return &keys[0], nil
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of its secret
+func (r *PostgresAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	keys, err := r.queryAPIKeys(ctx, `SELECT `+apiKeyColumns+` FROM api_keys k WHERE k.key_hash = $1`, hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return &keys[0], nil
+}
+
+// ListAPIKeys returns every key, oldest first
+func (r *PostgresAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return r.queryAPIKeys(ctx, `SELECT `+apiKeyColumns+` FROM api_keys k ORDER BY k.created_at, k.id`)
+}
+
+// UpdateAPIKey changes the name and limits of a key
+func (r *PostgresAPIKeyRepository) UpdateAPIKey(ctx context.Context, key domain.APIKey) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE api_keys SET name = $2, rate_limit = $3, daily_quota = $4 WHERE id = $1`,
+		key.ID, key.Name, key.RateLimit, key.DailyQuota)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// RevokeAPIKey marks a key as revoked; revoking twice keeps the first time
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1`, id, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// ConsumeAPIKeyQuota counts one request against the key's daily quota in a
+// single upsert, so concurrent replicas never accept more than the quota
+func (r *PostgresAPIKeyRepository) ConsumeAPIKeyQuota(ctx context.Context, id string, at time.Time, quota int) (domain.QuotaResult, error) {
+	// SET expressions read the row as it was before the update
+	var result domain.QuotaResult
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_key_usage AS u (key_id, day, requests, rejected, last_accepted, last_request_at)
+		VALUES ($1, $2, 1, 0, TRUE, $4)
+		ON CONFLICT (key_id, day) DO UPDATE SET
+			requests        = u.requests + CASE WHEN $3::bigint <= 0 OR u.requests < $3::bigint THEN 1 ELSE 0 END,
+			rejected        = u.rejected + CASE WHEN $3::bigint <= 0 OR u.requests < $3::bigint THEN 0 ELSE 1 END,
+			last_accepted   = ($3::bigint <= 0 OR u.requests < $3::bigint),
+			last_request_at = $4
+		RETURNING last_accepted, requests`,
+		id, domain.UsageDay(at), quota, at).Scan(&result.Allowed, &result.Used)
+
+	if sqlState(err) == "23503" {
+		// foreign_key_violation: the key does not exist
+		return domain.QuotaResult{}, domain.ErrAPIKeyNotFound
+	}
+	return result, err
+}
+
+// GetAPIKeyUsage returns the daily counters of the last days, newest first
+func (r *PostgresAPIKeyRepository) GetAPIKeyUsage(ctx context.Context, id string, days int) ([]domain.APIKeyUsage, error) {
+	if _, err := r.GetAPIKey(ctx, id); err != nil {
+		return nil, err
+	}
+
+	cutoff := domain.UsageDay(time.Now()).AddDate(0, 0, -(days - 1))
+	rows, err := r.pool.Query(ctx, `
+		SELECT day, requests, rejected FROM api_key_usage
+		WHERE key_id = $1 AND day >= $2
+		ORDER BY day DESC`,
+		id, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.APIKeyUsage, error) {
+		var usage domain.APIKeyUsage
+		err := row.Scan(&usage.Day, &usage.Requests, &usage.Rejected)
+		return usage, err
+	})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ type InMemoryRepository struct {
 	mu      sync.RWMutex
 	items   map[int]*domain.ItemPrice
 	history map[int][]domain.PriceHistory // itemID -> []PriceHistory
+	version domain.DataVersion
 }
 
 // NewInMemoryRepository creates a new in-memory repository with mock data
@@ -27,10 +29,22 @@ func NewInMemoryRepository() *InMemoryRepository {
 	// Initialize with mock data
 	repo.initializeMockData()
 	repo.initializeMockHistory()
+	now := time.Now()
+	repo.version = domain.DataVersion{Version: 1, UpdatedAt: now, ModifiedAt: now}
 
 	return repo
 }
 
+// NewEmptyInMemoryRepository creates a new in-memory repository without mock data
+func NewEmptyInMemoryRepository() *InMemoryRepository {
+	now := time.Now()
+	return &InMemoryRepository{
+		items:   make(map[int]*domain.ItemPrice),
+		history: make(map[int][]domain.PriceHistory),
+		version: domain.DataVersion{Version: 1, UpdatedAt: now, ModifiedAt: now},
+	}
+}
+
 // SavePrices saves or updates item prices
 func (r *InMemoryRepository) SavePrices(ctx context.Context, prices []domain.ItemPrice) error {
 	r.mu.Lock()
@@ -40,7 +54,12 @@ func (r *InMemoryRepository) SavePrices(ctx context.Context, prices []domain.Ite
 		// Create a copy to avoid pointer issues
 		item := price
 		r.items[price.ItemID] = &item
+
+		if item.UpdatedAt.After(r.version.UpdatedAt) {
+			r.version.UpdatedAt = item.UpdatedAt
+		}
 	}
+	r.bumpVersion()
 
 	return nil
 }
@@ -60,6 +79,21 @@ func (r *InMemoryRepository) GetItemByID(ctx context.Context, id int) (*domain.I
 	return &itemCopy, nil
 }
 
+// GetItemsByIDs retrieves the items matching ids in a single lock acquisition
+func (r *InMemoryRepository) GetItemsByIDs(ctx context.Context, ids []int) (map[int]domain.ItemPrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make(map[int]domain.ItemPrice, len(ids))
+	for _, id := range ids {
+		if item, exists := r.items[id]; exists {
+			results[id] = *item
+		}
+	}
+
+	return results, nil
+}
+
 // SearchItems searches for items by name (case-insensitive)
 func (r *InMemoryRepository) SearchItems(ctx context.Context, query string) ([]domain.ItemPrice, error) {
 	r.mu.RLock()
@@ -93,63 +127,25 @@ func (r *InMemoryRepository) GetAllItems(ctx context.Context) ([]domain.ItemPric
 	return results, nil
 }
 
-// GetAllItemsPaginated returns paginated items
+// GetAllItemsPaginated returns paginated items ordered by ID
 func (r *InMemoryRepository) GetAllItemsPaginated(ctx context.Context, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	allItems := make([]domain.ItemPrice, 0, len(r.items))
-	for _, item := range r.items {
-		itemCopy := *item
-		allItems = append(allItems, itemCopy)
-	}
-
-	total := len(allItems)
-	offset := params.Offset()
-	end := offset + params.Limit
-
-	if offset >= total {
-		return domain.PaginatedResult[domain.ItemPrice]{
-			Data:       []domain.ItemPrice{},
-			Total:      total,
-			Page:       params.Page,
-			Limit:      params.Limit,
-			TotalPages: (total + params.Limit - 1) / params.Limit,
-		}, nil
-	}
-
-	if end > total {
-		end = total
-	}
-
-	totalPages := (total + params.Limit - 1) / params.Limit
-	if totalPages == 0 {
-		totalPages = 1
-	}
-
-	return domain.PaginatedResult[domain.ItemPrice]{
-		Data:       allItems[offset:end],
-		Total:      total,
-		Page:       params.Page,
-		Limit:      params.Limit,
-		TotalPages: totalPages,
-	}, nil
+	return r.SearchItemsPaginated(ctx, domain.ItemFilter{}, params)
 }
 
-// SearchItemsPaginated returns paginated search results
-func (r *InMemoryRepository) SearchItemsPaginated(ctx context.Context, query string, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
+// SearchItemsPaginated returns paginated search results ordered by ID
+func (r *InMemoryRepository) SearchItemsPaginated(ctx context.Context, filter domain.ItemFilter, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	queryLower := strings.ToLower(query)
 	allResults := make([]domain.ItemPrice, 0)
 
 	for _, item := range r.items {
-		if strings.Contains(strings.ToLower(item.Name), queryLower) {
+		if filter.Matches(*item) {
 			itemCopy := *item
 			allResults = append(allResults, itemCopy)
 		}
 	}
+	sort.Slice(allResults, func(i, j int) bool { return allResults[i].ItemID < allResults[j].ItemID })
 
 	total := len(allResults)
 	offset := params.Offset()
@@ -186,25 +182,29 @@ func (r *InMemoryRepository) SearchItemsPaginated(ctx context.Context, query str
 // initializeMockData populates the repository with mock OSRS items
 func (r *InMemoryRepository) initializeMockData() {
 	now := time.Now()
+	// A nil catalog categorizes the mock items by their names alone
+	var catalog *domain.Catalog
 
 	// Helper function to generate realistic high/low prices and volume
 	generateItem := func(itemID int, name string, price, avg24h, avg7d int, trend domain.TrendType) domain.ItemPrice {
 		// High is typically 1-3% above average, Low is 1-3% below
 		high := int(float64(price) * (1.0 + 0.01*float64((itemID%3)+1)))
 		low := int(float64(price) * (1.0 - 0.01*float64((itemID%3)+1)))
-		// Volume varies based on price (cheaper items trade more)
-		volume := 0
+		// Volume and buy limit vary based on price (cheaper items trade more)
+		volume, buyLimit := 0, 0
 		if price < 100000 {
-			volume = 5000 + (itemID%1000)*10
+			volume, buyLimit = 5000+(itemID%1000)*10, 5000
 		} else if price < 1000000 {
-			volume = 500 + (itemID%100)*5
+			volume, buyLimit = 500+(itemID%100)*5, 100
 		} else {
-			volume = 50 + (itemID%20)*2
+			volume, buyLimit = 50+(itemID%20)*2, 8
 		}
-		return domain.ItemPrice{
+		item := domain.ItemPrice{
 			ItemID: itemID, Name: name, Price: price, High: high, Low: low,
-			Volume: volume, Avg24h: avg24h, Avg7d: avg7d, Trend: trend, UpdatedAt: now,
+			Volume: volume, Avg24h: avg24h, Avg7d: avg7d, Trend: trend, BuyLimit: buyLimit, UpdatedAt: now,
 		}
+		catalog.Apply(&item)
+		return item
 	}
 
 	mockItems := []domain.ItemPrice{
@@ -238,6 +238,11 @@ func (r *InMemoryRepository) initializeMockData() {
 		generateItem(28, "Rune Boots", 140000, 140000, 140000, domain.TrendFlat),
 		generateItem(29, "Rune Gloves", 100000, 102000, 100000, domain.TrendDown),
 		generateItem(30, "Rune Kiteshield", 50000, 49000, 50000, domain.TrendUp),
+		generateItem(31, "Nature Rune", 180, 178, 180, domain.TrendFlat),
+		generateItem(32, "Grimy Ranarr Weed", 7000, 7100, 7000, domain.TrendDown),
+		generateItem(33, "Super Combat Potion(4)", 12000, 11800, 12000, domain.TrendUp),
+		generateItem(34, "Yew Logs", 300, 300, 300, domain.TrendFlat),
+		generateItem(35, "Dragon Bones", 2500, 2450, 2500, domain.TrendUp),
 	}
 
 	for i := range mockItems {
@@ -259,6 +264,7 @@ func (r *InMemoryRepository) SavePriceHistory(ctx context.Context, itemID int, p
 	}
 
 	r.history[itemID] = append(r.history[itemID], entry)
+	r.bumpVersion()
 	return nil
 }
 
@@ -284,15 +290,112 @@ func (r *InMemoryRepository) GetPriceHistory(ctx context.Context, itemID int, da
 	}
 
 	// Sort by date ascending
-	for i := 0; i < len(result)-1; i++ {
-		for j := i + 1; j < len(result); j++ {
-			if result[i].Date.After(result[j].Date) {
-				result[i], result[j] = result[j], result[i]
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	return result, nil
+}
+
+// GetHistoricalPrices returns the latest history entry of each item dated within [from, to]
+func (r *InMemoryRepository) GetHistoricalPrices(ctx context.Context, ids []int, from, to time.Time) (map[int]domain.PriceHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make(map[int]domain.PriceHistory, len(ids))
+	for _, id := range ids {
+		for _, entry := range r.history[id] {
+			if entry.Date.Before(from) || entry.Date.After(to) {
+				continue
+			}
+			if latest, ok := results[id]; !ok || entry.Date.After(latest.Date) {
+				results[id] = entry
 			}
 		}
 	}
+	return results, nil
+}
 
-	return result, nil
+// SavePriceHistoryBatch stores entries, skipping any (item_id, date) already stored
+func (r *InMemoryRepository) SavePriceHistoryBatch(ctx context.Context, entries []domain.PriceHistory) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Index existing timestamps only for the items being imported
+	existing := make(map[int]map[int64]bool)
+	now := time.Now()
+	inserted := 0
+
+	for _, entry := range entries {
+		dates, ok := existing[entry.ItemID]
+		if !ok {
+			dates = make(map[int64]bool, len(r.history[entry.ItemID]))
+			for _, h := range r.history[entry.ItemID] {
+				dates[h.Date.UnixNano()] = true
+			}
+			existing[entry.ItemID] = dates
+		}
+
+		key := entry.Date.UnixNano()
+		if dates[key] {
+			continue
+		}
+		dates[key] = true
+
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = now
+		}
+		r.history[entry.ItemID] = append(r.history[entry.ItemID], entry)
+		inserted++
+	}
+
+	if inserted > 0 {
+		r.bumpVersion()
+	}
+	return inserted, nil
+}
+
+// ForEachItem calls fn for every item matching filter ordered by ID.
+// The lock is only held while collecting item pointers, so a slow consumer
+// does not block price updates. Saved items are never mutated in place.
+func (r *InMemoryRepository) ForEachItem(ctx context.Context, filter domain.ItemFilter, fn func(item domain.ItemPrice) error) error {
+	r.mu.RLock()
+	items := make([]*domain.ItemPrice, 0, len(r.items))
+	for _, item := range r.items {
+		if filter.Matches(*item) {
+			items = append(items, item)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ItemID < items[j].ItemID
+	})
+
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(*item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bumpVersion records a write; the caller holds r.mu
+func (r *InMemoryRepository) bumpVersion() {
+	r.version.Version++
+	r.version.ModifiedAt = time.Now()
+}
+
+// GetDataVersion returns the current version of the stored data
+func (r *InMemoryRepository) GetDataVersion(ctx context.Context) (domain.DataVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.version, nil
 }
 
 // initializeMockHistory generates mock price history for the last 7 days
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/repository")
+
+// InstrumentedRepository wraps an ItemRepository with tracing spans and latency metrics
+type InstrumentedRepository struct {
+	next domain.ItemRepository
+}
+
+// NewInstrumentedRepository creates a repository decorator that records spans and metrics
+func NewInstrumentedRepository(next domain.ItemRepository) *InstrumentedRepository {
+	return &InstrumentedRepository{next: next}
+}
+
+// startOperation starts a span for an ItemRepository operation.
+// The returned function ends the span and records the operation latency.
+func startOperation(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	return startRepositoryOperation(ctx, "ItemRepository", operation, attrs...)
+}
+
+// startRepositoryOperation starts a span named repository.operation; see startOperation
+func startRepositoryOperation(ctx context.Context, repository, operation string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, repository+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("db.operation.name", operation))...))
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.ObserveRepositoryOperation(operation, time.Since(start))
+	}
+}
+
+// SavePrices saves or updates item prices
+func (r *InstrumentedRepository) SavePrices(ctx context.Context, prices []domain.ItemPrice) error {
+	ctx, end := startOperation(ctx, "save_prices", attribute.Int("items.count", len(prices)))
+	err := r.next.SavePrices(ctx, prices)
+	end(err)
+	return err
+}
+
+// GetItemByID retrieves an item by its ID
+func (r *InstrumentedRepository) GetItemByID(ctx context.Context, id int) (*domain.ItemPrice, error) {
+	ctx, end := startOperation(ctx, "get_item_by_id", attribute.Int("item.id", id))
+	item, err := r.next.GetItemByID(ctx, id)
+	end(err)
+	return item, err
+}
+
+// GetItemsByIDs retrieves the items matching ids
+func (r *InstrumentedRepository) GetItemsByIDs(ctx context.Context, ids []int) (map[int]domain.ItemPrice, error) {
+	ctx, end := startOperation(ctx, "get_items_by_ids", attribute.Int("items.requested", len(ids)))
+	items, err := r.next.GetItemsByIDs(ctx, ids)
+	end(err)
+	return items, err
+}
+
+// SearchItems searches for items by name
+func (r *InstrumentedRepository) SearchItems(ctx context.Context, query string) ([]domain.ItemPrice, error) {
+	ctx, end := startOperation(ctx, "search_items", attribute.String("search.query", query))
+	items, err := r.next.SearchItems(ctx, query)
+	end(err)
+	return items, err
+}
+
+// GetAllItems returns all items in the repository
+func (r *InstrumentedRepository) GetAllItems(ctx context.Context) ([]domain.ItemPrice, error) {
+	ctx, end := startOperation(ctx, "get_all_items")
+	items, err := r.next.GetAllItems(ctx)
+	end(err)
+	return items, err
+}
+
+// SearchItemsPaginated returns paginated search results
+func (r *InstrumentedRepository) SearchItemsPaginated(ctx context.Context, filter domain.ItemFilter, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
+	ctx, end := startOperation(ctx, "search_items_paginated",
+		attribute.String("search.query", filter.Query),
+		attribute.String("search.category", string(filter.Category)),
+		attribute.String("search.tag", filter.Tag),
+		attribute.Int("pagination.page", params.Page),
+		attribute.Int("pagination.limit", params.Limit))
+	result, err := r.next.SearchItemsPaginated(ctx, filter, params)
+	end(err)
+	return result, err
+}
+
+// GetAllItemsPaginated returns paginated items
+func (r *InstrumentedRepository) GetAllItemsPaginated(ctx context.Context, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
+	ctx, end := startOperation(ctx, "get_all_items_paginated",
+		attribute.Int("pagination.page", params.Page),
+		attribute.Int("pagination.limit", params.Limit))
+	result, err := r.next.GetAllItemsPaginated(ctx, params)
+	end(err)
+	return result, err
+}
+
+// SavePriceHistory saves a price history entry for an item
+func (r *InstrumentedRepository) SavePriceHistory(ctx context.Context, itemID int, price int, date time.Time) error {
+	ctx, end := startOperation(ctx, "save_price_history", attribute.Int("item.id", itemID))
+	err := r.next.SavePriceHistory(ctx, itemID, price, date)
+	end(err)
+	return err
+}
+
+// GetPriceHistory retrieves price history for an item for the last N days
+func (r *InstrumentedRepository) GetPriceHistory(ctx context.Context, itemID int, days int) ([]domain.PriceHistory, error) {
+	ctx, end := startOperation(ctx, "get_price_history",
+		attribute.Int("item.id", itemID),
+		attribute.Int("history.days", days))
+	history, err := r.next.GetPriceHistory(ctx, itemID, days)
+	end(err)
+	return history, err
+}
+
+// GetHistoricalPrices returns the latest history entry of each item dated within [from, to]
+func (r *InstrumentedRepository) GetHistoricalPrices(ctx context.Context, ids []int, from, to time.Time) (map[int]domain.PriceHistory, error) {
+	ctx, end := startOperation(ctx, "get_historical_prices", attribute.Int("items.requested", len(ids)))
+	prices, err := r.next.GetHistoricalPrices(ctx, ids, from, to)
+	end(err)
+	return prices, err
+}
+
+// SavePriceHistoryBatch stores entries, skipping any (item_id, date) already stored
+func (r *InstrumentedRepository) SavePriceHistoryBatch(ctx context.Context, entries []domain.PriceHistory) (int, error) {
+	ctx, end := startOperation(ctx, "save_price_history_batch", attribute.Int("history.entries", len(entries)))
+	inserted, err := r.next.SavePriceHistoryBatch(ctx, entries)
+	end(err)
+	return inserted, err
+}
+
+// GetDataVersion returns the current version of the stored data
+func (r *InstrumentedRepository) GetDataVersion(ctx context.Context) (domain.DataVersion, error) {
+	ctx, end := startOperation(ctx, "get_data_version")
+	version, err := r.next.GetDataVersion(ctx)
+	end(err)
+	return version, err
+}
+
+// ForEachItem calls fn for every item matching filter ordered by ID
+func (r *InstrumentedRepository) ForEachItem(ctx context.Context, filter domain.ItemFilter, fn func(item domain.ItemPrice) error) error {
+	ctx, end := startOperation(ctx, "for_each_item")
+	err := r.next.ForEachItem(ctx, filter, fn)
+	end(err)
+	return err
+}
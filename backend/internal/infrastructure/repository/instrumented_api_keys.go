@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InstrumentedAPIKeyRepository wraps an APIKeyRepository with tracing spans and latency metrics
+type InstrumentedAPIKeyRepository struct {
+	next domain.APIKeyRepository
+}
+
+// NewInstrumentedAPIKeyRepository creates an API key repository decorator that records spans and metrics
+func NewInstrumentedAPIKeyRepository(next domain.APIKeyRepository) *InstrumentedAPIKeyRepository {
+	return &InstrumentedAPIKeyRepository{next: next}
+}
+
+// startAPIKeyOperation starts a span for an APIKeyRepository operation
+func startAPIKeyOperation(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	return startRepositoryOperation(ctx, "APIKeyRepository", operation, attrs...)
+}
+
+// CreateAPIKey stores a new key
+func (r *InstrumentedAPIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey) error {
+	ctx, end := startAPIKeyOperation(ctx, "create_api_key", attribute.String("api_key.id", key.ID))
+	err := r.next.CreateAPIKey(ctx, key)
+	end(err)
+	return err
+}
+
+// GetAPIKey retrieves a key by its ID
+func (r *InstrumentedAPIKeyRepository) GetAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	ctx, end := startAPIKeyOperation(ctx, "get_api_key", attribute.String("api_key.id", id))
+	key, err := r.next.GetAPIKey(ctx, id)
+	end(err)
+	return key, err
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of its secret
+func (r *InstrumentedAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	ctx, end := startAPIKeyOperation(ctx, "get_api_key_by_hash")
+	key, err := r.next.GetAPIKeyByHash(ctx, hash)
+	end(err)
+	return key, err
+}
+
+// ListAPIKeys returns every key
+func (r *InstrumentedAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	ctx, end := startAPIKeyOperation(ctx, "list_api_keys")
+	keys, err := r.next.ListAPIKeys(ctx)
+	end(err)
+	return keys, err
+}
+
+// UpdateAPIKey changes the name and limits of a key
+func (r *InstrumentedAPIKeyRepository) UpdateAPIKey(ctx context.Context, key domain.APIKey) error {
+	ctx, end := startAPIKeyOperation(ctx, "update_api_key", attribute.String("api_key.id", key.ID))
+	err := r.next.UpdateAPIKey(ctx, key)
+	end(err)
+	return err
+}
+
+// RevokeAPIKey marks a key as revoked
+func (r *InstrumentedAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	ctx, end := startAPIKeyOperation(ctx, "revoke_api_key", attribute.String("api_key.id", id))
+	err := r.next.RevokeAPIKey(ctx, id, at)
+	end(err)
+	return err
+}
+
+// ConsumeAPIKeyQuota counts one request against the key's daily quota
+func (r *InstrumentedAPIKeyRepository) ConsumeAPIKeyQuota(ctx context.Context, id string, at time.Time, quota int) (domain.QuotaResult, error) {
+	ctx, end := startAPIKeyOperation(ctx, "consume_api_key_quota", attribute.String("api_key.id", id))
+	result, err := r.next.ConsumeAPIKeyQuota(ctx, id, at, quota)
+	end(err)
+	return result, err
+}
+
+// GetAPIKeyUsage returns the daily counters of the last days
+func (r *InstrumentedAPIKeyRepository) GetAPIKeyUsage(ctx context.Context, id string, days int) ([]domain.APIKeyUsage, error) {
+	ctx, end := startAPIKeyOperation(ctx, "get_api_key_usage", attribute.String("api_key.id", id), attribute.Int("days", days))
+	usage, err := r.next.GetAPIKeyUsage(ctx, id, days)
+	end(err)
+	return usage, err
+}
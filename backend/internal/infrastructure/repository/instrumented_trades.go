@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InstrumentedTradeRepository wraps a TradeRepository with tracing spans and latency metrics
+type InstrumentedTradeRepository struct {
+	next domain.TradeRepository
+}
+
+// NewInstrumentedTradeRepository creates a trade repository decorator that records spans and metrics
+func NewInstrumentedTradeRepository(next domain.TradeRepository) *InstrumentedTradeRepository {
+	return &InstrumentedTradeRepository{next: next}
+}
+
+// CreateTrades stores new trades
+func (r *InstrumentedTradeRepository) CreateTrades(ctx context.Context, trades []domain.Trade) error {
+	ctx, end := startRepositoryOperation(ctx, "TradeRepository", "create_trades",
+		attribute.Int("trades.count", len(trades)))
+	err := r.next.CreateTrades(ctx, trades)
+	end(err)
+	return err
+}
+
+// ListTrades returns the user's trades matching filter
+func (r *InstrumentedTradeRepository) ListTrades(ctx context.Context, userID string, filter domain.TradeFilter) ([]domain.Trade, error) {
+	ctx, end := startRepositoryOperation(ctx, "TradeRepository", "list_trades",
+		attribute.String("user.id", userID), attribute.Int("item.id", filter.ItemID))
+	trades, err := r.next.ListTrades(ctx, userID, filter)
+	end(err)
+	return trades, err
+}
+
+// DeleteTrade removes one of the user's trades
+func (r *InstrumentedTradeRepository) DeleteTrade(ctx context.Context, userID, id string) error {
+	ctx, end := startRepositoryOperation(ctx, "TradeRepository", "delete_trade",
+		attribute.String("user.id", userID), attribute.String("trade.id", id))
+	err := r.next.DeleteTrade(ctx, userID, id)
+	end(err)
+	return err
+}
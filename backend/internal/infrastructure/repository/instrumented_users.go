@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InstrumentedUserRepository wraps a UserRepository with tracing spans and latency metrics
+type InstrumentedUserRepository struct {
+	next domain.UserRepository
+}
+
+// NewInstrumentedUserRepository creates a user repository decorator that records spans and metrics
+func NewInstrumentedUserRepository(next domain.UserRepository) *InstrumentedUserRepository {
+	return &InstrumentedUserRepository{next: next}
+}
+
+// startUserOperation starts a span for a UserRepository operation
+func startUserOperation(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	return startRepositoryOperation(ctx, "UserRepository", operation, attrs...)
+}
+
+// CreateUser stores a new user
+func (r *InstrumentedUserRepository) CreateUser(ctx context.Context, user domain.User) error {
+	ctx, end := startUserOperation(ctx, "create_user", attribute.String("user.id", user.ID))
+	err := r.next.CreateUser(ctx, user)
+	end(err)
+	return err
+}
+
+// GetUserByID retrieves a user by ID
+func (r *InstrumentedUserRepository) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	ctx, end := startUserOperation(ctx, "get_user_by_id", attribute.String("user.id", id))
+	user, err := r.next.GetUserByID(ctx, id)
+	end(err)
+	return user, err
+}
+
+// GetUserByEmail retrieves a user by normalized email
+func (r *InstrumentedUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ctx, end := startUserOperation(ctx, "get_user_by_email")
+	user, err := r.next.GetUserByEmail(ctx, email)
+	end(err)
+	return user, err
+}
+
+// SaveRefreshToken stores a new refresh token
+func (r *InstrumentedUserRepository) SaveRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	ctx, end := startUserOperation(ctx, "save_refresh_token", attribute.String("user.id", token.UserID))
+	err := r.next.SaveRefreshToken(ctx, token)
+	end(err)
+	return err
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by the hash of its secret
+func (r *InstrumentedUserRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	ctx, end := startUserOperation(ctx, "get_refresh_token_by_hash")
+	token, err := r.next.GetRefreshTokenByHash(ctx, hash)
+	end(err)
+	return token, err
+}
+
+// RotateRefreshToken revokes the old token and stores its successor
+func (r *InstrumentedUserRepository) RotateRefreshToken(ctx context.Context, oldID string, next domain.RefreshToken, at time.Time) error {
+	ctx, end := startUserOperation(ctx, "rotate_refresh_token", attribute.String("user.id", next.UserID))
+	err := r.next.RotateRefreshToken(ctx, oldID, next, at)
+	end(err)
+	return err
+}
+
+// RevokeRefreshToken revokes one refresh token
+func (r *InstrumentedUserRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	ctx, end := startUserOperation(ctx, "revoke_refresh_token")
+	err := r.next.RevokeRefreshToken(ctx, id, at)
+	end(err)
+	return err
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user
+func (r *InstrumentedUserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	ctx, end := startUserOperation(ctx, "revoke_user_refresh_tokens", attribute.String("user.id", userID))
+	err := r.next.RevokeUserRefreshTokens(ctx, userID, at)
+	end(err)
+	return err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InstrumentedWatchlistRepository wraps a WatchlistRepository with tracing spans and latency metrics
+type InstrumentedWatchlistRepository struct {
+	next domain.WatchlistRepository
+}
+
+// NewInstrumentedWatchlistRepository creates a watchlist repository decorator that records spans and metrics
+func NewInstrumentedWatchlistRepository(next domain.WatchlistRepository) *InstrumentedWatchlistRepository {
+	return &InstrumentedWatchlistRepository{next: next}
+}
+
+// startWatchlistOperation starts a span for a WatchlistRepository operation
+func startWatchlistOperation(ctx context.Context, operation, userID, id string) (context.Context, func(error)) {
+	attrs := []attribute.KeyValue{attribute.String("user.id", userID)}
+	if id != "" {
+		attrs = append(attrs, attribute.String("watchlist.id", id))
+	}
+	return startRepositoryOperation(ctx, "WatchlistRepository", operation, attrs...)
+}
+
+// CreateWatchlist stores a new watchlist unless the user has max of them
+func (r *InstrumentedWatchlistRepository) CreateWatchlist(ctx context.Context, watchlist domain.Watchlist, max int) error {
+	ctx, end := startWatchlistOperation(ctx, "create_watchlist", watchlist.UserID, watchlist.ID)
+	err := r.next.CreateWatchlist(ctx, watchlist, max)
+	end(err)
+	return err
+}
+
+// GetWatchlist retrieves one of the user's watchlists
+func (r *InstrumentedWatchlistRepository) GetWatchlist(ctx context.Context, userID, id string) (*domain.Watchlist, error) {
+	ctx, end := startWatchlistOperation(ctx, "get_watchlist", userID, id)
+	watchlist, err := r.next.GetWatchlist(ctx, userID, id)
+	end(err)
+	return watchlist, err
+}
+
+// ListWatchlists returns the user's watchlists
+func (r *InstrumentedWatchlistRepository) ListWatchlists(ctx context.Context, userID string) ([]domain.Watchlist, error) {
+	ctx, end := startWatchlistOperation(ctx, "list_watchlists", userID, "")
+	watchlists, err := r.next.ListWatchlists(ctx, userID)
+	end(err)
+	return watchlists, err
+}
+
+// RenameWatchlist changes the name of a watchlist
+func (r *InstrumentedWatchlistRepository) RenameWatchlist(ctx context.Context, userID, id, name string, at time.Time) (*domain.Watchlist, error) {
+	ctx, end := startWatchlistOperation(ctx, "rename_watchlist", userID, id)
+	watchlist, err := r.next.RenameWatchlist(ctx, userID, id, name, at)
+	end(err)
+	return watchlist, err
+}
+
+// DeleteWatchlist removes a watchlist
+func (r *InstrumentedWatchlistRepository) DeleteWatchlist(ctx context.Context, userID, id string) error {
+	ctx, end := startWatchlistOperation(ctx, "delete_watchlist", userID, id)
+	err := r.next.DeleteWatchlist(ctx, userID, id)
+	end(err)
+	return err
+}
+
+// AddWatchlistItems appends items not yet in the watchlist
+func (r *InstrumentedWatchlistRepository) AddWatchlistItems(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*domain.Watchlist, error) {
+	ctx, end := startWatchlistOperation(ctx, "add_watchlist_items", userID, id)
+	watchlist, err := r.next.AddWatchlistItems(ctx, userID, id, itemIDs, at)
+	end(err)
+	return watchlist, err
+}
+
+// RemoveWatchlistItem removes one item from the watchlist
+func (r *InstrumentedWatchlistRepository) RemoveWatchlistItem(ctx context.Context, userID, id string, itemID int, at time.Time) (*domain.Watchlist, error) {
+	ctx, end := startWatchlistOperation(ctx, "remove_watchlist_item", userID, id)
+	watchlist, err := r.next.RemoveWatchlistItem(ctx, userID, id, itemID, at)
+	end(err)
+	return watchlist, err
+}
+
+// ReorderWatchlist replaces the order of the watchlist's items
+func (r *InstrumentedWatchlistRepository) ReorderWatchlist(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*domain.Watchlist, error) {
+	ctx, end := startWatchlistOperation(ctx, "reorder_watchlist", userID, id)
+	watchlist, err := r.next.ReorderWatchlist(ctx, userID, id, itemIDs, at)
+	end(err)
+	return watchlist, err
+}
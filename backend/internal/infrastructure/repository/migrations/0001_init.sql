@@ -0,0 +1,31 @@
+-- Current item prices, one row per item
+CREATE TABLE items (
+    item_id    INTEGER     PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    price      BIGINT      NOT NULL,
+    high       BIGINT      NOT NULL,
+    low        BIGINT      NOT NULL,
+    volume     BIGINT      NOT NULL,
+    avg_24h    BIGINT      NOT NULL,
+    avg_7d     BIGINT      NOT NULL,
+    trend      TEXT        NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Price observations, unique per (item_id, date) so imports and backfills can be re-run
+CREATE TABLE price_history (
+    item_id    INTEGER     NOT NULL,
+    date       TIMESTAMPTZ NOT NULL,
+    price      BIGINT      NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (item_id, date)
+);
+
+-- Single-row counter bumped on every write, used for ETags across replicas
+CREATE TABLE data_version (
+    id         BOOLEAN     PRIMARY KEY DEFAULT TRUE CHECK (id),
+    version    BIGINT      NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO data_version (version, updated_at) VALUES (1, now());
@@ -0,0 +1,23 @@
+-- API keys; only the SHA-256 of the secret is stored
+CREATE TABLE api_keys (
+    id          TEXT        PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    prefix      TEXT        NOT NULL,
+    key_hash    TEXT        NOT NULL UNIQUE,
+    rate_limit  INTEGER     NOT NULL DEFAULT 0,
+    daily_quota INTEGER     NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ
+);
+
+-- Requests per key and UTC day. last_accepted records whether the latest
+-- request fit in the quota, so a single upsert can both count and decide.
+CREATE TABLE api_key_usage (
+    key_id          TEXT        NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+    day             DATE        NOT NULL,
+    requests        BIGINT      NOT NULL DEFAULT 0,
+    rejected        BIGINT      NOT NULL DEFAULT 0,
+    last_accepted   BOOLEAN     NOT NULL DEFAULT TRUE,
+    last_request_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key_id, day)
+);
@@ -0,0 +1,21 @@
+-- User accounts; emails are stored lowercased
+CREATE TABLE users (
+    id            TEXT        PRIMARY KEY,
+    email         TEXT        NOT NULL UNIQUE,
+    password_hash TEXT        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL
+);
+
+-- Refresh tokens, stored as SHA-256 hashes. replaced_by links a rotated
+-- token to its successor so reuse of an old token can be detected.
+CREATE TABLE refresh_tokens (
+    id          TEXT        PRIMARY KEY,
+    user_id     TEXT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash  TEXT        NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ,
+    replaced_by TEXT
+);
+
+CREATE INDEX refresh_tokens_user_id ON refresh_tokens (user_id);
@@ -0,0 +1,18 @@
+-- Named item lists of each user
+CREATE TABLE watchlists (
+    id         TEXT        PRIMARY KEY,
+    user_id    TEXT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX watchlists_user_id ON watchlists (user_id);
+
+-- Watchlist members; position is the user's order, starting at 0
+CREATE TABLE watchlist_items (
+    watchlist_id TEXT    NOT NULL REFERENCES watchlists (id) ON DELETE CASCADE,
+    item_id      INTEGER NOT NULL,
+    position     INTEGER NOT NULL,
+    PRIMARY KEY (watchlist_id, item_id)
+);
@@ -0,0 +1,13 @@
+-- Trade journal: Grand Exchange offers filled by each user
+CREATE TABLE trades (
+    id         TEXT        PRIMARY KEY,
+    user_id    TEXT        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    item_id    INTEGER     NOT NULL,
+    side       TEXT        NOT NULL CHECK (side IN ('buy', 'sell')),
+    quantity   INTEGER     NOT NULL CHECK (quantity > 0),
+    price      BIGINT      NOT NULL CHECK (price > 0),
+    traded_at  TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX trades_user_traded_at ON trades (user_id, traded_at);
@@ -0,0 +1,2 @@
+-- GE buy limit per 4 hours from the item mapping; 0 when unknown
+ALTER TABLE items ADD COLUMN buy_limit INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Suspicious movements flagged by the anomaly detector, and the rolling
+-- statistics it compares each update with
+ALTER TABLE items ADD COLUMN anomalies JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE items ADD COLUMN anomaly_stats JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+-- Category and tags assigned to each item by the catalog on every update
+ALTER TABLE items ADD COLUMN category TEXT NOT NULL DEFAULT 'other';
+ALTER TABLE items ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX items_category ON items (category);
@@ -0,0 +1,3 @@
+-- Time of the latest write of any kind, price history included; updated_at
+-- stays the newest item price time, which readiness checks for freshness
+ALTER TABLE data_version ADD COLUMN modified_at TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errItemNotFound matches the error returned by InMemoryRepository
+var errItemNotFound = errors.New("item not found")
+
+// sqlState returns the SQLSTATE code of a PostgreSQL error, or ""
+func sqlState(err error) string {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState()
+	}
+	return ""
+}
+
+// itemColumns is the column list scanned by scanItem
+const itemColumns = `item_id, name, price, high, low, volume, avg_24h, avg_7d, trend, updated_at, buy_limit,
+	anomalies, anomaly_stats, category, tags`
+
+// PostgresRepository implements ItemRepository on PostgreSQL, so several
+// API replicas and a separate worker can share the same data
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository connects to databaseURL and checks the connection
+func NewPostgresRepository(ctx context.Context, databaseURL string) (*PostgresRepository, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return &PostgresRepository{pool: pool}, nil
+}
+
+// Pool returns the connection pool, shared with the leader elector
+func (r *PostgresRepository) Pool() *pgxpool.Pool {
+	return r.pool
+}
+
+// Close closes every connection in the pool
+func (r *PostgresRepository) Close() {
+	r.pool.Close()
+}
+
+// bumpVersion increments the data version inside tx. updatedAt may be nil
+// when the write does not change item prices.
+func bumpVersion(ctx context.Context, tx pgx.Tx, updatedAt *time.Time) error {
+	// GREATEST ignores NULL, so a nil updatedAt keeps the current value
+	_, err := tx.Exec(ctx,
+		`UPDATE data_version SET version = version + 1, updated_at = GREATEST(updated_at, $1::timestamptz),
+			modified_at = now()`,
+		updatedAt)
+	return err
+}
+
+// SavePrices saves or updates item prices in a single statement
+func (r *PostgresRepository) SavePrices(ctx context.Context, prices []domain.ItemPrice) error {
+	n := len(prices)
+	var (
+		ids       = make([]int, n)
+		names     = make([]string, n)
+		price     = make([]int, n)
+		high      = make([]int, n)
+		low       = make([]int, n)
+		volume    = make([]int, n)
+		avg24h    = make([]int, n)
+		avg7d     = make([]int, n)
+		trends    = make([]string, n)
+		updatedAt = make([]time.Time, n)
+		buyLimits = make([]int, n)
+		anomalies = make([]string, n)
+		stats     = make([]string, n)
+		category  = make([]string, n)
+		tags      = make([]string, n)
+		newest    time.Time
+		err       error
+	)
+	for i, p := range prices {
+		ids[i], names[i], price[i], high[i], low[i] = p.ItemID, p.Name, p.Price, p.High, p.Low
+		volume[i], avg24h[i], avg7d[i], trends[i], updatedAt[i] = p.Volume, p.Avg24h, p.Avg7d, string(p.Trend), p.UpdatedAt
+		buyLimits[i] = p.BuyLimit
+		if anomalies[i], err = jsonText(p.Anomalies); err != nil {
+			return err
+		}
+		if stats[i], err = jsonText(p.AnomalyStats); err != nil {
+			return err
+		}
+		category[i] = string(p.Category)
+		if category[i] == "" {
+			category[i] = string(domain.CategoryOther)
+		}
+		if tags[i], err = jsonText(p.Tags); err != nil {
+			return err
+		}
+		if p.UpdatedAt.After(newest) {
+			newest = p.UpdatedAt
+		}
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO items (`+itemColumns+`)
+			SELECT * FROM unnest($1::int[], $2::text[], $3::bigint[], $4::bigint[], $5::bigint[],
+				$6::bigint[], $7::bigint[], $8::bigint[], $9::text[], $10::timestamptz[], $11::int[],
+				$12::jsonb[], $13::jsonb[], $14::text[], $15::jsonb[])
+			ON CONFLICT (item_id) DO UPDATE SET
+				name = EXCLUDED.name, price = EXCLUDED.price, high = EXCLUDED.high, low = EXCLUDED.low,
+				volume = EXCLUDED.volume, avg_24h = EXCLUDED.avg_24h, avg_7d = EXCLUDED.avg_7d,
+				trend = EXCLUDED.trend, updated_at = EXCLUDED.updated_at, buy_limit = EXCLUDED.buy_limit,
+				anomalies = EXCLUDED.anomalies, anomaly_stats = EXCLUDED.anomaly_stats,
+				category = EXCLUDED.category, tags = EXCLUDED.tags`,
+			ids, names, price, high, low, volume, avg24h, avg7d, trends, updatedAt, buyLimits, anomalies, stats,
+			category, tags)
+		if err != nil {
+			return err
+		}
+
+		var versionTime *time.Time
+		if !newest.IsZero() {
+			versionTime = &newest
+		}
+		return bumpVersion(ctx, tx, versionTime)
+	})
+}
+
+// jsonText encodes v for a jsonb array parameter; nil slices become []
+func jsonText(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if string(data) == "null" {
+		return "[]", nil
+	}
+	return string(data), nil
+}
+
+// scanItem scans a row selected with itemColumns
+func scanItem(row pgx.CollectableRow) (domain.ItemPrice, error) {
+	var item domain.ItemPrice
+	var trend, category string
+	err := row.Scan(&item.ItemID, &item.Name, &item.Price, &item.High, &item.Low,
+		&item.Volume, &item.Avg24h, &item.Avg7d, &trend, &item.UpdatedAt, &item.BuyLimit,
+		&item.Anomalies, &item.AnomalyStats, &category, &item.Tags)
+	item.Trend = domain.TrendType(trend)
+	item.Category = domain.Category(category)
+	return item, err
+}
+
+// queryItems runs an item query and collects the rows
+func (r *PostgresRepository) queryItems(ctx context.Context, sql string, args ...any) ([]domain.ItemPrice, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanItem)
+}
+
+// GetItemByID retrieves an item by its ID
+func (r *PostgresRepository) GetItemByID(ctx context.Context, id int) (*domain.ItemPrice, error) {
+	items, err := r.queryItems(ctx, `SELECT `+itemColumns+` FROM items WHERE item_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errItemNotFound
+	}
+	return &items[0], nil
+}
+
+// GetItemsByIDs retrieves the items matching ids in a single query
+func (r *PostgresRepository) GetItemsByIDs(ctx context.Context, ids []int) (map[int]domain.ItemPrice, error) {
+	items, err := r.queryItems(ctx, `SELECT `+itemColumns+` FROM items WHERE item_id = ANY($1::int[])`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]domain.ItemPrice, len(items))
+	for _, item := range items {
+		results[item.ItemID] = item
+	}
+	return results, nil
+}
+
+// likePattern builds a case-insensitive contains pattern, escaping LIKE wildcards
+func likePattern(query string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+	return "%" + escaped + "%"
+}
+
+// SearchItems searches for items by name (case-insensitive)
+func (r *PostgresRepository) SearchItems(ctx context.Context, query string) ([]domain.ItemPrice, error) {
+	return r.queryItems(ctx,
+		`SELECT `+itemColumns+` FROM items WHERE name ILIKE $1 ORDER BY item_id`, likePattern(query))
+}
+
+// GetAllItems returns all items in the repository
+func (r *PostgresRepository) GetAllItems(ctx context.Context) ([]domain.ItemPrice, error) {
+	return r.queryItems(ctx, `SELECT `+itemColumns+` FROM items ORDER BY item_id`)
+}
+
+// GetAllItemsPaginated returns paginated items ordered by ID
+func (r *PostgresRepository) GetAllItemsPaginated(ctx context.Context, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
+	return r.SearchItemsPaginated(ctx, domain.ItemFilter{}, params)
+}
+
+// itemFilterWhere matches the filter parameters $1 to $3 of itemFilterArgs
+const itemFilterWhere = `name ILIKE $1 AND ($2 = '' OR category = $2)
+	AND ($3 = '' OR tags @> jsonb_build_array(lower($3)))`
+
+// itemFilterArgs returns the parameters of itemFilterWhere
+func itemFilterArgs(filter domain.ItemFilter) []any {
+	return []any{likePattern(filter.Query), string(filter.Category), filter.Tag}
+}
+
+// SearchItemsPaginated returns paginated search results ordered by ID
+func (r *PostgresRepository) SearchItemsPaginated(ctx context.Context, filter domain.ItemFilter, params domain.PaginationParams) (domain.PaginatedResult[domain.ItemPrice], error) {
+	args := itemFilterArgs(filter)
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT count(*) FROM items WHERE `+itemFilterWhere, args...).Scan(&total); err != nil {
+		return domain.PaginatedResult[domain.ItemPrice]{}, err
+	}
+
+	items, err := r.queryItems(ctx,
+		`SELECT `+itemColumns+` FROM items WHERE `+itemFilterWhere+` ORDER BY item_id LIMIT $4 OFFSET $5`,
+		append(args, params.Limit, params.Offset())...)
+	if err != nil {
+		return domain.PaginatedResult[domain.ItemPrice]{}, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return domain.PaginatedResult[domain.ItemPrice]{
+		Data:       items,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// SavePriceHistory saves a price history entry for an item,
+// replacing the price if the item already has an entry at date
+func (r *PostgresRepository) SavePriceHistory(ctx context.Context, itemID int, price int, date time.Time) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO price_history (item_id, date, price) VALUES ($1, $2, $3)
+			ON CONFLICT (item_id, date) DO UPDATE SET price = EXCLUDED.price`,
+			itemID, date, price)
+		if err != nil {
+			return err
+		}
+		return bumpVersion(ctx, tx, nil)
+	})
+}
+
+// GetPriceHistory retrieves price history for an item for the last N days
+func (r *PostgresRepository) GetPriceHistory(ctx context.Context, itemID int, days int) ([]domain.PriceHistory, error) {
+	cutoffDate := time.Now().AddDate(0, 0, -days)
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT item_id, price, date, created_at FROM price_history
+		WHERE item_id = $1 AND date >= $2
+		ORDER BY date`,
+		itemID, cutoffDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PriceHistory, error) {
+		var entry domain.PriceHistory
+		err := row.Scan(&entry.ItemID, &entry.Price, &entry.Date, &entry.CreatedAt)
+		return entry, err
+	})
+}
+
+// GetHistoricalPrices returns the latest history entry of each item dated within [from, to]
+func (r *PostgresRepository) GetHistoricalPrices(ctx context.Context, ids []int, from, to time.Time) (map[int]domain.PriceHistory, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT DISTINCT ON (item_id) item_id, price, date, created_at FROM price_history
+		WHERE item_id = ANY($1::int[]) AND date BETWEEN $2 AND $3
+		ORDER BY item_id, date DESC`,
+		ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PriceHistory, error) {
+		var entry domain.PriceHistory
+		err := row.Scan(&entry.ItemID, &entry.Price, &entry.Date, &entry.CreatedAt)
+		return entry, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]domain.PriceHistory, len(entries))
+	for _, entry := range entries {
+		results[entry.ItemID] = entry
+	}
+	return results, nil
+}
+
+// SavePriceHistoryBatch stores entries, skipping any (item_id, date) already stored
+func (r *PostgresRepository) SavePriceHistoryBatch(ctx context.Context, entries []domain.PriceHistory) (int, error) {
+	now := time.Now()
+	ids := make([]int, len(entries))
+	dates := make([]time.Time, len(entries))
+	prices := make([]int, len(entries))
+	createdAt := make([]time.Time, len(entries))
+	for i, e := range entries {
+		ids[i], dates[i], prices[i], createdAt[i] = e.ItemID, e.Date, e.Price, e.CreatedAt
+		if e.CreatedAt.IsZero() {
+			createdAt[i] = now
+		}
+	}
+
+	inserted := 0
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO price_history (item_id, date, price, created_at)
+			SELECT * FROM unnest($1::int[], $2::timestamptz[], $3::bigint[], $4::timestamptz[])
+			ON CONFLICT (item_id, date) DO NOTHING`,
+			ids, dates, prices, createdAt)
+		if err != nil {
+			return err
+		}
+
+		inserted = int(tag.RowsAffected())
+		if inserted == 0 {
+			return nil
+		}
+		return bumpVersion(ctx, tx, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// ForEachItem calls fn for every item matching filter ordered by ID while
+// streaming rows from the database. fn runs while a pooled connection is held.
+func (r *PostgresRepository) ForEachItem(ctx context.Context, filter domain.ItemFilter, fn func(item domain.ItemPrice) error) error {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+itemColumns+` FROM items WHERE `+itemFilterWhere+` ORDER BY item_id`, itemFilterArgs(filter)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetDataVersion returns the current version of the stored data
+func (r *PostgresRepository) GetDataVersion(ctx context.Context) (domain.DataVersion, error) {
+	var version domain.DataVersion
+	err := r.pool.QueryRow(ctx, `SELECT version, updated_at, modified_at FROM data_version`).Scan(&version.Version, &version.UpdatedAt, &version.ModifiedAt)
+	return version, err
+}
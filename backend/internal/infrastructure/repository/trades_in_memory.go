@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// InMemoryTradeRepository implements TradeRepository in memory.
+// Trades are lost on restart, so it only suits development and single instances.
+type InMemoryTradeRepository struct {
+	mu     sync.Mutex
+	trades map[string]domain.Trade
+}
+
+// NewInMemoryTradeRepository creates an empty in-memory trade repository
+func NewInMemoryTradeRepository() *InMemoryTradeRepository {
+	return &InMemoryTradeRepository{
+		trades: make(map[string]domain.Trade),
+	}
+}
+
+// CreateTrades stores new trades, all or none
+func (r *InMemoryTradeRepository) CreateTrades(ctx context.Context, trades []domain.Trade) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, trade := range trades {
+		if _, exists := r.trades[trade.ID]; exists {
+			return errors.New("trade id already exists")
+		}
+	}
+	for _, trade := range trades {
+		r.trades[trade.ID] = trade
+	}
+	return nil
+}
+
+// ListTrades returns the user's trades matching filter, oldest first
+func (r *InMemoryTradeRepository) ListTrades(ctx context.Context, userID string, filter domain.TradeFilter) ([]domain.Trade, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trades := make([]domain.Trade, 0)
+	for _, trade := range r.trades {
+		if trade.UserID == userID && filter.Matches(trade) {
+			trades = append(trades, trade)
+		}
+	}
+	sort.Slice(trades, func(i, j int) bool {
+		if !trades[i].TradedAt.Equal(trades[j].TradedAt) {
+			return trades[i].TradedAt.Before(trades[j].TradedAt)
+		}
+		return trades[i].ID < trades[j].ID
+	})
+	return trades, nil
+}
+
+// DeleteTrade removes one of the user's trades
+func (r *InMemoryTradeRepository) DeleteTrade(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trade, exists := r.trades[id]
+	if !exists || trade.UserID != userID {
+		return domain.ErrTradeNotFound
+	}
+	delete(r.trades, id)
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresTradeRepository implements TradeRepository on PostgreSQL
+type PostgresTradeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresTradeRepository creates a trade repository on pool
+func NewPostgresTradeRepository(pool *pgxpool.Pool) *PostgresTradeRepository {
+	return &PostgresTradeRepository{pool: pool}
+}
+
+// CreateTrades stores new trades in one statement, all or none
+func (r *PostgresTradeRepository) CreateTrades(ctx context.Context, trades []domain.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	n := len(trades)
+	ids, userIDs, sides := make([]string, n), make([]string, n), make([]string, n)
+	itemIDs, quantities := make([]int, n), make([]int, n)
+	prices := make([]int64, n)
+	tradedAt, createdAt := make([]time.Time, n), make([]time.Time, n)
+	for i, trade := range trades {
+		ids[i], userIDs[i], sides[i] = trade.ID, trade.UserID, string(trade.Side)
+		itemIDs[i], quantities[i] = trade.ItemID, trade.Quantity
+		prices[i] = int64(trade.Price)
+		tradedAt[i], createdAt[i] = trade.TradedAt, trade.CreatedAt
+	}
+
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO trades (id, user_id, item_id, side, quantity, price, traded_at, created_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::int[], $4::text[], $5::int[], $6::bigint[],
+			$7::timestamptz[], $8::timestamptz[])`,
+		ids, userIDs, itemIDs, sides, quantities, prices, tradedAt, createdAt)
+	return err
+}
+
+// ListTrades returns the user's trades matching filter, oldest first
+func (r *PostgresTradeRepository) ListTrades(ctx context.Context, userID string, filter domain.TradeFilter) ([]domain.Trade, error) {
+	conditions := []string{"user_id = $1"}
+	args := []any{userID}
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ItemID != 0 {
+		where("item_id = $%d", filter.ItemID)
+	}
+	if !filter.From.IsZero() {
+		where("traded_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("traded_at < $%d", filter.To)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, user_id, item_id, side, quantity, price, traded_at, created_at
+		FROM trades WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY traded_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Trade, error) {
+		var trade domain.Trade
+		err := row.Scan(&trade.ID, &trade.UserID, &trade.ItemID, &trade.Side, &trade.Quantity, &trade.Price,
+			&trade.TradedAt, &trade.CreatedAt)
+		return trade, err
+	})
+}
+
+// DeleteTrade removes one of the user's trades
+func (r *PostgresTradeRepository) DeleteTrade(ctx context.Context, userID, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM trades WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTradeNotFound
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// InMemoryUserRepository implements UserRepository in memory.
+// Accounts are lost on restart, so it only suits development and single instances.
+type InMemoryUserRepository struct {
+	mu           sync.Mutex
+	users        map[string]*domain.User
+	byEmail      map[string]string // email -> user id
+	tokens       map[string]*domain.RefreshToken
+	tokensByHash map[string]string // hash -> token id
+}
+
+// NewInMemoryUserRepository creates an empty in-memory user repository
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		users:        make(map[string]*domain.User),
+		byEmail:      make(map[string]string),
+		tokens:       make(map[string]*domain.RefreshToken),
+		tokensByHash: make(map[string]string),
+	}
+}
+
+// CreateUser stores a new user
+func (r *InMemoryUserRepository) CreateUser(ctx context.Context, user domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byEmail[user.Email]; exists {
+		return domain.ErrEmailTaken
+	}
+	r.users[user.ID] = &user
+	r.byEmail[user.Email] = user.ID
+	return nil
+}
+
+// GetUserByID retrieves a user by ID
+func (r *InMemoryUserRepository) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[id]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+	userCopy := *user
+	return &userCopy, nil
+}
+
+// GetUserByEmail retrieves a user by normalized email
+func (r *InMemoryUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, exists := r.byEmail[email]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+	userCopy := *r.users[id]
+	return &userCopy, nil
+}
+
+// SaveRefreshToken stores a new refresh token
+func (r *InMemoryUserRepository) SaveRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.saveTokenLocked(token)
+	return nil
+}
+
+// saveTokenLocked stores token; r.mu must be held
+func (r *InMemoryUserRepository) saveTokenLocked(token domain.RefreshToken) {
+	r.tokens[token.ID] = &token
+	r.tokensByHash[token.Hash] = token.ID
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by the hash of its secret
+func (r *InMemoryUserRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, exists := r.tokensByHash[hash]
+	if !exists {
+		return nil, domain.ErrInvalidToken
+	}
+	tokenCopy := *r.tokens[id]
+	return &tokenCopy, nil
+}
+
+// RotateRefreshToken revokes the old token and stores its successor
+func (r *InMemoryUserRepository) RotateRefreshToken(ctx context.Context, oldID string, next domain.RefreshToken, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, exists := r.tokens[oldID]
+	if !exists || old.RevokedAt != nil {
+		return domain.ErrInvalidToken
+	}
+	old.RevokedAt = &at
+	old.ReplacedBy = next.ID
+	r.saveTokenLocked(next)
+	return nil
+}
+
+// RevokeRefreshToken revokes one refresh token
+func (r *InMemoryUserRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, exists := r.tokens[id]; exists && token.RevokedAt == nil {
+		token.RevokedAt = &at
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user
+func (r *InMemoryUserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresUserRepository implements UserRepository on PostgreSQL
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserRepository creates a user repository on pool
+func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{pool: pool}
+}
+
+// CreateUser stores a new user
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user domain.User) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO users (id, email, password_hash, created_at) VALUES ($1, $2, $3, $4)`,
+		user.ID, user.Email, user.PasswordHash, user.CreatedAt)
+	if sqlState(err) == "23505" {
+		// unique_violation on email
+		return domain.ErrEmailTaken
+	}
+	return err
+}
+
+// getUser runs a single-user query
+func (r *PostgresUserRepository) getUser(ctx context.Context, sql string, arg any) (*domain.User, error) {
+	var user domain.User
+	err := r.pool.QueryRow(ctx, sql, arg).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByID retrieves a user by ID
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.getUser(ctx, `SELECT id, email, password_hash, created_at FROM users WHERE id = $1`, id)
+}
+
+// GetUserByEmail retrieves a user by normalized email
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.getUser(ctx, `SELECT id, email, password_hash, created_at FROM users WHERE email = $1`, email)
+}
+
+// insertRefreshTokenSQL stores a refresh token; the arguments follow refreshTokenArgs
+const insertRefreshTokenSQL = `
+	INSERT INTO refresh_tokens (id, user_id, token_hash, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5)`
+
+// refreshTokenArgs returns the arguments of insertRefreshTokenSQL
+func refreshTokenArgs(token domain.RefreshToken) []any {
+	return []any{token.ID, token.UserID, token.Hash, token.CreatedAt, token.ExpiresAt}
+}
+
+// SaveRefreshToken stores a new refresh token
+func (r *PostgresUserRepository) SaveRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	_, err := r.pool.Exec(ctx, insertRefreshTokenSQL, refreshTokenArgs(token)...)
+	return err
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by the hash of its secret
+func (r *PostgresUserRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	var replacedBy *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, user_id, token_hash, created_at, expires_at, revoked_at, replaced_by
+		FROM refresh_tokens WHERE token_hash = $1`, hash).
+		Scan(&token.ID, &token.UserID, &token.Hash, &token.CreatedAt, &token.ExpiresAt, &token.RevokedAt, &replacedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if replacedBy != nil {
+		token.ReplacedBy = *replacedBy
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken revokes the old token and stores its successor in one
+// transaction. The revocation only matches an unrevoked token, so of two
+// concurrent refreshes with the same token only one succeeds.
+func (r *PostgresUserRepository) RotateRefreshToken(ctx context.Context, oldID string, next domain.RefreshToken, at time.Time) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE refresh_tokens SET revoked_at = $2, replaced_by = $3 WHERE id = $1 AND revoked_at IS NULL`,
+			oldID, at, next.ID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrInvalidToken
+		}
+		_, err = tx.Exec(ctx, insertRefreshTokenSQL, refreshTokenArgs(next)...)
+		return err
+	})
+}
+
+// RevokeRefreshToken revokes one refresh token
+func (r *PostgresUserRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`, id, at)
+	return err
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user
+func (r *PostgresUserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`, userID, at)
+	return err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// InMemoryWatchlistRepository implements WatchlistRepository in memory.
+// Watchlists are lost on restart, so it only suits development and single instances.
+type InMemoryWatchlistRepository struct {
+	mu         sync.Mutex
+	watchlists map[string]*domain.Watchlist
+}
+
+// NewInMemoryWatchlistRepository creates an empty in-memory watchlist repository
+func NewInMemoryWatchlistRepository() *InMemoryWatchlistRepository {
+	return &InMemoryWatchlistRepository{
+		watchlists: make(map[string]*domain.Watchlist),
+	}
+}
+
+// copyWatchlist returns a copy that shares no memory with the stored watchlist
+func copyWatchlist(w *domain.Watchlist) *domain.Watchlist {
+	watchlistCopy := *w
+	watchlistCopy.ItemIDs = slices.Clone(w.ItemIDs)
+	return &watchlistCopy
+}
+
+// getLocked returns the stored watchlist of the user; r.mu must be held
+func (r *InMemoryWatchlistRepository) getLocked(userID, id string) (*domain.Watchlist, error) {
+	watchlist, exists := r.watchlists[id]
+	if !exists || watchlist.UserID != userID {
+		return nil, domain.ErrWatchlistNotFound
+	}
+	return watchlist, nil
+}
+
+// CreateWatchlist stores a new watchlist unless the user has max of them
+func (r *InMemoryWatchlistRepository) CreateWatchlist(ctx context.Context, watchlist domain.Watchlist, max int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.watchlists[watchlist.ID]; exists {
+		return errors.New("watchlist id already exists")
+	}
+	count := 0
+	for _, existing := range r.watchlists {
+		if existing.UserID == watchlist.UserID {
+			count++
+		}
+	}
+	if count >= max {
+		return domain.ErrTooManyWatchlists
+	}
+	r.watchlists[watchlist.ID] = copyWatchlist(&watchlist)
+	return nil
+}
+
+// GetWatchlist retrieves one of the user's watchlists
+func (r *InMemoryWatchlistRepository) GetWatchlist(ctx context.Context, userID, id string) (*domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watchlist, err := r.getLocked(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return copyWatchlist(watchlist), nil
+}
+
+// ListWatchlists returns the user's watchlists, oldest first
+func (r *InMemoryWatchlistRepository) ListWatchlists(ctx context.Context, userID string) ([]domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watchlists := make([]domain.Watchlist, 0)
+	for _, watchlist := range r.watchlists {
+		if watchlist.UserID == userID {
+			watchlists = append(watchlists, *copyWatchlist(watchlist))
+		}
+	}
+	sort.Slice(watchlists, func(i, j int) bool {
+		if !watchlists[i].CreatedAt.Equal(watchlists[j].CreatedAt) {
+			return watchlists[i].CreatedAt.Before(watchlists[j].CreatedAt)
+		}
+		return watchlists[i].ID < watchlists[j].ID
+	})
+	return watchlists, nil
+}
+
+// RenameWatchlist changes the name of a watchlist
+func (r *InMemoryWatchlistRepository) RenameWatchlist(ctx context.Context, userID, id, name string, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(userID, id, at, func(w *domain.Watchlist) error {
+		w.Name = name
+		return nil
+	})
+}
+
+// DeleteWatchlist removes a watchlist
+func (r *InMemoryWatchlistRepository) DeleteWatchlist(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.getLocked(userID, id); err != nil {
+		return err
+	}
+	delete(r.watchlists, id)
+	return nil
+}
+
+// AddWatchlistItems appends items not yet in the watchlist
+func (r *InMemoryWatchlistRepository) AddWatchlistItems(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(userID, id, at, func(w *domain.Watchlist) error {
+		return w.AddItems(itemIDs)
+	})
+}
+
+// RemoveWatchlistItem removes one item from the watchlist
+func (r *InMemoryWatchlistRepository) RemoveWatchlistItem(ctx context.Context, userID, id string, itemID int, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(userID, id, at, func(w *domain.Watchlist) error {
+		return w.RemoveItem(itemID)
+	})
+}
+
+// ReorderWatchlist replaces the order of the watchlist's items
+func (r *InMemoryWatchlistRepository) ReorderWatchlist(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(userID, id, at, func(w *domain.Watchlist) error {
+		return w.Reorder(itemIDs)
+	})
+}
+
+// modify applies fn to a copy of the watchlist and stores the result when fn succeeds
+func (r *InMemoryWatchlistRepository) modify(userID, id string, at time.Time, fn func(*domain.Watchlist) error) (*domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.getLocked(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist := copyWatchlist(stored)
+	if err := fn(watchlist); err != nil {
+		return nil, err
+	}
+	watchlist.UpdatedAt = at
+
+	r.watchlists[id] = copyWatchlist(watchlist)
+	return watchlist, nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// watchlistColumns is the column list scanned by scanWatchlist, with the
+// item IDs aggregated in the user's order
+const watchlistColumns = `w.id, w.user_id, w.name, w.created_at, w.updated_at,
+	COALESCE((SELECT array_agg(i.item_id ORDER BY i.position) FROM watchlist_items i WHERE i.watchlist_id = w.id), '{}')`
+
+// PostgresWatchlistRepository implements WatchlistRepository on PostgreSQL
+type PostgresWatchlistRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresWatchlistRepository creates a watchlist repository on pool
+func NewPostgresWatchlistRepository(pool *pgxpool.Pool) *PostgresWatchlistRepository {
+	return &PostgresWatchlistRepository{pool: pool}
+}
+
+// scanWatchlist scans a row selected with watchlistColumns
+func scanWatchlist(row pgx.CollectableRow) (domain.Watchlist, error) {
+	var watchlist domain.Watchlist
+	err := row.Scan(&watchlist.ID, &watchlist.UserID, &watchlist.Name, &watchlist.CreatedAt, &watchlist.UpdatedAt,
+		&watchlist.ItemIDs)
+	return watchlist, err
+}
+
+// queryWatchlists runs a watchlist query on q and collects the rows
+func queryWatchlists(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, sql string, args ...any) ([]domain.Watchlist, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanWatchlist)
+}
+
+// replaceWatchlistItems rewrites the members of a watchlist in order inside tx
+func replaceWatchlistItems(ctx context.Context, tx pgx.Tx, id string, itemIDs []int) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM watchlist_items WHERE watchlist_id = $1`, id); err != nil {
+		return err
+	}
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO watchlist_items (watchlist_id, item_id, position)
+		SELECT $1, item_id, position - 1 FROM unnest($2::int[]) WITH ORDINALITY AS t(item_id, position)`,
+		id, itemIDs)
+	return err
+}
+
+// CreateWatchlist stores a new watchlist with its items unless the user has
+// max of them. The user row is locked so concurrent creations count in turn.
+func (r *PostgresWatchlistRepository) CreateWatchlist(ctx context.Context, watchlist domain.Watchlist, max int) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		// A missing user is left to the foreign key of the insert
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, watchlist.UserID); err != nil {
+			return err
+		}
+		var count int
+		err := tx.QueryRow(ctx, `SELECT count(*) FROM watchlists WHERE user_id = $1`, watchlist.UserID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count >= max {
+			return domain.ErrTooManyWatchlists
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO watchlists (id, user_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)`,
+			watchlist.ID, watchlist.UserID, watchlist.Name, watchlist.CreatedAt, watchlist.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return replaceWatchlistItems(ctx, tx, watchlist.ID, watchlist.ItemIDs)
+	})
+}
+
+// GetWatchlist retrieves one of the user's watchlists
+func (r *PostgresWatchlistRepository) GetWatchlist(ctx context.Context, userID, id string) (*domain.Watchlist, error) {
+	watchlists, err := queryWatchlists(ctx, r.pool,
+		`SELECT `+watchlistColumns+` FROM watchlists w WHERE w.id = $1 AND w.user_id = $2`, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(watchlists) == 0 {
+		return nil, domain.ErrWatchlistNotFound
+	}
+	return &watchlists[0], nil
+}
+
+// ListWatchlists returns the user's watchlists, oldest first
+func (r *PostgresWatchlistRepository) ListWatchlists(ctx context.Context, userID string) ([]domain.Watchlist, error) {
+	return queryWatchlists(ctx, r.pool,
+		`SELECT `+watchlistColumns+` FROM watchlists w WHERE w.user_id = $1 ORDER BY w.created_at, w.id`, userID)
+}
+
+// RenameWatchlist changes the name of a watchlist
+func (r *PostgresWatchlistRepository) RenameWatchlist(ctx context.Context, userID, id, name string, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(ctx, userID, id, at, func(w *domain.Watchlist) error {
+		w.Name = name
+		return nil
+	})
+}
+
+// DeleteWatchlist removes a watchlist; its items are deleted by cascade
+func (r *PostgresWatchlistRepository) DeleteWatchlist(ctx context.Context, userID, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM watchlists WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWatchlistNotFound
+	}
+	return nil
+}
+
+// AddWatchlistItems appends items not yet in the watchlist
+func (r *PostgresWatchlistRepository) AddWatchlistItems(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(ctx, userID, id, at, func(w *domain.Watchlist) error {
+		return w.AddItems(itemIDs)
+	})
+}
+
+// RemoveWatchlistItem removes one item from the watchlist
+func (r *PostgresWatchlistRepository) RemoveWatchlistItem(ctx context.Context, userID, id string, itemID int, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(ctx, userID, id, at, func(w *domain.Watchlist) error {
+		return w.RemoveItem(itemID)
+	})
+}
+
+// ReorderWatchlist replaces the order of the watchlist's items
+func (r *PostgresWatchlistRepository) ReorderWatchlist(ctx context.Context, userID, id string, itemIDs []int, at time.Time) (*domain.Watchlist, error) {
+	return r.modify(ctx, userID, id, at, func(w *domain.Watchlist) error {
+		return w.Reorder(itemIDs)
+	})
+}
+
+// modify loads the watchlist with its row locked, applies fn and writes the
+// result back in the same transaction, so concurrent changes never interleave
+func (r *PostgresWatchlistRepository) modify(ctx context.Context, userID, id string, at time.Time, fn func(*domain.Watchlist) error) (*domain.Watchlist, error) {
+	var result *domain.Watchlist
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		watchlists, err := queryWatchlists(ctx, tx,
+			`SELECT `+watchlistColumns+` FROM watchlists w WHERE w.id = $1 AND w.user_id = $2 FOR UPDATE`, id, userID)
+		if err != nil {
+			return err
+		}
+		if len(watchlists) == 0 {
+			return domain.ErrWatchlistNotFound
+		}
+
+		watchlist := watchlists[0]
+		if err := fn(&watchlist); err != nil {
+			return err
+		}
+		watchlist.UpdatedAt = at
+
+		if _, err := tx.Exec(ctx, `UPDATE watchlists SET name = $2, updated_at = $3 WHERE id = $1`,
+			id, watchlist.Name, at); err != nil {
+			return err
+		}
+		if err := replaceWatchlistItems(ctx, tx, id, watchlist.ItemIDs); err != nil {
+			return err
+		}
+		result = &watchlist
+		return nil
+	})
+	return result, err
+}
@@ -2,16 +2,18 @@ package worker
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
 )
 
 // PriceUpdaterWorker handles periodic price updates
 type PriceUpdaterWorker struct {
 	updateUseCase *application.UpdatePricesUseCase
 	interval      time.Duration
+	intervalCh    chan time.Duration
 	ctx           context.Context
 	cancel        context.CancelFunc
 }
@@ -22,6 +24,7 @@ func NewPriceUpdaterWorker(updateUseCase *application.UpdatePricesUseCase, inter
 	return &PriceUpdaterWorker{
 		updateUseCase: updateUseCase,
 		interval:      interval,
+		intervalCh:    make(chan time.Duration, 1),
 		ctx:           ctx,
 		cancel:        cancel,
 	}
@@ -29,7 +32,7 @@ func NewPriceUpdaterWorker(updateUseCase *application.UpdatePricesUseCase, inter
 
 // Start begins the periodic price update worker
 func (w *PriceUpdaterWorker) Start() {
-	log.Printf("Price updater worker started with interval: %v", w.interval)
+	slog.Info("price updater worker started", "interval", w.interval.String())
 
 	go func() {
 		ticker := time.NewTicker(w.interval)
@@ -42,17 +45,36 @@ func (w *PriceUpdaterWorker) Start() {
 			select {
 			case <-ticker.C:
 				w.updatePrices()
+			case interval := <-w.intervalCh:
+				ticker.Reset(interval)
+				slog.Info("price updater interval changed", "interval", interval.String())
 			case <-w.ctx.Done():
-				log.Println("Price updater worker stopped")
+				slog.Info("price updater worker stopped")
 				return
 			}
 		}
 	}()
 }
 
+// SetInterval changes the time between updates; the next update happens
+// one full interval after the change. Only the latest pending value is kept.
+func (w *PriceUpdaterWorker) SetInterval(interval time.Duration) {
+	for {
+		select {
+		case w.intervalCh <- interval:
+			return
+		default:
+		}
+		select {
+		case <-w.intervalCh:
+		default:
+		}
+	}
+}
+
 // Stop stops the worker
 func (w *PriceUpdaterWorker) Stop() {
-	log.Println("Stopping price updater worker...")
+	slog.Info("stopping price updater worker")
 	w.cancel()
 }
 
@@ -61,10 +83,23 @@ func (w *PriceUpdaterWorker) updatePrices() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	log.Println("Updating prices from OSRS Wiki API...")
-	if err := w.updateUseCase.Execute(ctx); err != nil {
-		log.Printf("Error updating prices: %v", err)
+	slog.Debug("updating prices from OSRS Wiki API")
+	start := time.Now()
+	err := w.updateUseCase.Execute(ctx)
+	duration := time.Since(start)
+	metrics.ObserveUpdateCycle(duration, err)
+	if err != nil {
+		slog.Error("price update failed",
+			"error", err,
+			"duration_ms", duration.Milliseconds(),
+		)
 		return
 	}
-	log.Println("Prices updated successfully")
+
+	itemCount := w.updateUseCase.Status().LastItemCount
+	metrics.SetItemCount(itemCount)
+	slog.Info("prices updated",
+		"item_count", itemCount,
+		"duration_ms", duration.Milliseconds(),
+	)
 }
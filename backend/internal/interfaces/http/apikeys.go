@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
+	"github.com/go-chi/httprate"
+)
+
+// apiKeyHeader carries the API key secret
+const apiKeyHeader = "X-API-Key"
+
+// apiKeyContextKey stores the authenticated *domain.APIKey in the request context
+type apiKeyContextKey struct{}
+
+// apiKeyFromContext returns the key that authenticated the request, if any
+func apiKeyFromContext(ctx context.Context) (*domain.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*domain.APIKey)
+	return key, ok
+}
+
+// apiKeyMiddleware authenticates requests carrying an X-API-Key header and
+// counts them against the key's daily quota. Requests without the header
+// stay anonymous; an unknown or revoked key is rejected rather than being
+// downgraded to anonymous access, so misconfigured clients notice.
+func apiKeyMiddleware(authenticate *application.AuthenticateAPIKeyUseCase, reloader *config.Reloader) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret := r.Header.Get(apiKeyHeader)
+			if secret == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			access, err := authenticate.Execute(r.Context(), secret, reloader.Current().RateLimit.KeyDailyQuota)
+			switch {
+			case errors.Is(err, domain.ErrAPIKeyNotFound), errors.Is(err, domain.ErrAPIKeyRevoked):
+				metrics.IncAPIKeyRequest("invalid")
+				w.Header().Set("WWW-Authenticate", `APIKey header="`+apiKeyHeader+`"`)
+				http.Error(w, "invalid API key", http.StatusUnauthorized)
+				return
+			case err != nil:
+				slog.ErrorContext(r.Context(), "api key authentication failed", "error", err)
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+
+			if access.DailyQuota > 0 {
+				remaining := int64(access.DailyQuota) - access.Quota.Used
+				if remaining < 0 {
+					remaining = 0
+				}
+				w.Header().Set("X-Quota-Limit", strconv.Itoa(access.DailyQuota))
+				w.Header().Set("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+			}
+			if !access.Quota.Allowed {
+				metrics.IncAPIKeyRequest("quota_exceeded")
+				// Quotas reset at midnight UTC
+				now := time.Now().UTC()
+				reset := domain.UsageDay(now).Add(24 * time.Hour)
+				w.Header().Set("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+				http.Error(w, "daily API key quota exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			metrics.IncAPIKeyRequest("accepted")
+			ctx := context.WithValue(r.Context(), apiKeyContextKey{}, &access.Key)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// rateLimitKey limits requests with an API key per key, wherever they come
+// from, and anonymous requests per IP
+func rateLimitKey(r *http.Request) (string, error) {
+	if key, ok := apiKeyFromContext(r.Context()); ok {
+		return "key:" + key.ID, nil
+	}
+	return httprate.KeyByIP(r)
+}
+
+// requestLimit returns the per-minute limit of the request: the key's own
+// limit or the keyed default for authenticated requests, and the lower
+// anonymous limit otherwise
+func requestLimit(r *http.Request, cfg *config.Config) int {
+	key, ok := apiKeyFromContext(r.Context())
+	switch {
+	case !ok:
+		return cfg.RateLimit.RequestsPerMinute
+	case key.RateLimit > 0:
+		return key.RateLimit
+	default:
+		return cfg.RateLimit.KeyRequestsPerMinute
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// compressionLevel balances CPU cost and size for JSON payloads
+const compressionLevel = 5
+
+// compressionMiddleware negotiates brotli or gzip from Accept-Encoding.
+// Brotli is preferred when the client supports both.
+func compressionMiddleware() func(http.Handler) http.Handler {
+	compressor := middleware.NewCompressor(compressionLevel, "application/json", "text/csv", "text/plain")
+	compressor.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+		return brotli.NewWriterLevel(w, level)
+	})
+	return compressor.Handler
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// AnomaliesHandler handles listing items with suspicious price movements
+type AnomaliesHandler struct {
+	listAnomaliesUseCase *application.ListAnomaliesUseCase
+}
+
+// NewAnomaliesHandler creates a new AnomaliesHandler
+func NewAnomaliesHandler(listAnomaliesUseCase *application.ListAnomaliesUseCase) *AnomaliesHandler {
+	return &AnomaliesHandler{listAnomaliesUseCase: listAnomaliesUseCase}
+}
+
+// GetAnomalies handles GET /anomalies?type=&limit=
+func (h *AnomaliesHandler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "AnomaliesHandler.GetAnomalies")
+	defer span.End()
+
+	query := r.URL.Query()
+	anomalyType := domain.AnomalyType(query.Get("type"))
+	switch anomalyType {
+	case "", domain.AnomalyPriceSpike, domain.AnomalyVolumeBurst, domain.AnomalySpreadBlowout:
+	default:
+		respondWithError(w, http.StatusBadRequest, "invalid type (expected price_spike, volume_burst or spread_blowout)")
+		return
+	}
+
+	limit := defaultFlipsLimit
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			respondWithError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxLimit))
+			return
+		}
+		limit = parsed
+	}
+
+	items, err := h.listAnomaliesUseCase.Execute(ctx, anomalyType, limit)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, items)
+}
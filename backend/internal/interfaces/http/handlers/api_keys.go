@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeysHandler handles API key management requests from administrators
+type APIKeysHandler struct {
+	createAPIKeyUseCase   *application.CreateAPIKeyUseCase
+	listAPIKeysUseCase    *application.ListAPIKeysUseCase
+	getAPIKeyUsageUseCase *application.GetAPIKeyUsageUseCase
+	updateAPIKeyUseCase   *application.UpdateAPIKeyUseCase
+	revokeAPIKeyUseCase   *application.RevokeAPIKeyUseCase
+}
+
+// NewAPIKeysHandler creates a new APIKeysHandler
+func NewAPIKeysHandler(
+	createAPIKeyUseCase *application.CreateAPIKeyUseCase,
+	listAPIKeysUseCase *application.ListAPIKeysUseCase,
+	getAPIKeyUsageUseCase *application.GetAPIKeyUsageUseCase,
+	updateAPIKeyUseCase *application.UpdateAPIKeyUseCase,
+	revokeAPIKeyUseCase *application.RevokeAPIKeyUseCase,
+) *APIKeysHandler {
+	return &APIKeysHandler{
+		createAPIKeyUseCase:   createAPIKeyUseCase,
+		listAPIKeysUseCase:    listAPIKeysUseCase,
+		getAPIKeyUsageUseCase: getAPIKeyUsageUseCase,
+		updateAPIKeyUseCase:   updateAPIKeyUseCase,
+		revokeAPIKeyUseCase:   revokeAPIKeyUseCase,
+	}
+}
+
+// apiKeyRequest is the body of POST and PATCH /admin/keys requests
+type apiKeyRequest struct {
+	Name       *string `json:"name"`
+	RateLimit  *int    `json:"rate_limit"`
+	DailyQuota *int    `json:"daily_quota"`
+}
+
+// decode reads and validates the request body
+func (req *apiKeyRequest) decode(w http.ResponseWriter, r *http.Request) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAPIKeyBodySize)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return errors.New("invalid request body")
+	}
+	return validateAPIKeyFields(req.Name, req.RateLimit, req.DailyQuota)
+}
+
+// createAPIKeyResponse includes the secret, which is only ever shown here
+type createAPIKeyResponse struct {
+	Key    string        `json:"key"`
+	APIKey domain.APIKey `json:"api_key"`
+}
+
+// apiKeyUsageResponse is a key with its daily usage counters
+type apiKeyUsageResponse struct {
+	APIKey domain.APIKey        `json:"api_key"`
+	Usage  []domain.APIKeyUsage `json:"usage"`
+}
+
+// CreateAPIKey handles POST /admin/keys
+func (h *APIKeysHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "APIKeysHandler.CreateAPIKey")
+	defer span.End()
+
+	var req apiKeyRequest
+	if err := req.decode(w, r); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Name == nil {
+		respondWithError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	var rateLimit, dailyQuota int
+	if req.RateLimit != nil {
+		rateLimit = *req.RateLimit
+	}
+	if req.DailyQuota != nil {
+		dailyQuota = *req.DailyQuota
+	}
+
+	key, secret, err := h.createAPIKeyUseCase.Execute(ctx, *req.Name, rateLimit, dailyQuota)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondWithJSON(w, http.StatusCreated, createAPIKeyResponse{Key: secret, APIKey: key})
+}
+
+// ListAPIKeys handles GET /admin/keys
+func (h *APIKeysHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "APIKeysHandler.ListAPIKeys")
+	defer span.End()
+
+	keys, err := h.listAPIKeysUseCase.Execute(ctx)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, keys)
+}
+
+// GetAPIKey handles GET /admin/keys/{id}?days=7 with the key's daily usage
+func (h *APIKeysHandler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "APIKeysHandler.GetAPIKey")
+	defer span.End()
+
+	days, err := validateDays(r.URL.Query().Get("days"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	key, usage, err := h.getAPIKeyUsageUseCase.Execute(ctx, chi.URLParam(r, "id"), days)
+	if err != nil {
+		respondWithAPIKeyError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, apiKeyUsageResponse{APIKey: *key, Usage: usage})
+}
+
+// UpdateAPIKey handles PATCH /admin/keys/{id}; omitted fields are kept
+func (h *APIKeysHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "APIKeysHandler.UpdateAPIKey")
+	defer span.End()
+
+	var req apiKeyRequest
+	if err := req.decode(w, r); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, err := h.updateAPIKeyUseCase.Execute(ctx, chi.URLParam(r, "id"), application.APIKeyChanges{
+		Name:       req.Name,
+		RateLimit:  req.RateLimit,
+		DailyQuota: req.DailyQuota,
+	})
+	if err != nil {
+		respondWithAPIKeyError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, key)
+}
+
+// RevokeAPIKey handles DELETE /admin/keys/{id}
+func (h *APIKeysHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "APIKeysHandler.RevokeAPIKey")
+	defer span.End()
+
+	if err := h.revokeAPIKeyUseCase.Execute(ctx, chi.URLParam(r, "id")); err != nil {
+		respondWithAPIKeyError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondWithAPIKeyError maps API key errors to status codes
+func respondWithAPIKeyError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		respondWithError(w, http.StatusNotFound, "API key not found")
+		return
+	}
+	respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+}
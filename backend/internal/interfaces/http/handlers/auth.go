@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// userIDKey is the context key of the authenticated user's ID
+type userIDKey struct{}
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user's ID
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the user authenticated by RequireUser, if any
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok
+}
+
+// AuthHandler handles user registration, sessions and the current user
+type AuthHandler struct {
+	registerUserUseCase   *application.RegisterUserUseCase
+	loginUserUseCase      *application.LoginUserUseCase
+	refreshSessionUseCase *application.RefreshSessionUseCase
+	logoutUserUseCase     *application.LogoutUserUseCase
+	getUserUseCase        *application.GetUserUseCase
+	tokens                domain.AccessTokenIssuer
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(
+	registerUserUseCase *application.RegisterUserUseCase,
+	loginUserUseCase *application.LoginUserUseCase,
+	refreshSessionUseCase *application.RefreshSessionUseCase,
+	logoutUserUseCase *application.LogoutUserUseCase,
+	getUserUseCase *application.GetUserUseCase,
+	tokens domain.AccessTokenIssuer,
+) *AuthHandler {
+	return &AuthHandler{
+		registerUserUseCase:   registerUserUseCase,
+		loginUserUseCase:      loginUserUseCase,
+		refreshSessionUseCase: refreshSessionUseCase,
+		logoutUserUseCase:     logoutUserUseCase,
+		getUserUseCase:        getUserUseCase,
+		tokens:                tokens,
+	}
+}
+
+// credentialsRequest is the body of register and login requests
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// refreshRequest is the body of refresh and logout requests
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	Everywhere   bool   `json:"everywhere"` // Logout only: end every session of the user
+}
+
+// decodeAuthBody reads a small JSON request body into v
+func decodeAuthBody(w http.ResponseWriter, r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAuthBodySize)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errors.New("invalid request body")
+	}
+	return nil
+}
+
+// Register handles POST /auth/register
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "AuthHandler.Register")
+	defer span.End()
+
+	var req credentialsRequest
+	if err := decodeAuthBody(w, r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateEmail(req.Email); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validatePassword(req.Password); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.registerUserUseCase.Execute(ctx, req.Email, req.Password)
+	if errors.Is(err, domain.ErrEmailTaken) {
+		respondWithError(w, http.StatusConflict, "email already registered")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, user)
+}
+
+// Login handles POST /auth/login
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "AuthHandler.Login")
+	defer span.End()
+
+	var req credentialsRequest
+	if err := decodeAuthBody(w, r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := h.loginUserUseCase.Execute(ctx, req.Email, req.Password)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		respondWithError(w, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithSession(w, session)
+}
+
+// Refresh handles POST /auth/refresh, exchanging a refresh token for a new session
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "AuthHandler.Refresh")
+	defer span.End()
+
+	var req refreshRequest
+	if err := decodeAuthBody(w, r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.RefreshToken == "" {
+		respondWithError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	session, err := h.refreshSessionUseCase.Execute(ctx, req.RefreshToken)
+	if errors.Is(err, domain.ErrInvalidToken) {
+		respondWithError(w, http.StatusUnauthorized, "invalid or expired refresh token")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithSession(w, session)
+}
+
+// Logout handles POST /auth/logout. Unknown or revoked tokens are accepted
+// so a client can always discard its session.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "AuthHandler.Logout")
+	defer span.End()
+
+	var req refreshRequest
+	if err := decodeAuthBody(w, r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.RefreshToken == "" {
+		respondWithError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	if err := h.logoutUserUseCase.Execute(ctx, req.RefreshToken, req.Everywhere); err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me handles GET /me, returning the authenticated user
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "AuthHandler.Me")
+	defer span.End()
+
+	userID, _ := UserIDFromContext(ctx)
+	user, err := h.getUserUseCase.Execute(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		// The token outlived its account
+		respondUnauthorized(w, "user not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// RequireUser rejects requests without a valid "Authorization: Bearer"
+// access token and stores the token's user ID in the request context
+func (h *AuthHandler) RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			respondUnauthorized(w, "missing bearer token")
+			return
+		}
+
+		claims, err := h.tokens.Verify(token)
+		if err != nil {
+			respondUnauthorized(w, "invalid or expired access token")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithUserID(r.Context(), claims.UserID)))
+	})
+}
+
+// OptionalUser is RequireUser for routes that also serve anonymous
+// requests: without an Authorization header the request passes through
+// unauthenticated, but an invalid token is still rejected
+func (h *AuthHandler) OptionalUser(next http.Handler) http.Handler {
+	required := h.RequireUser(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		required.ServeHTTP(w, r)
+	})
+}
+
+// respondWithSession writes a session, which must never be cached
+func respondWithSession(w http.ResponseWriter, session domain.Session) {
+	w.Header().Set("Cache-Control", "no-store")
+	respondWithJSON(w, http.StatusOK, session)
+}
+
+// respondUnauthorized writes a 401 asking for a bearer token
+func respondUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="osrs-good-to-flip"`)
+	respondWithError(w, http.StatusUnauthorized, message)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/backtest"
+)
+
+// BacktestHandler handles strategy backtests
+type BacktestHandler struct {
+	runBacktestUseCase *application.RunBacktestUseCase
+}
+
+// NewBacktestHandler creates a new BacktestHandler
+func NewBacktestHandler(runBacktestUseCase *application.RunBacktestUseCase) *BacktestHandler {
+	return &BacktestHandler{runBacktestUseCase: runBacktestUseCase}
+}
+
+// RunBacktest handles POST /backtest with an application.BacktestRequest body
+func (h *BacktestHandler) RunBacktest(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "BacktestHandler.RunBacktest")
+	defer span.End()
+
+	var req application.BacktestRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxBacktestBody)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateBacktestRequest(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.runBacktestUseCase.Execute(ctx, req)
+	if errors.Is(err, backtest.ErrUnknownStrategy) {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
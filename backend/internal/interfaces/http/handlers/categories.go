@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+)
+
+// CategoriesHandler handles the per-category statistics
+type CategoriesHandler struct {
+	summarizeCategoriesUseCase *application.SummarizeCategoriesUseCase
+}
+
+// NewCategoriesHandler creates a new CategoriesHandler
+func NewCategoriesHandler(summarizeCategoriesUseCase *application.SummarizeCategoriesUseCase) *CategoriesHandler {
+	return &CategoriesHandler{summarizeCategoriesUseCase: summarizeCategoriesUseCase}
+}
+
+// GetCategories handles GET /categories
+func (h *CategoriesHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "CategoriesHandler.GetCategories")
+	defer span.End()
+
+	stats, err := h.summarizeCategoriesUseCase.Execute(ctx)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+)
+
+// ConditionalHandler adds ETag, Last-Modified and Cache-Control headers to
+// price responses and answers conditional requests with 304 Not Modified
+type ConditionalHandler struct {
+	getDataVersionUseCase *application.GetDataVersionUseCase
+	updateInterval        atomic.Int64 // time.Duration; changed on config reload
+}
+
+// NewConditionalHandler creates a new ConditionalHandler
+func NewConditionalHandler(getDataVersionUseCase *application.GetDataVersionUseCase, updateInterval time.Duration) *ConditionalHandler {
+	h := &ConditionalHandler{getDataVersionUseCase: getDataVersionUseCase}
+	h.SetUpdateInterval(updateInterval)
+	return h
+}
+
+// SetUpdateInterval changes the interval Cache-Control max-age is based on
+func (h *ConditionalHandler) SetUpdateInterval(interval time.Duration) {
+	h.updateInterval.Store(int64(interval))
+}
+
+// Middleware wraps GET handlers whose output only changes when the data version
+// does. Validators are only sent with 2xx responses, and only those become 304.
+func (h *ConditionalHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		version, err := h.getDataVersionUseCase.Execute(r.Context())
+		if err != nil {
+			// Serve without validators rather than failing the request
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Weak: item ordering in list responses is not byte-stable across requests
+		etag := fmt.Sprintf(`W/"%d"`, version.Version)
+		lastModified := version.ModifiedAt.UTC().Truncate(time.Second)
+
+		// Set before the handler runs, since the response cache keys on the ETag;
+		// conditionalWriter removes them if the response is not a success
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		w.Header().Set("Cache-Control", h.cacheControl(version.UpdatedAt))
+
+		next.ServeHTTP(&conditionalWriter{ResponseWriter: w, notModified: notModified(r, etag, lastModified)}, r)
+	})
+}
+
+// conditionalWriter settles the response once the handler picks its status:
+// errors lose the validators, and successes become 304 Not Modified when the
+// request's preconditions matched
+type conditionalWriter struct {
+	http.ResponseWriter
+	notModified bool
+	wroteHeader bool
+	discard     bool // The body of a response turned into a 304
+}
+
+func (w *conditionalWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	switch {
+	case status < 200 || status > 299:
+		header.Del("ETag")
+		header.Del("Last-Modified")
+		header.Del("Cache-Control")
+	case w.notModified:
+		w.discard = true
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		status = http.StatusNotModified
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *conditionalWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets streaming handlers flush through the wrapper
+func (w *conditionalWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.discard {
+		http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *conditionalWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// cacheControl lets clients reuse responses until the next expected price update
+func (h *ConditionalHandler) cacheControl(updatedAt time.Time) string {
+	updateInterval := time.Duration(h.updateInterval.Load())
+	maxAge := updateInterval - time.Since(updatedAt)
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	if maxAge > updateInterval {
+		maxAge = updateInterval
+	}
+	return fmt.Sprintf("public, max-age=%d, must-revalidate", int(maxAge.Seconds()))
+}
+
+// notModified evaluates If-None-Match and If-Modified-Since (RFC 9110).
+// If-Modified-Since is ignored when If-None-Match is present.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || weakMatch(candidate, etag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.After(since)
+	}
+
+	return false
+}
+
+// weakMatch compares two entity tags ignoring the weak indicator
+func weakMatch(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
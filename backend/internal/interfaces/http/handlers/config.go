@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+)
+
+// ConfigHandler exposes and reloads the running configuration for administrators
+type ConfigHandler struct {
+	reloader *config.Reloader
+}
+
+// NewConfigHandler creates a new ConfigHandler
+func NewConfigHandler(reloader *config.Reloader) *ConfigHandler {
+	return &ConfigHandler{reloader: reloader}
+}
+
+// GetConfig handles GET /admin/config with secrets redacted
+func (h *ConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, h.reloader.Current().Redacted())
+}
+
+// ReloadConfig handles POST /admin/config/reload, the HTTP equivalent of SIGHUP
+func (h *ConfigHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	changed, err := h.reloader.Reload()
+
+	var restart *config.RestartRequiredError
+	switch {
+	case errors.As(err, &restart):
+		respondWithJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":            restart.Error(),
+			"restart_required": restart.Keys,
+		})
+		return
+	case err != nil:
+		// Only administrators reach this route, so the details are safe to show
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if changed == nil {
+		changed = []string{}
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"changed": changed})
+}
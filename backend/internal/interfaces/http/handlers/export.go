@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/export"
+)
+
+// ExportHandler handles CSV and Parquet export requests
+type ExportHandler struct {
+	exportItemsUseCase        *application.ExportItemsUseCase
+	exportPriceHistoryUseCase *application.ExportPriceHistoryUseCase
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler(
+	exportItemsUseCase *application.ExportItemsUseCase,
+	exportPriceHistoryUseCase *application.ExportPriceHistoryUseCase,
+) *ExportHandler {
+	return &ExportHandler{
+		exportItemsUseCase:        exportItemsUseCase,
+		exportPriceHistoryUseCase: exportPriceHistoryUseCase,
+	}
+}
+
+// ExportItems handles GET /export/items?format=csv|parquet&q=&category=&tag=
+func (h *ExportHandler) ExportItems(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "ExportHandler.ExportItems")
+	defer span.End()
+
+	query := r.URL.Query().Get("q")
+	if err := validateQuery(query); err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	category, tag, err := validateCategoryFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	filter := domain.ItemFilter{Query: query, Category: category, Tag: tag}
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	extendWriteDeadline(w, 60*time.Second)
+	setExportHeaders(w, format, "items")
+	writer, err := export.NewItemWriter(format, w)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start items export", "error", err)
+		return
+	}
+
+	err = h.exportItemsUseCase.Execute(ctx, filter, writer.WriteItem)
+	finishExport(ctx, "items", writer.Close, err)
+}
+
+// ExportPriceHistory handles GET /export/history?format=csv|parquet&q=&category=&tag=&days=
+func (h *ExportHandler) ExportPriceHistory(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 120*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "ExportHandler.ExportPriceHistory")
+	defer span.End()
+
+	query := r.URL.Query().Get("q")
+	if err := validateQuery(query); err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	category, tag, err := validateCategoryFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	filter := domain.ItemFilter{Query: query, Category: category, Tag: tag}
+
+	days, err := validateExportDays(r.URL.Query().Get("days"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	extendWriteDeadline(w, 120*time.Second)
+	setExportHeaders(w, format, "price-history")
+	writer, err := export.NewHistoryWriter(format, w)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start history export", "error", err)
+		return
+	}
+
+	err = h.exportPriceHistoryUseCase.Execute(ctx, filter, days, writer.WriteHistory)
+	finishExport(ctx, "price_history", writer.Close, err)
+}
+
+// extendWriteDeadline lets long exports outlive the server-wide WriteTimeout
+func extendWriteDeadline(w http.ResponseWriter, d time.Duration) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d)); err != nil {
+		slog.Debug("cannot extend write deadline", "error", err)
+	}
+}
+
+// setExportHeaders sets the content type and download file name
+func setExportHeaders(w http.ResponseWriter, format export.Format, name string) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102"), format.Extension())
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+}
+
+// finishExport closes the writer unless streaming failed.
+// Headers are already sent on failure, so the file is left truncated
+// (no trailing rows or footer) and the error is only logged.
+func finishExport(ctx context.Context, name string, closeWriter func() error, err error) {
+	if err != nil {
+		slog.WarnContext(ctx, "export aborted", "export", name, "error", err)
+		return
+	}
+	if err := closeWriter(); err != nil {
+		slog.WarnContext(ctx, "failed to finish export", "export", name, "error", err)
+	}
+}
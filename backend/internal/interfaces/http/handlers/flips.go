@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+)
+
+// FlipsHandler handles flip ranking requests
+type FlipsHandler struct {
+	findFlipsUseCase          *application.FindFlipsUseCase
+	getBuyLimitsUseCase       *application.GetBuyLimitsUseCase
+	optimizeAllocationUseCase *application.OptimizeAllocationUseCase
+}
+
+// NewFlipsHandler creates a new FlipsHandler
+func NewFlipsHandler(
+	findFlipsUseCase *application.FindFlipsUseCase,
+	getBuyLimitsUseCase *application.GetBuyLimitsUseCase,
+	optimizeAllocationUseCase *application.OptimizeAllocationUseCase,
+) *FlipsHandler {
+	return &FlipsHandler{
+		findFlipsUseCase:          findFlipsUseCase,
+		getBuyLimitsUseCase:       getBuyLimitsUseCase,
+		optimizeAllocationUseCase: optimizeAllocationUseCase,
+	}
+}
+
+// GetFlips handles GET /flips?min_margin=&min_volume=&sort=&limit=&exclude_anomalies=&category=&tag=.
+// Behind AuthHandler.OptionalUser, a signed-in user's flips carry their
+// remaining buy limits and items they are capped on rank last.
+func (h *FlipsHandler) GetFlips(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "FlipsHandler.GetFlips")
+	defer span.End()
+
+	// Validate filter parameters
+	filter, err := validateFlipFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	filter.BuyLimits, err = h.userBuyLimits(ctx, w)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+
+	flips, err := h.findFlipsUseCase.Execute(ctx, filter)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, flips)
+}
+
+// OptimizeAllocation handles GET /flips/optimize?cash=&slots=&risk=low|medium|high&horizon=.
+// Like GetFlips, a signed-in user's remaining buy limits are taken into account.
+func (h *FlipsHandler) OptimizeAllocation(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "FlipsHandler.OptimizeAllocation")
+	defer span.End()
+
+	req, err := validateAllocationRequest(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	buyLimits, err := h.userBuyLimits(ctx, w)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+
+	plan, err := h.optimizeAllocationUseCase.Execute(ctx, req, buyLimits)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plan)
+}
+
+// userBuyLimits returns the buy limit status of the items the signed-in user
+// bought recently, keyed by item ID, or nil for anonymous requests. Responses
+// using them depend on the user's trades, so they are marked private.
+func (h *FlipsHandler) userBuyLimits(ctx context.Context, w http.ResponseWriter) (map[int]domain.BuyLimitStatus, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	statuses, err := h.getBuyLimitsUseCase.Execute(ctx, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	buyLimits := make(map[int]domain.BuyLimitStatus, len(statuses))
+	for _, status := range statuses {
+		buyLimits[status.ItemID] = status
+	}
+	w.Header().Set("Cache-Control", "private, no-store")
+	return buyLimits, nil
+}
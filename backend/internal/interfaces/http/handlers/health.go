@@ -1,24 +1,46 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
 )
 
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	checkHealthUseCase *application.CheckHealthUseCase
+}
 
 // NewHealthHandler creates a new HealthHandler
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(checkHealthUseCase *application.CheckHealthUseCase) *HealthHandler {
+	return &HealthHandler{checkHealthUseCase: checkHealthUseCase}
 }
 
-// Check handles GET /health
-func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
+// Live handles GET /health and GET /health/live
+// It only reports that the process is up and serving requests
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]string{
 		"status":  "ok",
 		"service": "osrs-good-to-flip",
 	})
 }
+
+// Ready handles GET /health/ready
+// Returns 503 when any component is degraded or down
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	report := h.checkHealthUseCase.Execute(ctx)
+
+	statusCode := http.StatusOK
+	if report.Status != domain.HealthOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	respondWithJSON(w, statusCode, report)
+}
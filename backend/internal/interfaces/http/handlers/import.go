@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/importer"
+)
+
+// maxImportBodyBytes bounds the size of an uploaded price history dump
+const maxImportBodyBytes = 256 << 20 // 256MB
+
+// ImportHandler handles historical price imports
+type ImportHandler struct {
+	importPriceHistoryUseCase *application.ImportPriceHistoryUseCase
+}
+
+// NewImportHandler creates a new ImportHandler
+func NewImportHandler(importPriceHistoryUseCase *application.ImportPriceHistoryUseCase) *ImportHandler {
+	return &ImportHandler{importPriceHistoryUseCase: importPriceHistoryUseCase}
+}
+
+// ImportPriceHistory handles POST /admin/import/history?format=csv|json&dry_run=true
+// The request body is the dump itself; the response is the import summary
+func (h *ImportHandler) ImportPriceHistory(w http.ResponseWriter, r *http.Request) {
+	// Large dumps take longer than the server-wide timeouts
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Minute)
+	defer cancel()
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Now().Add(10 * time.Minute))
+	rc.SetWriteDeadline(time.Now().Add(10 * time.Minute))
+
+	ctx, span := tracer.Start(ctx, "ImportHandler.ImportPriceHistory")
+	defer span.End()
+
+	format, err := importer.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid dry_run value")
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodyBytes)
+	source, err := importer.NewHistorySource(format, body)
+	if err != nil {
+		respondWithImportError(w, fmt.Errorf("%w: %w", domain.ErrInvalidImport, err), domain.ImportSummary{DryRun: dryRun, Rejections: []domain.ImportRejection{}})
+		return
+	}
+
+	summary, err := h.importPriceHistoryUseCase.Execute(ctx, source, dryRun)
+	if err != nil {
+		span.RecordError(err)
+		respondWithImportError(w, err, summary)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, summary)
+}
+
+// importErrorResponse reports a failed import along with the counts reached
+// before the failure; rows counted in Inserted are already stored
+type importErrorResponse struct {
+	Error string `json:"error"`
+	domain.ImportSummary
+}
+
+// respondWithImportError maps an import failure to its status: 413 for an
+// oversized body, 400 for an unreadable file and 500 for storage errors and
+// timeouts
+func respondWithImportError(w http.ResponseWriter, err error, summary domain.ImportSummary) {
+	status, message := http.StatusInternalServerError, getSafeErrorMessage(err)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		status = http.StatusRequestEntityTooLarge
+		message = fmt.Sprintf("import file exceeds %d bytes", tooLarge.Limit)
+	case errors.Is(err, domain.ErrInvalidImport):
+		status, message = http.StatusBadRequest, err.Error()
+	}
+	respondWithJSON(w, status, importErrorResponse{Error: message, ImportSummary: summary})
+}
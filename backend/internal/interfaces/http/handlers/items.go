@@ -3,21 +3,31 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
 	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/forecast"
 	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/gabv/osrs-good-to-flip/backend/internal/interfaces/http/handlers")
+
 // ItemsHandler handles item-related HTTP requests
 type ItemsHandler struct {
 	getItemUseCase         *application.GetItemUseCase
 	searchItemsUseCase     *application.SearchItemsUseCase
 	getPriceHistoryUseCase *application.GetPriceHistoryUseCase
+	exportItemsUseCase     *application.ExportItemsUseCase
+	getItemsBatchUseCase   *application.GetItemsBatchUseCase
+	forecastPriceUseCase   *application.ForecastPriceUseCase
 }
 
 // NewItemsHandler creates a new ItemsHandler
@@ -25,11 +35,17 @@ func NewItemsHandler(
 	getItemUseCase *application.GetItemUseCase,
 	searchItemsUseCase *application.SearchItemsUseCase,
 	getPriceHistoryUseCase *application.GetPriceHistoryUseCase,
+	exportItemsUseCase *application.ExportItemsUseCase,
+	getItemsBatchUseCase *application.GetItemsBatchUseCase,
+	forecastPriceUseCase *application.ForecastPriceUseCase,
 ) *ItemsHandler {
 	return &ItemsHandler{
 		getItemUseCase:         getItemUseCase,
 		searchItemsUseCase:     searchItemsUseCase,
 		getPriceHistoryUseCase: getPriceHistoryUseCase,
+		exportItemsUseCase:     exportItemsUseCase,
+		getItemsBatchUseCase:   getItemsBatchUseCase,
+		forecastPriceUseCase:   forecastPriceUseCase,
 	}
 }
 
@@ -39,6 +55,9 @@ func (h *ItemsHandler) GetItems(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
+	ctx, span := tracer.Start(ctx, "ItemsHandler.GetItems")
+	defer span.End()
+
 	query := r.URL.Query().Get("q")
 
 	// Validate query
@@ -59,8 +78,15 @@ func (h *ItemsHandler) GetItems(w http.ResponseWriter, r *http.Request) {
 	
 	params := domain.NewPaginationParams(validatedPage, validatedLimit)
 
+	category, tag, err := validateCategoryFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	filter := domain.ItemFilter{Query: query, Category: category, Tag: tag}
+
 	// Use paginated version
-	result, err := h.searchItemsUseCase.ExecutePaginated(ctx, query, params)
+	result, err := h.searchItemsUseCase.ExecutePaginated(ctx, filter, params)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
 		return
@@ -89,6 +115,9 @@ func (h *ItemsHandler) GetItemByID(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
+	ctx, span := tracer.Start(ctx, "ItemsHandler.GetItemByID")
+	defer span.End()
+
 	idStr := chi.URLParam(r, "id")
 
 	// Validate item ID
@@ -117,6 +146,9 @@ func (h *ItemsHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
+	ctx, span := tracer.Start(ctx, "ItemsHandler.GetPriceHistory")
+	defer span.End()
+
 	idStr := chi.URLParam(r, "id")
 	daysStr := r.URL.Query().Get("days")
 
@@ -147,6 +179,144 @@ func (h *ItemsHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, history)
 }
 
+// GetForecast handles GET /items/{id}/forecast?days=
+func (h *ItemsHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "ItemsHandler.GetForecast")
+	defer span.End()
+
+	id, err := validateItemID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	days, err := validateForecastDays(r.URL.Query().Get("days"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	result, err := h.forecastPriceUseCase.Execute(ctx, id, days)
+	if err != nil {
+		switch {
+		case err.Error() == "item not found":
+			respondWithError(w, http.StatusNotFound, "Item not found")
+		case errors.Is(err, forecast.ErrInsufficientHistory):
+			respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// batchRequest is the body accepted by POST /items/batch
+type batchRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// GetItemsBatch handles GET /items/batch?ids=1,2,3 and POST /items/batch
+// Returns a result per requested ID, with found=false for unknown items
+func (h *ItemsHandler) GetItemsBatch(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "ItemsHandler.GetItemsBatch")
+	defer span.End()
+
+	var idStrs []string
+	if r.Method == http.MethodPost {
+		var req batchRequest
+		r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		for _, id := range req.IDs {
+			idStrs = append(idStrs, strconv.Itoa(id))
+		}
+	} else if raw := r.URL.Query().Get("ids"); raw != "" {
+		idStrs = strings.Split(raw, ",")
+	}
+
+	// Validate item IDs
+	ids, err := validateItemIDs(idStrs)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+
+	results, err := h.getItemsBatchUseCase.Execute(ctx, ids)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+	})
+}
+
+// ExportAllItems handles GET /items/all?q=&category=&tag=
+// Streams every matching item as a JSON array, encoding one item at a time
+func (h *ItemsHandler) ExportAllItems(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "ItemsHandler.ExportAllItems")
+	defer span.End()
+
+	query := r.URL.Query().Get("q")
+
+	// Validate query
+	if err := validateQuery(query); err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	category, tag, err := validateCategoryFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, getSafeErrorMessage(err))
+		return
+	}
+	filter := domain.ItemFilter{Query: query, Category: category, Tag: tag}
+
+	// The server-wide WriteTimeout is shorter than the export's own timeout
+	extendWriteDeadline(w, 30*time.Second)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	if _, err := w.Write([]byte("[")); err != nil {
+		return
+	}
+
+	count := 0
+	err = h.exportItemsUseCase.Execute(ctx, filter, func(item domain.ItemPrice) error {
+		if count > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		count++
+		return enc.Encode(item)
+	})
+	if err != nil {
+		// Headers are already sent: leave the array unterminated so clients
+		// detect the truncated payload instead of trusting a partial catalog
+		slog.WarnContext(ctx, "items export aborted", "error", err, "item_count", count)
+		return
+	}
+
+	w.Write([]byte("]\n"))
+}
+
 // respondWithJSON sends a JSON response
 func respondWithJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+)
+
+// LimitsHandler handles the buy limits of the authenticated user.
+// Its routes must be behind AuthHandler.RequireUser.
+type LimitsHandler struct {
+	getBuyLimitsUseCase *application.GetBuyLimitsUseCase
+}
+
+// NewLimitsHandler creates a new LimitsHandler
+func NewLimitsHandler(getBuyLimitsUseCase *application.GetBuyLimitsUseCase) *LimitsHandler {
+	return &LimitsHandler{getBuyLimitsUseCase: getBuyLimitsUseCase}
+}
+
+// GetLimits handles GET /limits?item_id=
+func (h *LimitsHandler) GetLimits(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "LimitsHandler.GetLimits")
+	defer span.End()
+
+	var itemID int
+	if idStr := r.URL.Query().Get("item_id"); idStr != "" {
+		id, err := validateItemID(idStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		itemID = id
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	statuses, err := h.getBuyLimitsUseCase.Execute(ctx, userID, itemID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, statuses)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/importer"
+)
+
+// PortfolioHandler handles valuing bank snapshots
+type PortfolioHandler struct {
+	valuePortfolioUseCase *application.ValuePortfolioUseCase
+}
+
+// NewPortfolioHandler creates a new PortfolioHandler
+func NewPortfolioHandler(valuePortfolioUseCase *application.ValuePortfolioUseCase) *PortfolioHandler {
+	return &PortfolioHandler{valuePortfolioUseCase: valuePortfolioUseCase}
+}
+
+// ValuePortfolio handles POST /portfolio/value. The body is the snapshot
+// itself, as JSON or a RuneLite bank export (see importer.ParseBankSnapshot).
+func (h *PortfolioHandler) ValuePortfolio(w http.ResponseWriter, r *http.Request) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "PortfolioHandler.ValuePortfolio")
+	defer span.End()
+
+	holdings, err := importer.ParseBankSnapshot(http.MaxBytesReader(w, r.Body, maxPortfolioBody))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, "snapshot too large")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid snapshot: "+err.Error())
+		return
+	}
+	if err := validateHoldings(holdings); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	portfolio, err := h.valuePortfolioUseCase.Execute(ctx, holdings)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, portfolio)
+}
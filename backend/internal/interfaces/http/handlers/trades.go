@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// TradesHandler handles the trade journal of the authenticated user.
+// Its routes must be behind AuthHandler.RequireUser.
+type TradesHandler struct {
+	logTradesUseCase      *application.LogTradesUseCase
+	listTradesUseCase     *application.ListTradesUseCase
+	deleteTradeUseCase    *application.DeleteTradeUseCase
+	getTradeReportUseCase *application.GetTradeReportUseCase
+}
+
+// NewTradesHandler creates a new TradesHandler
+func NewTradesHandler(
+	logTradesUseCase *application.LogTradesUseCase,
+	listTradesUseCase *application.ListTradesUseCase,
+	deleteTradeUseCase *application.DeleteTradeUseCase,
+	getTradeReportUseCase *application.GetTradeReportUseCase,
+) *TradesHandler {
+	return &TradesHandler{
+		logTradesUseCase:      logTradesUseCase,
+		listTradesUseCase:     listTradesUseCase,
+		deleteTradeUseCase:    deleteTradeUseCase,
+		getTradeReportUseCase: getTradeReportUseCase,
+	}
+}
+
+// tradeRequest is one trade in a POST /trades or /trades/batch body
+type tradeRequest struct {
+	ItemID   int        `json:"item_id"`
+	Side     string     `json:"side"`
+	Quantity int        `json:"quantity"`
+	Price    int        `json:"price"`
+	TradedAt *time.Time `json:"traded_at"`
+}
+
+// tradesBatchRequest is the body of POST /trades/batch
+type tradesBatchRequest struct {
+	Trades []tradeRequest `json:"trades"`
+}
+
+// decodeTradesBody reads a JSON request body into v
+func decodeTradesBody(w http.ResponseWriter, r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxTradesBody)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errors.New("invalid request body")
+	}
+	return nil
+}
+
+// LogTrade handles POST /trades with a single trade
+func (h *TradesHandler) LogTrade(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "TradesHandler.LogTrade")
+	defer span.End()
+
+	var req tradeRequest
+	if err := decodeTradesBody(w, r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	trade, err := validateTrade(req, time.Now())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	logged, err := h.logTradesUseCase.Execute(ctx, userID, []domain.Trade{trade})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, logged[0])
+}
+
+// LogTrades handles POST /trades/batch, storing every trade or none
+func (h *TradesHandler) LogTrades(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "TradesHandler.LogTrades")
+	defer span.End()
+
+	var req tradesBatchRequest
+	if err := decodeTradesBody(w, r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Trades) == 0 {
+		respondWithError(w, http.StatusBadRequest, "trades is required")
+		return
+	}
+	if len(req.Trades) > maxTradesBatch {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("too many trades (max %d)", maxTradesBatch))
+		return
+	}
+
+	now := time.Now()
+	trades := make([]domain.Trade, len(req.Trades))
+	for i, tradeReq := range req.Trades {
+		trade, err := validateTrade(tradeReq, now)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("trades[%d]: %v", i, err))
+			return
+		}
+		trades[i] = trade
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	logged, err := h.logTradesUseCase.Execute(ctx, userID, trades)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, logged)
+}
+
+// ListTrades handles GET /trades?item_id=&from=&to=
+func (h *TradesHandler) ListTrades(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "TradesHandler.ListTrades")
+	defer span.End()
+
+	filter, err := validateTradeFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	trades, err := h.listTradesUseCase.Execute(ctx, userID, filter)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, trades)
+}
+
+// DeleteTrade handles DELETE /trades/{id}
+func (h *TradesHandler) DeleteTrade(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "TradesHandler.DeleteTrade")
+	defer span.End()
+
+	userID, _ := UserIDFromContext(ctx)
+	err := h.deleteTradeUseCase.Execute(ctx, userID, chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrTradeNotFound) {
+		respondWithError(w, http.StatusNotFound, "trade not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTradeReport handles GET /trades/report?item_id=&from=&to=&period=day|week|month
+func (h *TradesHandler) GetTradeReport(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "TradesHandler.GetTradeReport")
+	defer span.End()
+
+	query := r.URL.Query()
+	filter, err := validateTradeFilter(query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	period, err := validateReportPeriod(query.Get("period"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	report, err := h.getTradeReportUseCase.Execute(ctx, userID, filter, period)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
@@ -2,9 +2,16 @@ package handlers
 
 import (
 	"fmt"
-	"os"
+	"math"
+	"net/mail"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/backtest"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
 )
 
 const (
@@ -15,6 +22,35 @@ const (
 	minDays           = 1
 	maxPage           = 10000
 	maxLimit          = 100
+	maxExportDays     = 3650
+	maxBatchSize      = 100
+	maxMinMargin      = 1000
+	defaultFlipsLimit = 50
+	maxBatchBodyBytes = 64 << 10
+	maxAPIKeyName     = 100
+	maxAPIKeyBodySize = 4 << 10
+	maxEmailLength    = 254
+	minPassword       = 8
+	maxPassword       = 72 // bcrypt ignores anything longer
+	maxAuthBodySize   = 4 << 10
+	maxWatchlistName  = 100
+	maxWatchlistBody  = 16 << 10
+	maxTradesBatch    = 1000
+	maxTradesBody     = 256 << 10
+	maxTradeClockSkew = 5 * time.Minute
+	maxPortfolioItems = 2000
+	maxPortfolioBody  = 1 << 20
+	maxAllocationCash = 1_000_000_000_000
+	defaultHorizon    = 4 * time.Hour
+	minHorizon        = time.Hour
+	maxHorizon        = 48 * time.Hour
+	maxBacktestBody   = 16 << 10
+	defaultReplayDays = 30
+	maxBacktestDays   = 365
+	maxBacktestTTL    = 100
+	maxLookback       = 365
+	maxForecastDays   = 90
+	maxTagLength      = 50
 )
 
 // validateItemID validates and parses an item ID from URL parameter
@@ -40,6 +76,33 @@ func validateItemID(idStr string) (int, error) {
 	return id, nil
 }
 
+// validateItemIDs validates a list of item IDs for batch lookups.
+// Duplicates are removed while keeping the first occurrence order.
+func validateItemIDs(idStrs []string) ([]int, error) {
+	if len(idStrs) == 0 {
+		return nil, fmt.Errorf("item IDs are required")
+	}
+
+	if len(idStrs) > maxBatchSize {
+		return nil, fmt.Errorf("invalid ids: at most %d item IDs allowed", maxBatchSize)
+	}
+
+	ids := make([]int, 0, len(idStrs))
+	seen := make(map[int]bool, len(idStrs))
+	for _, idStr := range idStrs {
+		id, err := validateItemID(strings.TrimSpace(idStr))
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
 // validateQuery validates search query string
 func validateQuery(query string) error {
 	if len(query) > maxQueryLength {
@@ -91,9 +154,417 @@ func validateDays(daysStr string) (int, error) {
 	return days, nil
 }
 
+// validateExportDays validates the days parameter for history exports
+// Exports accept much longer ranges than the history endpoint
+func validateExportDays(daysStr string) (int, error) {
+	if daysStr == "" {
+		return 30, nil // Default
+	}
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid days format")
+	}
+
+	if days < minDays || days > maxExportDays {
+		return 0, fmt.Errorf("days must be between %d and %d", minDays, maxExportDays)
+	}
+
+	return days, nil
+}
+
+// validateForecastDays validates the days of history a forecast is fit on
+func validateForecastDays(daysStr string) (int, error) {
+	if daysStr == "" {
+		return 30, nil // Default
+	}
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid days format")
+	}
+
+	if days < minDays || days > maxForecastDays {
+		return 0, fmt.Errorf("days must be between %d and %d", minDays, maxForecastDays)
+	}
+
+	return days, nil
+}
+
+// validateFlipFilter validates the /flips query parameters
+func validateFlipFilter(query url.Values) (domain.FlipFilter, error) {
+	filter := domain.FlipFilter{SortBy: domain.FlipSortProfit, Limit: defaultFlipsLimit}
+
+	if v := query.Get("min_margin"); v != "" {
+		margin, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid min_margin format")
+		}
+		if margin < 0 || margin > maxMinMargin {
+			return filter, fmt.Errorf("min_margin must be between 0 and %d", maxMinMargin)
+		}
+		filter.MinMargin = margin
+	}
+
+	if v := query.Get("min_volume"); v != "" {
+		volume, err := strconv.Atoi(v)
+		if err != nil || volume < 0 {
+			return filter, fmt.Errorf("invalid min_volume format")
+		}
+		filter.MinVolume = volume
+	}
+
+	if v := query.Get("sort"); v != "" {
+		switch domain.FlipSort(v) {
+		case domain.FlipSortProfit, domain.FlipSortMargin, domain.FlipSortVolume:
+			filter.SortBy = domain.FlipSort(v)
+		default:
+			return filter, fmt.Errorf("invalid sort (expected profit, margin or volume)")
+		}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid limit format")
+		}
+		if limit < 1 || limit > maxLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		filter.Limit = limit
+	}
+
+	if v := query.Get("exclude_anomalies"); v != "" {
+		exclude, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid exclude_anomalies format")
+		}
+		filter.ExcludeAnomalies = exclude
+	}
+
+	category, tag, err := validateCategoryFilter(query)
+	if err != nil {
+		return filter, err
+	}
+	filter.Category, filter.Tag = category, tag
+
+	return filter, nil
+}
+
+// validateCategoryFilter validates the category and tag filters of /items and /flips
+func validateCategoryFilter(query url.Values) (domain.Category, string, error) {
+	category := domain.Category(query.Get("category"))
+	if category != "" && !category.Valid() {
+		names := make([]string, len(domain.Categories))
+		for i, c := range domain.Categories {
+			names[i] = string(c)
+		}
+		return "", "", fmt.Errorf("invalid category (expected one of %s)", strings.Join(names, ", "))
+	}
+
+	tag := strings.TrimSpace(query.Get("tag"))
+	if len(tag) > maxTagLength {
+		return "", "", fmt.Errorf("tag too long (max %d characters)", maxTagLength)
+	}
+	if strings.ContainsAny(tag, "<>\"'&") {
+		return "", "", fmt.Errorf("tag contains invalid characters")
+	}
+	return category, tag, nil
+}
+
+// validateAPIKeyFields validates the name and limits of an API key request.
+// Zero limits select the configured defaults.
+func validateAPIKeyFields(name *string, rateLimit, dailyQuota *int) error {
+	if name != nil {
+		*name = strings.TrimSpace(*name)
+		if *name == "" {
+			return fmt.Errorf("name is required")
+		}
+		if len(*name) > maxAPIKeyName {
+			return fmt.Errorf("name too long (max %d characters)", maxAPIKeyName)
+		}
+	}
+	if rateLimit != nil && (*rateLimit < 0 || *rateLimit > math.MaxInt32) {
+		return fmt.Errorf("rate_limit must be between 0 and %d", math.MaxInt32)
+	}
+	if dailyQuota != nil && (*dailyQuota < 0 || *dailyQuota > math.MaxInt32) {
+		return fmt.Errorf("daily_quota must be between 0 and %d", math.MaxInt32)
+	}
+	return nil
+}
+
+// validateEmail checks that email is a single bare address
+func validateEmail(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return fmt.Errorf("email is required")
+	}
+	if len(email) > maxEmailLength {
+		return fmt.Errorf("email too long (max %d characters)", maxEmailLength)
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("invalid email address")
+	}
+	return nil
+}
+
+// validatePassword checks the length of a new password
+func validatePassword(password string) error {
+	if len(password) < minPassword || len(password) > maxPassword {
+		return fmt.Errorf("password must be between %d and %d bytes", minPassword, maxPassword)
+	}
+	return nil
+}
+
+// validateWatchlistName trims and validates the name of a watchlist
+func validateWatchlistName(name *string) error {
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(*name) > maxWatchlistName {
+		return fmt.Errorf("name too long (max %d characters)", maxWatchlistName)
+	}
+	return nil
+}
+
+// validateWatchlistItemIDs validates the item IDs of a watchlist request
+func validateWatchlistItemIDs(ids []int) error {
+	if len(ids) > domain.MaxWatchlistItems {
+		return fmt.Errorf("too many item IDs (max %d)", domain.MaxWatchlistItems)
+	}
+	for _, id := range ids {
+		if id < minItemID || id > maxItemID {
+			return fmt.Errorf("item ID %d out of valid range", id)
+		}
+	}
+	return nil
+}
+
+// validateTrade validates a trade request. A missing traded_at means now.
+func validateTrade(req tradeRequest, now time.Time) (domain.Trade, error) {
+	trade := domain.Trade{
+		ItemID:   req.ItemID,
+		Side:     domain.TradeSide(req.Side),
+		Quantity: req.Quantity,
+		Price:    req.Price,
+		TradedAt: now,
+	}
+	if trade.ItemID < minItemID || trade.ItemID > maxItemID {
+		return trade, fmt.Errorf("item_id out of valid range")
+	}
+	if trade.Side != domain.TradeBuy && trade.Side != domain.TradeSell {
+		return trade, fmt.Errorf("side must be buy or sell")
+	}
+	if trade.Quantity < 1 || trade.Quantity > math.MaxInt32 {
+		return trade, fmt.Errorf("quantity must be between 1 and %d", math.MaxInt32)
+	}
+	if trade.Price < 1 || trade.Price > math.MaxInt32 {
+		return trade, fmt.Errorf("price must be between 1 and %d", math.MaxInt32)
+	}
+	if req.TradedAt != nil {
+		if req.TradedAt.After(now.Add(maxTradeClockSkew)) {
+			return trade, fmt.Errorf("traded_at is in the future")
+		}
+		trade.TradedAt = *req.TradedAt
+	}
+	return trade, nil
+}
+
+// validateTimeQuery parses an optional RFC 3339 time or YYYY-MM-DD date (UTC midnight)
+func validateTimeQuery(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be an RFC 3339 time or a YYYY-MM-DD date", name)
+}
+
+// validateTradeFilter parses the item_id, from and to query parameters
+func validateTradeFilter(query url.Values) (domain.TradeFilter, error) {
+	var filter domain.TradeFilter
+	if idStr := query.Get("item_id"); idStr != "" {
+		id, err := validateItemID(idStr)
+		if err != nil {
+			return filter, err
+		}
+		filter.ItemID = id
+	}
+
+	var err error
+	if filter.From, err = validateTimeQuery("from", query.Get("from")); err != nil {
+		return filter, err
+	}
+	if filter.To, err = validateTimeQuery("to", query.Get("to")); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	return filter, nil
+}
+
+// validateReportPeriod parses the period of a trade report; empty means daily
+func validateReportPeriod(value string) (domain.ReportPeriod, error) {
+	switch period := domain.ReportPeriod(value); period {
+	case "":
+		return domain.ReportDaily, nil
+	case domain.ReportDaily, domain.ReportWeekly, domain.ReportMonthly:
+		return period, nil
+	default:
+		return "", fmt.Errorf("period must be day, week or month")
+	}
+}
+
+// production hides internal error details from responses; set at startup
+var production bool
+
+// SetProduction enables production mode for all handlers
+func SetProduction(enabled bool) {
+	production = enabled
+}
+
 // isProduction checks if the application is running in production mode
 func isProduction() bool {
-	env := os.Getenv("ENV")
-	return env == "production" || env == "prod"
+	return production
 }
 
+// validateHoldings checks the items and quantities of a snapshot
+func validateHoldings(holdings []domain.Holding) error {
+	if len(holdings) == 0 {
+		return fmt.Errorf("snapshot has no items")
+	}
+	if len(holdings) > maxPortfolioItems {
+		return fmt.Errorf("too many items (max %d)", maxPortfolioItems)
+	}
+	for i, h := range holdings {
+		if h.ItemID < minItemID || h.ItemID > maxItemID {
+			return fmt.Errorf("items[%d]: item ID out of valid range", i)
+		}
+		if h.Quantity < 0 || h.Quantity > math.MaxInt32 {
+			return fmt.Errorf("items[%d]: qty out of valid range", i)
+		}
+	}
+	return nil
+}
+
+// validateAllocationRequest validates the cash, slots, risk and horizon of GET /flips/optimize
+func validateAllocationRequest(query url.Values) (domain.AllocationRequest, error) {
+	req := domain.AllocationRequest{Slots: domain.GESlots, Risk: domain.RiskMedium, Horizon: defaultHorizon}
+
+	v := query.Get("cash")
+	if v == "" {
+		return req, fmt.Errorf("cash is required")
+	}
+	cash, err := strconv.Atoi(v)
+	if err != nil || cash < 1 || cash > maxAllocationCash {
+		return req, fmt.Errorf("cash must be between 1 and %d", maxAllocationCash)
+	}
+	req.Cash = cash
+
+	if v := query.Get("slots"); v != "" {
+		slots, err := strconv.Atoi(v)
+		if err != nil || slots < 1 || slots > domain.GESlots {
+			return req, fmt.Errorf("slots must be between 1 and %d", domain.GESlots)
+		}
+		req.Slots = slots
+	}
+
+	if v := query.Get("risk"); v != "" {
+		switch domain.RiskTolerance(v) {
+		case domain.RiskLow, domain.RiskMedium, domain.RiskHigh:
+			req.Risk = domain.RiskTolerance(v)
+		default:
+			return req, fmt.Errorf("invalid risk (expected low, medium or high)")
+		}
+	}
+
+	if v := query.Get("horizon"); v != "" {
+		horizon, err := time.ParseDuration(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid horizon format (e.g. 4h)")
+		}
+		if horizon < minHorizon || horizon > maxHorizon {
+			return req, fmt.Errorf("horizon must be between %s and %s", minHorizon, maxHorizon)
+		}
+		req.Horizon = horizon
+	}
+
+	category, tag, err := validateCategoryFilter(query)
+	if err != nil {
+		return req, err
+	}
+	req.Category, req.Tag = category, tag
+
+	return req, nil
+}
+
+// validateBacktestRequest checks a backtest request and fills its defaults
+func validateBacktestRequest(req *application.BacktestRequest) error {
+	switch req.Strategy.Name {
+	case "":
+		return fmt.Errorf("strategy.name is required")
+	case backtest.StrategyMargin, backtest.StrategyMeanReversion:
+	default:
+		return fmt.Errorf("invalid strategy.name (expected %s or %s)", backtest.StrategyMargin, backtest.StrategyMeanReversion)
+	}
+	if req.Strategy.Lookback != 0 && (req.Strategy.Lookback < 2 || req.Strategy.Lookback > maxLookback) {
+		return fmt.Errorf("strategy.lookback must be between 2 and %d", maxLookback)
+	}
+	if req.Strategy.MinMargin < 0 || req.Strategy.MinMargin > maxMinMargin {
+		return fmt.Errorf("strategy.min_margin must be between 0 and %d", maxMinMargin)
+	}
+	if req.Strategy.Threshold < 0 || req.Strategy.Threshold >= 100 {
+		return fmt.Errorf("strategy.threshold must be between 0 and 100")
+	}
+	if req.Strategy.MaxShare < 0 || req.Strategy.MaxShare > 100 {
+		return fmt.Errorf("strategy.max_share must be between 0 and 100")
+	}
+
+	if len(req.ItemIDs) > application.MaxBacktestItems {
+		return fmt.Errorf("too many item_ids (max %d)", application.MaxBacktestItems)
+	}
+	for _, id := range req.ItemIDs {
+		if id < minItemID || id > maxItemID {
+			return fmt.Errorf("item ID out of valid range")
+		}
+	}
+	if req.MinVolume < 0 {
+		return fmt.Errorf("min_volume must not be negative")
+	}
+
+	if req.Days == 0 {
+		req.Days = defaultReplayDays
+	}
+	if req.Days < 1 || req.Days > maxBacktestDays {
+		return fmt.Errorf("days must be between 1 and %d", maxBacktestDays)
+	}
+	if req.Cash < 1 || req.Cash > maxAllocationCash {
+		return fmt.Errorf("cash must be between 1 and %d", maxAllocationCash)
+	}
+	if req.Slots == 0 {
+		req.Slots = domain.GESlots
+	}
+	if req.Slots < 1 || req.Slots > domain.GESlots {
+		return fmt.Errorf("slots must be between 1 and %d", domain.GESlots)
+	}
+	if req.FillRatio == 0 {
+		req.FillRatio = 1
+	}
+	if req.FillRatio < 0 || req.FillRatio > 1 {
+		return fmt.Errorf("fill_ratio must be between 0 and 1")
+	}
+	if req.OrderTTL == 0 {
+		req.OrderTTL = 1
+	}
+	if req.OrderTTL < 1 || req.OrderTTL > maxBacktestTTL {
+		return fmt.Errorf("order_ttl must be between 1 and %d", maxBacktestTTL)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// WatchlistsHandler handles the watchlists of the authenticated user.
+// Its routes must be behind AuthHandler.RequireUser.
+type WatchlistsHandler struct {
+	createWatchlistUseCase     *application.CreateWatchlistUseCase
+	listWatchlistsUseCase      *application.ListWatchlistsUseCase
+	getWatchlistUseCase        *application.GetWatchlistUseCase
+	renameWatchlistUseCase     *application.RenameWatchlistUseCase
+	deleteWatchlistUseCase     *application.DeleteWatchlistUseCase
+	addWatchlistItemsUseCase   *application.AddWatchlistItemsUseCase
+	removeWatchlistItemUseCase *application.RemoveWatchlistItemUseCase
+	reorderWatchlistUseCase    *application.ReorderWatchlistUseCase
+	getWatchlistItemsUseCase   *application.GetWatchlistItemsUseCase
+}
+
+// NewWatchlistsHandler creates a new WatchlistsHandler
+func NewWatchlistsHandler(
+	createWatchlistUseCase *application.CreateWatchlistUseCase,
+	listWatchlistsUseCase *application.ListWatchlistsUseCase,
+	getWatchlistUseCase *application.GetWatchlistUseCase,
+	renameWatchlistUseCase *application.RenameWatchlistUseCase,
+	deleteWatchlistUseCase *application.DeleteWatchlistUseCase,
+	addWatchlistItemsUseCase *application.AddWatchlistItemsUseCase,
+	removeWatchlistItemUseCase *application.RemoveWatchlistItemUseCase,
+	reorderWatchlistUseCase *application.ReorderWatchlistUseCase,
+	getWatchlistItemsUseCase *application.GetWatchlistItemsUseCase,
+) *WatchlistsHandler {
+	return &WatchlistsHandler{
+		createWatchlistUseCase:     createWatchlistUseCase,
+		listWatchlistsUseCase:      listWatchlistsUseCase,
+		getWatchlistUseCase:        getWatchlistUseCase,
+		renameWatchlistUseCase:     renameWatchlistUseCase,
+		deleteWatchlistUseCase:     deleteWatchlistUseCase,
+		addWatchlistItemsUseCase:   addWatchlistItemsUseCase,
+		removeWatchlistItemUseCase: removeWatchlistItemUseCase,
+		reorderWatchlistUseCase:    reorderWatchlistUseCase,
+		getWatchlistItemsUseCase:   getWatchlistItemsUseCase,
+	}
+}
+
+// watchlistRequest is the body of watchlist requests; each route reads the fields it needs
+type watchlistRequest struct {
+	Name    *string `json:"name"`
+	ItemIDs []int   `json:"item_ids"`
+}
+
+// decode reads and validates the request body
+func (req *watchlistRequest) decode(w http.ResponseWriter, r *http.Request) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxWatchlistBody)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return errors.New("invalid request body")
+	}
+	if req.Name != nil {
+		if err := validateWatchlistName(req.Name); err != nil {
+			return err
+		}
+	}
+	return validateWatchlistItemIDs(req.ItemIDs)
+}
+
+// CreateWatchlist handles POST /watchlists with {"name": "...", "item_ids": [...]}
+func (h *WatchlistsHandler) CreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.CreateWatchlist")
+	defer span.End()
+
+	var req watchlistRequest
+	if err := req.decode(w, r); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Name == nil {
+		respondWithError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlist, err := h.createWatchlistUseCase.Execute(ctx, userID, *req.Name, req.ItemIDs)
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, watchlist)
+}
+
+// ListWatchlists handles GET /watchlists
+func (h *WatchlistsHandler) ListWatchlists(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.ListWatchlists")
+	defer span.End()
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlists, err := h.listWatchlistsUseCase.Execute(ctx, userID)
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, watchlists)
+}
+
+// GetWatchlist handles GET /watchlists/{id}
+func (h *WatchlistsHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.GetWatchlist")
+	defer span.End()
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlist, err := h.getWatchlistUseCase.Execute(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, watchlist)
+}
+
+// RenameWatchlist handles PATCH /watchlists/{id} with {"name": "..."}
+func (h *WatchlistsHandler) RenameWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.RenameWatchlist")
+	defer span.End()
+
+	var req watchlistRequest
+	if err := req.decode(w, r); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Name == nil {
+		respondWithError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlist, err := h.renameWatchlistUseCase.Execute(ctx, userID, chi.URLParam(r, "id"), *req.Name)
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, watchlist)
+}
+
+// DeleteWatchlist handles DELETE /watchlists/{id}
+func (h *WatchlistsHandler) DeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.DeleteWatchlist")
+	defer span.End()
+
+	userID, _ := UserIDFromContext(ctx)
+	if err := h.deleteWatchlistUseCase.Execute(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddWatchlistItems handles POST /watchlists/{id}/items with {"item_ids": [...]}
+func (h *WatchlistsHandler) AddWatchlistItems(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.AddWatchlistItems")
+	defer span.End()
+
+	var req watchlistRequest
+	if err := req.decode(w, r); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.ItemIDs) == 0 {
+		respondWithError(w, http.StatusBadRequest, "item_ids is required")
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlist, err := h.addWatchlistItemsUseCase.Execute(ctx, userID, chi.URLParam(r, "id"), req.ItemIDs)
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, watchlist)
+}
+
+// RemoveWatchlistItem handles DELETE /watchlists/{id}/items/{itemId}
+func (h *WatchlistsHandler) RemoveWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.RemoveWatchlistItem")
+	defer span.End()
+
+	itemID, err := validateItemID(chi.URLParam(r, "itemId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlist, err := h.removeWatchlistItemUseCase.Execute(ctx, userID, chi.URLParam(r, "id"), itemID)
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, watchlist)
+}
+
+// ReorderWatchlist handles PUT /watchlists/{id}/order with every item ID in the new order
+func (h *WatchlistsHandler) ReorderWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.ReorderWatchlist")
+	defer span.End()
+
+	var req watchlistRequest
+	if err := req.decode(w, r); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.ItemIDs == nil {
+		respondWithError(w, http.StatusBadRequest, "item_ids is required")
+		return
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	watchlist, err := h.reorderWatchlistUseCase.Execute(ctx, userID, chi.URLParam(r, "id"), req.ItemIDs)
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, watchlist)
+}
+
+// GetWatchlistItems handles GET /watchlists/{id}/items, returning the
+// current prices and margins of every item in the watchlist's order
+func (h *WatchlistsHandler) GetWatchlistItems(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "WatchlistsHandler.GetWatchlistItems")
+	defer span.End()
+
+	userID, _ := UserIDFromContext(ctx)
+	items, err := h.getWatchlistItemsUseCase.Execute(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithWatchlistError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, items)
+}
+
+// respondWithWatchlistError maps watchlist errors to status codes
+func respondWithWatchlistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWatchlistNotFound):
+		respondWithError(w, http.StatusNotFound, "watchlist not found")
+	case errors.Is(err, domain.ErrWatchlistItemNotFound):
+		respondWithError(w, http.StatusNotFound, "item not in watchlist")
+	case errors.Is(err, domain.ErrInvalidWatchlistOrder):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrWatchlistFull):
+		respondWithError(w, http.StatusConflict, "watchlist is full")
+	case errors.Is(err, domain.ErrTooManyWatchlists):
+		respondWithError(w, http.StatusConflict, "too many watchlists")
+	default:
+		respondWithError(w, http.StatusInternalServerError, getSafeErrorMessage(err))
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/cache"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// CachedResponse is a successful response body stored by the response cache
+type CachedResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// ResponseCache stores rendered responses keyed by request path and query
+type ResponseCache = cache.MemoryCache[string, CachedResponse]
+
+// responseCacheMiddleware serves GET responses from the cache when present
+// and stores successful responses otherwise. The cache is also cleared after
+// each price update to release stale entries.
+func responseCacheMiddleware(responseCache *ResponseCache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Encode sorts the query so equivalent URLs share an entry. The ETag set by
+			// the conditional middleware ties entries to the data version, so writes
+			// outside price updates (e.g. history imports) also invalidate them.
+			key := w.Header().Get("ETag") + " " + r.URL.Path + "?" + r.URL.Query().Encode()
+
+			if cached, ok := responseCache.Get(key); ok {
+				w.Header().Set("Content-Type", cached.ContentType)
+				w.Header().Set("X-Cache", "HIT")
+				w.WriteHeader(cached.StatusCode)
+				w.Write(cached.Body)
+				return
+			}
+
+			w.Header().Set("X-Cache", "MISS")
+
+			var body bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&body)
+
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() == http.StatusOK {
+				responseCache.Set(key, CachedResponse{
+					StatusCode:  http.StatusOK,
+					ContentType: ww.Header().Get("Content-Type"),
+					Body:        body.Bytes(),
+				})
+			}
+		})
+	}
+}
@@ -2,35 +2,68 @@ package http
 
 import (
 	"net/http"
-	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gabv/osrs-good-to-flip/backend/internal/application"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/config"
+	"github.com/gabv/osrs-good-to-flip/backend/internal/infrastructure/metrics"
 	"github.com/gabv/osrs-good-to-flip/backend/internal/interfaces/http/handlers"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httprate"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SetupRoutes configures all HTTP routes
 func SetupRoutes(
+	reloader *config.Reloader,
 	itemsHandler *handlers.ItemsHandler,
 	healthHandler *handlers.HealthHandler,
+	conditionalHandler *handlers.ConditionalHandler,
+	exportHandler *handlers.ExportHandler,
+	importHandler *handlers.ImportHandler,
+	flipsHandler *handlers.FlipsHandler,
+	configHandler *handlers.ConfigHandler,
+	apiKeysHandler *handlers.APIKeysHandler,
+	authHandler *handlers.AuthHandler,
+	watchlistsHandler *handlers.WatchlistsHandler,
+	tradesHandler *handlers.TradesHandler,
+	limitsHandler *handlers.LimitsHandler,
+	portfolioHandler *handlers.PortfolioHandler,
+	backtestHandler *handlers.BacktestHandler,
+	anomaliesHandler *handlers.AnomaliesHandler,
+	categoriesHandler *handlers.CategoriesHandler,
+	authenticateAPIKeyUseCase *application.AuthenticateAPIKeyUseCase,
+	responseCache *ResponseCache,
 ) http.Handler {
+	// Settings read here only change on restart; the rate limit and CORS
+	// origins are read per request so reloads take effect immediately
+	cfg := reloader.Current()
 	r := chi.NewRouter()
 
 	// Middleware
+	r.Use(tracingMiddleware)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(requestLoggerMiddleware)
 	r.Use(middleware.Recoverer)
 
+	// Request metrics middleware
+	r.Use(metricsMiddleware)
+
+	// Response compression (br, gzip)
+	r.Use(compressionMiddleware())
+
 	// Security headers middleware
 	r.Use(securityHeadersMiddleware)
 
-	// Rate limiting middleware
-	r.Use(rateLimitMiddleware())
+	// API key authentication, then rate limiting per key or per IP
+	r.Use(apiKeyMiddleware(authenticateAPIKeyUseCase, reloader))
+	r.Use(rateLimitMiddleware(reloader))
 
 	// CORS middleware for frontend
 	allowedOrigins := []string{
@@ -39,31 +72,14 @@ func SetupRoutes(
 		"https://osrs-good-to-flip.vercel.app", // Production Vercel URL
 	}
 
-	// Add custom allowed origins from environment variable
-	if customOrigins := os.Getenv("ALLOWED_ORIGINS"); customOrigins != "" {
-		// Support comma-separated list
-		origins := strings.Split(customOrigins, ",")
-		for _, origin := range origins {
-			origin = strings.TrimSpace(origin)
-			if origin != "" {
-				allowedOrigins = append(allowedOrigins, origin)
-			}
-		}
-	}
-
 	// Custom CORS handler that allows *.vercel.app domains
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
-			// Check if origin is in allowed list
-			isAllowed := false
-			for _, allowed := range allowedOrigins {
-				if origin == allowed {
-					isAllowed = true
-					break
-				}
-			}
+			// Check if origin is in allowed list or in the configured origins
+			isAllowed := slices.Contains(allowedOrigins, origin) ||
+				slices.Contains(reloader.Current().CORS.AllowedOrigins, origin)
 
 			// Also allow any *.vercel.app domain (preview deployments)
 			if !isAllowed && origin != "" && strings.HasSuffix(origin, ".vercel.app") {
@@ -72,9 +88,9 @@ func SetupRoutes(
 
 			if isAllowed {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
-				w.Header().Set("Access-Control-Expose-Headers", "Link")
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-API-Key, X-CSRF-Token")
+				w.Header().Set("Access-Control-Expose-Headers", "Link, ETag, X-Quota-Limit, X-Quota-Remaining")
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 				w.Header().Set("Access-Control-Max-Age", "300")
 			}
@@ -89,16 +105,103 @@ func SetupRoutes(
 	})
 
 	// Routes
-	r.Get("/health", healthHandler.Check)
+	r.Handle("/metrics", metrics.Handler())
+	r.Route("/health", func(r chi.Router) {
+		r.Get("/", healthHandler.Live)
+		r.Get("/live", healthHandler.Live)
+		r.Get("/ready", healthHandler.Ready)
+	})
 	r.Route("/items", func(r chi.Router) {
-		r.Get("/", itemsHandler.GetItems)
+		r.Use(conditionalHandler.Middleware)
+		cached := r.With(responseCacheMiddleware(responseCache))
+
+		cached.Get("/", itemsHandler.GetItems)
+		r.Get("/all", itemsHandler.ExportAllItems)
+		r.Get("/batch", itemsHandler.GetItemsBatch)
+		r.Post("/batch", itemsHandler.GetItemsBatch)
 		r.Get("/{id}", itemsHandler.GetItemByID)
-		r.Get("/{id}/history", itemsHandler.GetPriceHistory)
+		cached.Get("/{id}/history", itemsHandler.GetPriceHistory)
+		cached.Get("/{id}/forecast", itemsHandler.GetForecast)
+	})
+	// A signed-in user's flips depend on their buy limits, so only
+	// anonymous rankings and allocations are conditional and cached
+	flips := r.With(authHandler.OptionalUser, anonymousOnly(conditionalHandler.Middleware, responseCacheMiddleware(responseCache)))
+	flips.Get("/flips", flipsHandler.GetFlips)
+	flips.Get("/flips/optimize", flipsHandler.OptimizeAllocation)
+	r.With(conditionalHandler.Middleware, responseCacheMiddleware(responseCache)).Get("/anomalies", anomaliesHandler.GetAnomalies)
+	r.With(conditionalHandler.Middleware, responseCacheMiddleware(responseCache)).Get("/categories", categoriesHandler.GetCategories)
+	r.Post("/portfolio/value", portfolioHandler.ValuePortfolio)
+	r.Post("/backtest", backtestHandler.RunBacktest)
+	r.Route("/export", func(r chi.Router) {
+		r.Get("/items", exportHandler.ExportItems)
+		r.Get("/history", exportHandler.ExportPriceHistory)
 	})
 
+	// User accounts; routes behind authHandler.RequireUser need an access token
+	r.Route("/auth", func(r chi.Router) {
+		r.Post("/register", authHandler.Register)
+		r.Post("/login", authHandler.Login)
+		r.Post("/refresh", authHandler.Refresh)
+		r.Post("/logout", authHandler.Logout)
+	})
+	r.With(authHandler.RequireUser).Get("/me", authHandler.Me)
+	r.Route("/watchlists", func(r chi.Router) {
+		r.Use(authHandler.RequireUser)
+		r.Get("/", watchlistsHandler.ListWatchlists)
+		r.Post("/", watchlistsHandler.CreateWatchlist)
+		r.Get("/{id}", watchlistsHandler.GetWatchlist)
+		r.Patch("/{id}", watchlistsHandler.RenameWatchlist)
+		r.Delete("/{id}", watchlistsHandler.DeleteWatchlist)
+		r.Get("/{id}/items", watchlistsHandler.GetWatchlistItems)
+		r.Post("/{id}/items", watchlistsHandler.AddWatchlistItems)
+		r.Delete("/{id}/items/{itemId}", watchlistsHandler.RemoveWatchlistItem)
+		r.Put("/{id}/order", watchlistsHandler.ReorderWatchlist)
+	})
+	r.Route("/trades", func(r chi.Router) {
+		r.Use(authHandler.RequireUser)
+		r.Get("/", tradesHandler.ListTrades)
+		r.Post("/", tradesHandler.LogTrade)
+		r.Post("/batch", tradesHandler.LogTrades)
+		r.Get("/report", tradesHandler.GetTradeReport)
+		r.Delete("/{id}", tradesHandler.DeleteTrade)
+	})
+	r.With(authHandler.RequireUser).Get("/limits", limitsHandler.GetLimits)
+
+	// Admin routes are only enabled when ADMIN_TOKEN is set
+	if cfg.Admin.Token != "" {
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(adminAuthMiddleware(cfg.Admin.Token))
+			r.Get("/config", configHandler.GetConfig)
+			r.Post("/config/reload", configHandler.ReloadConfig)
+			r.Route("/keys", func(r chi.Router) {
+				r.Get("/", apiKeysHandler.ListAPIKeys)
+				r.Post("/", apiKeysHandler.CreateAPIKey)
+				r.Get("/{id}", apiKeysHandler.GetAPIKey)
+				r.Patch("/{id}", apiKeysHandler.UpdateAPIKey)
+				r.Delete("/{id}", apiKeysHandler.RevokeAPIKey)
+			})
+			r.Post("/import/history", importHandler.ImportPriceHistory)
+		})
+	}
+
 	return r
 }
 
+// anonymousOnly applies middlewares only to requests without an
+// Authorization header; authenticated requests skip them
+func anonymousOnly(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		anonymous := chi.Chain(middlewares...).Handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			anonymous.ServeHTTP(w, r)
+		})
+	}
+}
+
 // securityHeadersMiddleware adds security headers to all responses
 func securityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -127,19 +230,66 @@ func securityHeadersMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// rateLimitMiddleware configures rate limiting based on environment variables
-func rateLimitMiddleware() func(http.Handler) http.Handler {
-	// Default: 100 requests per minute per IP
-	requestsPerMinute := 100
-	if envRate := os.Getenv("RATE_LIMIT_REQUESTS_PER_MINUTE"); envRate != "" {
-		if rate, err := strconv.Atoi(envRate); err == nil && rate > 0 {
-			requestsPerMinute = rate
-		}
+// rateLimitMiddleware limits each API key, or each IP for anonymous requests,
+// per endpoint. Limits are read on every request so they can change without
+// a restart.
+func rateLimitMiddleware(reloader *config.Reloader) func(http.Handler) http.Handler {
+	limiter := httprate.Limit(
+		reloader.Current().RateLimit.RequestsPerMinute,
+		1*time.Minute,
+		httprate.WithKeyFuncs(rateLimitKey, httprate.KeyByEndpoint),
+		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			metrics.IncRateLimitRejection()
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		}),
+	)
+	return func(next http.Handler) http.Handler {
+		limited := limiter(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := httprate.WithRequestLimit(r.Context(), requestLimit(r, reloader.Current()))
+			limited.ServeHTTP(w, r.WithContext(ctx))
+		})
 	}
+}
 
-	return httprate.Limit(
-		requestsPerMinute,
-		1*time.Minute,
-		httprate.WithKeyFuncs(httprate.KeyByIP, httprate.KeyByEndpoint),
+// metricsMiddleware records request count and latency by route pattern and status
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		// Use the route pattern instead of the raw path to keep label cardinality bounded
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		metrics.ObserveHTTPRequest(route, r.Method, strconv.Itoa(status), time.Since(start))
+	})
+}
+
+// tracingMiddleware starts a server span per request, continuing any W3C trace
+// context sent by the caller, and names the span after the matched route
+func tracingMiddleware(next http.Handler) http.Handler {
+	rename := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			trace.SpanFromContext(r.Context()).SetName(r.Method + " " + rctx.RoutePattern())
+		}
+	})
+
+	return otelhttp.NewHandler(rename, "http.request",
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			// Skip probes and scrapes to keep traces focused on API traffic
+			return r.URL.Path != "/metrics" && !strings.HasPrefix(r.URL.Path, "/health")
+		}),
 	)
 }
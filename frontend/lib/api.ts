@@ -91,3 +91,18 @@ export async function getPriceHistory(
   return fetchAPI<PriceHistoryEntry[]>(endpoint);
 }
 
+
+export interface BatchItemResult {
+  found: boolean;
+  item?: ItemPrice;
+}
+
+export interface BatchItemsResponse {
+  results: Record<string, BatchItemResult>;
+}
+
+export async function getItemsBatch(
+  ids: number[]
+): Promise<BatchItemsResponse> {
+  return fetchAPI<BatchItemsResponse>(`/items/batch?ids=${ids.join(",")}`);
+}